@@ -28,3 +28,14 @@ type ObjectMetadata struct {
 	// GenerateName is used to auto-generate a unique object name with the specified prefix.
 	GenerateName string `json:"generateName,omitempty"`
 }
+
+// HistoryLimits bounds how many finished executions a parent resource keeps. Unset limits fall
+// back to the operator settings.
+type HistoryLimits struct {
+	// +kubebuilder:validation:Minimum=0
+	// Successful is the number of succeeded executions to keep.
+	Successful *int32 `json:"successful,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// Failed is the number of failed, cancelled or timed out executions to keep.
+	Failed *int32 `json:"failed,omitempty"`
+}
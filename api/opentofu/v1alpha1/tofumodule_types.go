@@ -76,9 +76,39 @@ type DriftDetectionSpec struct {
 	Interval metav1.Duration `json:"interval,omitempty"` // Interval between drift checks (e.g., "30m").
 }
 
+// GitProvider names the service hosting a module repository.
+// +kubebuilder:validation:Enum=GitHub;GitLab;Gitea
+type GitProvider string
+
+const (
+	GitProviderGitHub GitProvider = "GitHub"
+	GitProviderGitLab GitProvider = "GitLab"
+	GitProviderGitea  GitProvider = "Gitea"
+)
+
+// PullRequestCommentSpec configures the comment plans of a module post on the pull request it
+// tracks.
+type PullRequestCommentSpec struct {
+	// Provider is the service hosting the repository the module is cloned from.
+	Provider GitProvider `json:"provider"`
+	// TokenSecretRef selects the key of a Secret in the module's namespace holding the API token
+	// comments are posted with.
+	TokenSecretRef KeyRef `json:"tokenSecretRef"`
+	// APIURL is the API endpoint of the provider. It defaults to https://api.github.com for
+	// github.com and otherwise to https://<host>/api/v3 for GitHub, https://<host>/api/v4 for
+	// GitLab and https://<host>/api/v1 for Gitea, <host> being the host of the module source.
+	// +optional
+	APIURL string `json:"apiURL,omitempty"`
+}
+
+// DefaultWorkdir is the module directory, relative to the repository root, used when Workdir is unset.
+const DefaultWorkdir = "."
+
 // TofuModuleSpec defines the desired state of a TofuModule resource.
 type TofuModuleSpec struct {
-	Source            string                   `json:"source"`
+	Source string `json:"source"`
+	// Version is the branch, tag, commit or ref checked out after cloning the source, e.g.
+	// main or refs/pull/42/head. The default branch is used when empty.
 	Version           string                   `json:"version,omitempty"`
 	Workdir           string                   `json:"workdir,omitempty"`
 	Backend           BackendSpec              `json:"backend,omitempty"`
@@ -89,6 +119,12 @@ type TofuModuleSpec struct {
 	ExecutionTemplate ExecutionTemplateSpec    `json:"executionTemplate,omitempty"`
 	AutoApply         bool                     `json:"autoApply,omitempty"`      // If true, applies changes automatically when drift is detected.
 	DriftDetection    *DriftDetectionSpec      `json:"driftDetection,omitempty"` // Optional drift detection configuration.
+	// HistoryLimits bounds how many finished executions of this module are kept.
+	HistoryLimits *HistoryLimits `json:"historyLimits,omitempty"`
+	// PullRequestComment posts the results of the module's plans as a comment on the pull
+	// request of the branch or pull request ref in Version, and updates it on every plan.
+	// +optional
+	PullRequestComment *PullRequestCommentSpec `json:"pullRequestComment,omitempty"`
 }
 
 // TofuModuleStatus defines the observed state of a TofuModule.
@@ -104,6 +140,7 @@ type TofuModuleStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName=tfmod
 // +kubebuilder:printcolumn:name="Source",type=string,JSONPath=`.spec.source`
@@ -0,0 +1,93 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PolicyEnforcement decides what a policy violation does.
+// +kubebuilder:validation:Enum=Deny;Warn
+type PolicyEnforcement string
+
+const (
+	// PolicyDeny blocks applies violating the policy.
+	PolicyDeny PolicyEnforcement = "Deny"
+	// PolicyWarn only reports violations in the execution status.
+	PolicyWarn PolicyEnforcement = "Warn"
+)
+
+// TofuPolicySpec defines guardrails checked against the plans of the modules it selects.
+type TofuPolicySpec struct {
+	// ModuleSelector selects the TofuModules of the policy's namespace the policy applies to. An
+	// empty selector selects every module.
+	ModuleSelector *metav1.LabelSelector `json:"moduleSelector,omitempty"`
+	// Enforcement decides what a violation does: Deny fails the apply before any change is made,
+	// Warn only reports it.
+	// +kubebuilder:default=Deny
+	Enforcement PolicyEnforcement `json:"enforcement,omitempty"`
+	// Rules are checked against every plan of the selected modules.
+	// +kubebuilder:validation:MinItems=1
+	Rules []PolicyRule `json:"rules"`
+}
+
+// PolicyRule is a single check of a TofuPolicy.
+type PolicyRule struct {
+	// Name identifies the rule in execution results.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+$`
+	Name string `json:"name"`
+	// Expression is a CEL expression that must evaluate to true for a compliant plan. The plan's
+	// JSON representation, as printed by `tofu show -json`, is available as `plan`, e.g.
+	// `plan.resource_changes.all(rc, rc.type != "aws_db_instance" || !("delete" in rc.change.actions))`.
+	// +kubebuilder:validation:MinLength=1
+	Expression string `json:"expression"`
+	// Message explains a violation.
+	Message string `json:"message,omitempty"`
+}
+
+// TofuPolicyStatus holds observed state (currently unused).
+type TofuPolicyStatus struct {
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=tfpol
+// +kubebuilder:printcolumn:name="Enforcement",type=string,JSONPath=`.spec.enforcement`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// TofuPolicy is the Schema for the tofupolicies API.
+type TofuPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TofuPolicySpec   `json:"spec,omitempty"`
+	Status TofuPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TofuPolicyList contains a list of TofuPolicy.
+type TofuPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TofuPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TofuPolicy{}, &TofuPolicyList{})
+}
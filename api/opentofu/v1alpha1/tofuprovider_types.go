@@ -60,6 +60,7 @@ type TofuProviderStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName=tfprov
 // +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
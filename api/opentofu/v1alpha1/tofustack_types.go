@@ -17,15 +17,87 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// TofuStackInstance describes one instantiation of the stack's module, e.g. an environment or region.
+type TofuStackInstance struct {
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	// Name identifies the instance within the stack.
+	Name string `json:"name"`
+	// Workspace overrides the engine workspace used by the instance. Defaults to the instance name.
+	Workspace string `json:"workspace,omitempty"`
+	// Variables are overlaid on top of the module and execution template variables.
+	Variables map[string]apiextv1.JSON `json:"variables,omitempty"`
+	// ValueSources are overlaid on top of the module and execution template value sources.
+	ValueSources map[string]ValueFrom `json:"valueSources,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// Wave orders the instance when the rollout strategy is Waves; lower waves roll out first.
+	Wave int32 `json:"wave,omitempty"`
+}
+
+// TofuStackMatrixAxis is one dimension of a stack matrix. Each value is exposed to the
+// module as a variable named after the axis.
+type TofuStackMatrixAxis struct {
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_-]*$`
+	// Name is the variable the axis values are passed as.
+	Name string `json:"name"`
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:items:MaxLength=63
+	// Values are joined with dashes into the names of the generated instances, so they follow
+	// the rules of instance names.
+	Values []string `json:"values"`
+}
+
+// TofuStackRolloutSpec controls how changes are promoted across stack instances. A wave
+// promotes to the next one once all of its executions applied successfully.
+type TofuStackRolloutSpec struct {
+	// +kubebuilder:validation:Enum=Parallel;Sequential;Waves
+	// +kubebuilder:default=Parallel
+	// Strategy selects how instances are grouped into waves: Parallel runs every instance in a
+	// single wave, Sequential runs one instance per wave in declaration order, and Waves groups
+	// instances by their wave number.
+	Strategy string `json:"strategy,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// MaxParallel caps the number of in-flight executions within a wave. Zero means unlimited.
+	MaxParallel int32 `json:"maxParallel,omitempty"`
+	// StopOnFailure stops starting further instances of the current wave once one has failed.
+	// A wave with a failed instance never promotes to the next wave.
+	StopOnFailure bool `json:"stopOnFailure,omitempty"`
+}
+
 // TofuStackSpec defines the desired state of a TofuStack.
 type TofuStackSpec struct {
 	ModuleRef         ObjectRef             `json:"moduleTemplate"`           // Reference to a TofuModule.
 	ExecutionTemplate ExecutionTemplateSpec `json:"executionTemplate"`        // Template used to generate TofuExecutions.
 	AutoApply         bool                  `json:"autoApply,omitempty"`      // If true, applies changes automatically when drift is detected.
 	DriftDetection    *DriftDetectionSpec   `json:"driftDetection,omitempty"` // Optional drift detection configuration.
+	// Instances lists the explicit instances generated by the stack, one execution each.
+	Instances []TofuStackInstance `json:"instances,omitempty"`
+	// Matrix generates one instance per combination of axis values, in addition to Instances.
+	Matrix []TofuStackMatrixAxis `json:"matrix,omitempty"`
+	// Rollout controls the order in which instances pick up a new stack generation.
+	Rollout *TofuStackRolloutSpec `json:"rollout,omitempty"`
+	// HistoryLimits bounds how many finished executions of this stack are kept.
+	HistoryLimits *HistoryLimits `json:"historyLimits,omitempty"`
+}
+
+// TofuStackInstanceStatus reports the observed state of a single stack instance.
+type TofuStackInstanceStatus struct {
+	Name              string `json:"name"`
+	Wave              int32  `json:"wave"`
+	Phase             string `json:"phase,omitempty"` // Waiting until the rollout reaches the instance
+	LastExecutionName string `json:"lastExecution,omitempty"`
+}
+
+// TofuStackExecutionCounts tallies the latest execution of each stack instance by outcome.
+type TofuStackExecutionCounts struct {
+	Succeeded int32 `json:"succeeded"`
+	Failed    int32 `json:"failed"`
+	Pending   int32 `json:"pending"` // Includes running executions and instances waiting for their wave
 }
 
 // TofuStackStatus defines the observed state of a TofuStack.
@@ -38,12 +110,21 @@ type TofuStackStatus struct {
 	LastApply          *ExecutionSummary  `json:"lastApply,omitempty"`          // Info from most recent apply
 	Conditions         []metav1.Condition `json:"conditions,omitempty"`         // Standard K8s-style condition set
 	LastExecutionName  string             `json:"lastExecution,omitempty"`
+	// Instances reports per-instance phases when the stack fans out.
+	Instances []TofuStackInstanceStatus `json:"instances,omitempty"`
+	// CurrentWave is the wave currently being rolled out.
+	CurrentWave int32 `json:"currentWave,omitempty"`
+	// Executions counts the stack's current executions by outcome.
+	Executions TofuStackExecutionCounts `json:"executions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName=tfstack
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Wave",type=integer,JSONPath=`.status.currentWave`,priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="LastExecution",type=string,JSONPath=`.status.lastExecution`
 
@@ -113,6 +113,11 @@ func (in *ExecutionSummary) DeepCopyInto(out *ExecutionSummary) {
 		in, out := &in.FinishedAt, &out.FinishedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.HasChanges != nil {
+		in, out := &in.HasChanges, &out.HasChanges
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionSummary.
@@ -142,6 +147,31 @@ func (in *ExecutionTemplateSpec) DeepCopy() *ExecutionTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HistoryLimits) DeepCopyInto(out *HistoryLimits) {
+	*out = *in
+	if in.Successful != nil {
+		in, out := &in.Successful, &out.Successful
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Failed != nil {
+		in, out := &in.Failed, &out.Failed
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HistoryLimits.
+func (in *HistoryLimits) DeepCopy() *HistoryLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(HistoryLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JobTemplateSpec) DeepCopyInto(out *JobTemplateSpec) {
 	*out = *in
@@ -160,6 +190,69 @@ func (in *JobTemplateSpec) DeepCopyInto(out *JobTemplateSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PodSpecPatch != nil {
+		in, out := &in.PodSpecPatch, &out.PodSpecPatch
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobTemplateSpec.
@@ -187,6 +280,63 @@ func (in *KeyRef) DeepCopy() *KeyRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LockfileStatus) DeepCopyInto(out *LockfileStatus) {
+	*out = *in
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make([]ProviderLock, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockfileStatus.
+func (in *LockfileStatus) DeepCopy() *LockfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LockfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacedKeyRef) DeepCopyInto(out *NamespacedKeyRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacedKeyRef.
+func (in *NamespacedKeyRef) DeepCopy() *NamespacedKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacedKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationSink) DeepCopyInto(out *NotificationSink) {
+	*out = *in
+	if in.URLSecretRef != nil {
+		in, out := &in.URLSecretRef, &out.URLSecretRef
+		*out = new(NamespacedKeyRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSink.
+func (in *NotificationSink) DeepCopy() *NotificationSink {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectMetadata) DeepCopyInto(out *ObjectMetadata) {
 	*out = *in
@@ -226,63 +376,571 @@ func (in *ObjectRef) DeepCopy() *ObjectRef {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectRef)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OutputSpec) DeepCopyInto(out *OutputSpec) {
-	*out = *in
-	if in.To != nil {
-		in, out := &in.To, &out.To
-		*out = make([]OutputTarget, len(*in))
-		copy(*out, *in)
+	out := new(ObjectRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputSpec) DeepCopyInto(out *OutputSpec) {
+	*out = *in
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]OutputTarget, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputSpec.
+func (in *OutputSpec) DeepCopy() *OutputSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputTarget) DeepCopyInto(out *OutputTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputTarget.
+func (in *OutputTarget) DeepCopy() *OutputTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyResult) DeepCopyInto(out *PolicyResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyResult.
+func (in *PolicyResult) DeepCopy() *PolicyResult {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyRule) DeepCopyInto(out *PolicyRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRule.
+func (in *PolicyRule) DeepCopy() *PolicyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderLock) DeepCopyInto(out *ProviderLock) {
+	*out = *in
+	if in.Hashes != nil {
+		in, out := &in.Hashes, &out.Hashes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderLock.
+func (in *ProviderLock) DeepCopy() *ProviderLock {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderLock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PullRequestCommentSpec) DeepCopyInto(out *PullRequestCommentSpec) {
+	*out = *in
+	out.TokenSecretRef = in.TokenSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullRequestCommentSpec.
+func (in *PullRequestCommentSpec) DeepCopy() *PullRequestCommentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PullRequestCommentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RetryOn != nil {
+		in, out := &in.RetryOn, &out.RetryOn
+		*out = make([]FailureReason, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyRef) DeepCopyInto(out *SecretKeyRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyRef.
+func (in *SecretKeyRef) DeepCopy() *SecretKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuExecution) DeepCopyInto(out *TofuExecution) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuExecution.
+func (in *TofuExecution) DeepCopy() *TofuExecution {
+	if in == nil {
+		return nil
+	}
+	out := new(TofuExecution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TofuExecution) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuExecutionList) DeepCopyInto(out *TofuExecutionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TofuExecution, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuExecutionList.
+func (in *TofuExecutionList) DeepCopy() *TofuExecutionList {
+	if in == nil {
+		return nil
+	}
+	out := new(TofuExecutionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TofuExecutionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuExecutionSpec) DeepCopyInto(out *TofuExecutionSpec) {
+	*out = *in
+	out.ModuleRef = in.ModuleRef
+	in.JobTemplate.DeepCopyInto(&out.JobTemplate)
+	out.Engine = in.Engine
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make(map[string]apiextensionsv1.JSON, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ValueSources != nil {
+		in, out := &in.ValueSources, &out.ValueSources
+		*out = make(map[string]ValueFrom, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuExecutionSpec.
+func (in *TofuExecutionSpec) DeepCopy() *TofuExecutionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TofuExecutionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuExecutionStatus) DeepCopyInto(out *TofuExecutionStatus) {
+	*out = *in
+	in.ExecutionSummary.DeepCopyInto(&out.ExecutionSummary)
+	if in.Lockfile != nil {
+		in, out := &in.Lockfile, &out.Lockfile
+		*out = new(LockfileStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]PolicyResult, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuExecutionStatus.
+func (in *TofuExecutionStatus) DeepCopy() *TofuExecutionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TofuExecutionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuModule) DeepCopyInto(out *TofuModule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuModule.
+func (in *TofuModule) DeepCopy() *TofuModule {
+	if in == nil {
+		return nil
+	}
+	out := new(TofuModule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TofuModule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuModuleGrant) DeepCopyInto(out *TofuModuleGrant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuModuleGrant.
+func (in *TofuModuleGrant) DeepCopy() *TofuModuleGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(TofuModuleGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TofuModuleGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuModuleGrantFrom) DeepCopyInto(out *TofuModuleGrantFrom) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuModuleGrantFrom.
+func (in *TofuModuleGrantFrom) DeepCopy() *TofuModuleGrantFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(TofuModuleGrantFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuModuleGrantList) DeepCopyInto(out *TofuModuleGrantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TofuModuleGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuModuleGrantList.
+func (in *TofuModuleGrantList) DeepCopy() *TofuModuleGrantList {
+	if in == nil {
+		return nil
+	}
+	out := new(TofuModuleGrantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TofuModuleGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuModuleGrantSpec) DeepCopyInto(out *TofuModuleGrantSpec) {
+	*out = *in
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = make([]TofuModuleGrantFrom, len(*in))
+		copy(*out, *in)
+	}
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]TofuModuleGrantTo, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuModuleGrantSpec.
+func (in *TofuModuleGrantSpec) DeepCopy() *TofuModuleGrantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TofuModuleGrantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuModuleGrantStatus) DeepCopyInto(out *TofuModuleGrantStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuModuleGrantStatus.
+func (in *TofuModuleGrantStatus) DeepCopy() *TofuModuleGrantStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TofuModuleGrantStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuModuleGrantTo) DeepCopyInto(out *TofuModuleGrantTo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuModuleGrantTo.
+func (in *TofuModuleGrantTo) DeepCopy() *TofuModuleGrantTo {
+	if in == nil {
+		return nil
+	}
+	out := new(TofuModuleGrantTo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuModuleList) DeepCopyInto(out *TofuModuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TofuModule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuModuleList.
+func (in *TofuModuleList) DeepCopy() *TofuModuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(TofuModuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TofuModuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuModuleSpec) DeepCopyInto(out *TofuModuleSpec) {
+	*out = *in
+	in.Backend.DeepCopyInto(&out.Backend)
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make([]TofuProviderRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make(map[string]apiextensionsv1.JSON, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ValueSources != nil {
+		in, out := &in.ValueSources, &out.ValueSources
+		*out = make(map[string]ValueFrom, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]OutputSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.ExecutionTemplate.DeepCopyInto(&out.ExecutionTemplate)
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetectionSpec)
+		**out = **in
+	}
+	if in.HistoryLimits != nil {
+		in, out := &in.HistoryLimits, &out.HistoryLimits
+		*out = new(HistoryLimits)
+		(*in).DeepCopyInto(*out)
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputSpec.
-func (in *OutputSpec) DeepCopy() *OutputSpec {
-	if in == nil {
-		return nil
+	if in.PullRequestComment != nil {
+		in, out := &in.PullRequestComment, &out.PullRequestComment
+		*out = new(PullRequestCommentSpec)
+		**out = **in
 	}
-	out := new(OutputSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OutputTarget) DeepCopyInto(out *OutputTarget) {
-	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputTarget.
-func (in *OutputTarget) DeepCopy() *OutputTarget {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuModuleSpec.
+func (in *TofuModuleSpec) DeepCopy() *TofuModuleSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OutputTarget)
+	out := new(TofuModuleSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretKeyRef) DeepCopyInto(out *SecretKeyRef) {
+func (in *TofuModuleStatus) DeepCopyInto(out *TofuModuleStatus) {
 	*out = *in
+	if in.LastPlan != nil {
+		in, out := &in.LastPlan, &out.LastPlan
+		*out = new(ExecutionSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastApply != nil {
+		in, out := &in.LastApply, &out.LastApply
+		*out = new(ExecutionSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyRef.
-func (in *SecretKeyRef) DeepCopy() *SecretKeyRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuModuleStatus.
+func (in *TofuModuleStatus) DeepCopy() *TofuModuleStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretKeyRef)
+	out := new(TofuModuleStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TofuExecution) DeepCopyInto(out *TofuExecution) {
+func (in *TofuNotification) DeepCopyInto(out *TofuNotification) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -290,18 +948,18 @@ func (in *TofuExecution) DeepCopyInto(out *TofuExecution) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuExecution.
-func (in *TofuExecution) DeepCopy() *TofuExecution {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuNotification.
+func (in *TofuNotification) DeepCopy() *TofuNotification {
 	if in == nil {
 		return nil
 	}
-	out := new(TofuExecution)
+	out := new(TofuNotification)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TofuExecution) DeepCopyObject() runtime.Object {
+func (in *TofuNotification) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -309,31 +967,31 @@ func (in *TofuExecution) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TofuExecutionList) DeepCopyInto(out *TofuExecutionList) {
+func (in *TofuNotificationList) DeepCopyInto(out *TofuNotificationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]TofuExecution, len(*in))
+		*out = make([]TofuNotification, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuExecutionList.
-func (in *TofuExecutionList) DeepCopy() *TofuExecutionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuNotificationList.
+func (in *TofuNotificationList) DeepCopy() *TofuNotificationList {
 	if in == nil {
 		return nil
 	}
-	out := new(TofuExecutionList)
+	out := new(TofuNotificationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TofuExecutionList) DeepCopyObject() runtime.Object {
+func (in *TofuNotificationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -341,67 +999,80 @@ func (in *TofuExecutionList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TofuExecutionSpec) DeepCopyInto(out *TofuExecutionSpec) {
+func (in *TofuNotificationSpec) DeepCopyInto(out *TofuNotificationSpec) {
 	*out = *in
-	out.ModuleRef = in.ModuleRef
-	in.JobTemplate.DeepCopyInto(&out.JobTemplate)
-	out.Engine = in.Engine
+	in.Sink.DeepCopyInto(&out.Sink)
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]NotificationEvent, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ModuleSelector != nil {
+		in, out := &in.ModuleSelector, &out.ModuleSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuExecutionSpec.
-func (in *TofuExecutionSpec) DeepCopy() *TofuExecutionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuNotificationSpec.
+func (in *TofuNotificationSpec) DeepCopy() *TofuNotificationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TofuExecutionSpec)
+	out := new(TofuNotificationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TofuExecutionStatus) DeepCopyInto(out *TofuExecutionStatus) {
+func (in *TofuNotificationStatus) DeepCopyInto(out *TofuNotificationStatus) {
 	*out = *in
-	in.ExecutionSummary.DeepCopyInto(&out.ExecutionSummary)
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.LastDeliveredAt != nil {
+		in, out := &in.LastDeliveredAt, &out.LastDeliveredAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailedAt != nil {
+		in, out := &in.LastFailedAt, &out.LastFailedAt
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuExecutionStatus.
-func (in *TofuExecutionStatus) DeepCopy() *TofuExecutionStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuNotificationStatus.
+func (in *TofuNotificationStatus) DeepCopy() *TofuNotificationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(TofuExecutionStatus)
+	out := new(TofuNotificationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TofuModule) DeepCopyInto(out *TofuModule) {
+func (in *TofuPolicy) DeepCopyInto(out *TofuPolicy) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuModule.
-func (in *TofuModule) DeepCopy() *TofuModule {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuPolicy.
+func (in *TofuPolicy) DeepCopy() *TofuPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(TofuModule)
+	out := new(TofuPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TofuModule) DeepCopyObject() runtime.Object {
+func (in *TofuPolicy) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -409,31 +1080,31 @@ func (in *TofuModule) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TofuModuleList) DeepCopyInto(out *TofuModuleList) {
+func (in *TofuPolicyList) DeepCopyInto(out *TofuPolicyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]TofuModule, len(*in))
+		*out = make([]TofuPolicy, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuModuleList.
-func (in *TofuModuleList) DeepCopy() *TofuModuleList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuPolicyList.
+func (in *TofuPolicyList) DeepCopy() *TofuPolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(TofuModuleList)
+	out := new(TofuPolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TofuModuleList) DeepCopyObject() runtime.Object {
+func (in *TofuPolicyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -441,81 +1112,41 @@ func (in *TofuModuleList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TofuModuleSpec) DeepCopyInto(out *TofuModuleSpec) {
+func (in *TofuPolicySpec) DeepCopyInto(out *TofuPolicySpec) {
 	*out = *in
-	in.Backend.DeepCopyInto(&out.Backend)
-	if in.Providers != nil {
-		in, out := &in.Providers, &out.Providers
-		*out = make([]TofuProviderRef, len(*in))
-		copy(*out, *in)
-	}
-	if in.Variables != nil {
-		in, out := &in.Variables, &out.Variables
-		*out = make(map[string]apiextensionsv1.JSON, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.ValueSources != nil {
-		in, out := &in.ValueSources, &out.ValueSources
-		*out = make(map[string]ValueFrom, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.Outputs != nil {
-		in, out := &in.Outputs, &out.Outputs
-		*out = make([]OutputSpec, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.ModuleSelector != nil {
+		in, out := &in.ModuleSelector, &out.ModuleSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
-	in.ExecutionTemplate.DeepCopyInto(&out.ExecutionTemplate)
-	if in.DriftDetection != nil {
-		in, out := &in.DriftDetection, &out.DriftDetection
-		*out = new(DriftDetectionSpec)
-		**out = **in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]PolicyRule, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuModuleSpec.
-func (in *TofuModuleSpec) DeepCopy() *TofuModuleSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuPolicySpec.
+func (in *TofuPolicySpec) DeepCopy() *TofuPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TofuModuleSpec)
+	out := new(TofuPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TofuModuleStatus) DeepCopyInto(out *TofuModuleStatus) {
+func (in *TofuPolicyStatus) DeepCopyInto(out *TofuPolicyStatus) {
 	*out = *in
-	if in.LastPlan != nil {
-		in, out := &in.LastPlan, &out.LastPlan
-		*out = new(ExecutionSummary)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.LastApply != nil {
-		in, out := &in.LastApply, &out.LastApply
-		*out = new(ExecutionSummary)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuModuleStatus.
-func (in *TofuModuleStatus) DeepCopy() *TofuModuleStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuPolicyStatus.
+func (in *TofuPolicyStatus) DeepCopy() *TofuPolicyStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(TofuModuleStatus)
+	out := new(TofuPolicyStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -665,6 +1296,65 @@ func (in *TofuStack) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuStackExecutionCounts) DeepCopyInto(out *TofuStackExecutionCounts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuStackExecutionCounts.
+func (in *TofuStackExecutionCounts) DeepCopy() *TofuStackExecutionCounts {
+	if in == nil {
+		return nil
+	}
+	out := new(TofuStackExecutionCounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuStackInstance) DeepCopyInto(out *TofuStackInstance) {
+	*out = *in
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make(map[string]apiextensionsv1.JSON, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ValueSources != nil {
+		in, out := &in.ValueSources, &out.ValueSources
+		*out = make(map[string]ValueFrom, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuStackInstance.
+func (in *TofuStackInstance) DeepCopy() *TofuStackInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(TofuStackInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuStackInstanceStatus) DeepCopyInto(out *TofuStackInstanceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuStackInstanceStatus.
+func (in *TofuStackInstanceStatus) DeepCopy() *TofuStackInstanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TofuStackInstanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TofuStackList) DeepCopyInto(out *TofuStackList) {
 	*out = *in
@@ -697,6 +1387,41 @@ func (in *TofuStackList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuStackMatrixAxis) DeepCopyInto(out *TofuStackMatrixAxis) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuStackMatrixAxis.
+func (in *TofuStackMatrixAxis) DeepCopy() *TofuStackMatrixAxis {
+	if in == nil {
+		return nil
+	}
+	out := new(TofuStackMatrixAxis)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TofuStackRolloutSpec) DeepCopyInto(out *TofuStackRolloutSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuStackRolloutSpec.
+func (in *TofuStackRolloutSpec) DeepCopy() *TofuStackRolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TofuStackRolloutSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TofuStackSpec) DeepCopyInto(out *TofuStackSpec) {
 	*out = *in
@@ -707,6 +1432,30 @@ func (in *TofuStackSpec) DeepCopyInto(out *TofuStackSpec) {
 		*out = new(DriftDetectionSpec)
 		**out = **in
 	}
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]TofuStackInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Matrix != nil {
+		in, out := &in.Matrix, &out.Matrix
+		*out = make([]TofuStackMatrixAxis, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(TofuStackRolloutSpec)
+		**out = **in
+	}
+	if in.HistoryLimits != nil {
+		in, out := &in.HistoryLimits, &out.HistoryLimits
+		*out = new(HistoryLimits)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuStackSpec.
@@ -739,6 +1488,12 @@ func (in *TofuStackStatus) DeepCopyInto(out *TofuStackStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]TofuStackInstanceStatus, len(*in))
+		copy(*out, *in)
+	}
+	out.Executions = in.Executions
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TofuStackStatus.
@@ -0,0 +1,111 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ObjectMetadata defines metadata fields for generated objects.
+type ObjectMetadata struct {
+	// Labels are key-value pairs attached to the object.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations provide additional metadata for the object.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// GenerateName is used to auto-generate a unique object name with the specified prefix.
+	GenerateName string `json:"generateName,omitempty"`
+}
+
+// ObjectRef defines a reference to another namespaced resource.
+type ObjectRef struct {
+	Name string `json:"name"`
+	// Namespace defaults to the namespace of the referencing object.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ValueSource selects a single value from a Secret or a ConfigMap. Exactly one of the references
+// must be set.
+type ValueSource struct {
+	// SecretRef selects a key of a Secret in the namespace of the referencing object.
+	SecretRef *SecretKeyRef `json:"secretRef,omitempty"`
+	// ConfigMapRef selects a key of a ConfigMap in the namespace of the referencing object.
+	ConfigMapRef *ConfigMapKeyRef `json:"configMapRef,omitempty"`
+}
+
+// SecretKeyRef identifies a key within a Kubernetes Secret.
+type SecretKeyRef struct {
+	// Name is the name of the Secret.
+	Name string `json:"name"`
+	// Key is the specific key within the Secret.
+	Key string `json:"key"`
+}
+
+// ConfigMapKeyRef identifies a key within a ConfigMap.
+type ConfigMapKeyRef struct {
+	// Name is the name of the ConfigMap.
+	Name string `json:"name"`
+	// Key is the specific key within the ConfigMap.
+	Key string `json:"key"`
+}
+
+// HistoryLimits bounds how many finished executions a parent resource keeps. Unset limits fall
+// back to the operator settings.
+type HistoryLimits struct {
+	// +kubebuilder:validation:Minimum=0
+	// Successful is the number of succeeded executions to keep.
+	Successful *int32 `json:"successful,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// Failed is the number of failed, cancelled or timed out executions to keep.
+	Failed *int32 `json:"failed,omitempty"`
+}
+
+// DriftDetectionSpec configures drift detection for a TofuModule or TofuStack.
+type DriftDetectionSpec struct {
+	// Enabled turns drift detection on.
+	Enabled bool `json:"enabled"`
+	// Interval is the time between drift checks, e.g. "30m".
+	Interval metav1.Duration `json:"interval,omitempty"`
+}
+
+// ExecutionSummary captures metadata about a specific execution of a module.
+type ExecutionSummary struct {
+	Revision    string       `json:"revision,omitempty"`
+	StartedAt   *metav1.Time `json:"startedAt,omitempty"`
+	FinishedAt  *metav1.Time `json:"finishedAt,omitempty"`
+	Summary     string       `json:"summary,omitempty"`
+	TriggeredBy string       `json:"triggeredBy,omitempty"`
+	JobName     string       `json:"jobName,omitempty"`
+	// HasChanges reports whether a successful plan proposes changes.
+	HasChanges *bool `json:"hasChanges,omitempty"`
+}
+
+// ModuleStatus is the observed state shared by TofuModules and TofuStacks.
+type ModuleStatus struct {
+	// Phase is the high-level lifecycle phase.
+	Phase string `json:"phase,omitempty"`
+	// ObservedGeneration is the generation the status was computed for.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastPlan summarizes the most recent plan.
+	LastPlan *ExecutionSummary `json:"lastPlan,omitempty"`
+	// LastApply summarizes the most recent apply.
+	LastApply *ExecutionSummary `json:"lastApply,omitempty"`
+	// Conditions contains the standard condition set.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// LastExecutionName is the name of the most recent TofuExecution.
+	LastExecutionName string `json:"lastExecution,omitempty"`
+}
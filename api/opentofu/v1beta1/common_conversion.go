@@ -0,0 +1,85 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+// Conversions to and from the v1alpha1 hub. Structs whose fields are identical in both versions
+// are converted with Go type conversions, so the build breaks when one version gains a field the
+// other lacks instead of the field being silently dropped.
+
+// convertSlice converts every element of src, keeping nil slices nil.
+func convertSlice[S, D any](src []S, convert func(S) D) []D {
+	if src == nil {
+		return nil
+	}
+	dst := make([]D, len(src))
+	for i := range src {
+		dst[i] = convert(src[i])
+	}
+	return dst
+}
+
+// convertMap converts every value of src, keeping nil maps nil.
+func convertMap[S, D any](src map[string]S, convert func(S) D) map[string]D {
+	if src == nil {
+		return nil
+	}
+	dst := make(map[string]D, len(src))
+	for k, v := range src {
+		dst[k] = convert(v)
+	}
+	return dst
+}
+
+func convertValueSourceTo(src ValueSource) v1alpha1.ValueFrom {
+	return v1alpha1.ValueFrom{
+		SecretRef:    (*v1alpha1.KeyRef)(src.SecretRef),
+		ConfigMapRef: (*v1alpha1.KeyRef)(src.ConfigMapRef),
+	}
+}
+
+func convertValueSourceFrom(src v1alpha1.ValueFrom) ValueSource {
+	return ValueSource{
+		SecretRef:    (*SecretKeyRef)(src.SecretRef),
+		ConfigMapRef: (*ConfigMapKeyRef)(src.ConfigMapRef),
+	}
+}
+
+func convertModuleStatusTo(src ModuleStatus) v1alpha1.TofuModuleStatus {
+	return v1alpha1.TofuModuleStatus{
+		Phase:              src.Phase,
+		ObservedGeneration: src.ObservedGeneration,
+		LastPlan:           (*v1alpha1.ExecutionSummary)(src.LastPlan),
+		LastApply:          (*v1alpha1.ExecutionSummary)(src.LastApply),
+		Conditions:         src.Conditions,
+		LastExecutionName:  src.LastExecutionName,
+	}
+}
+
+func convertModuleStatusFrom(src v1alpha1.TofuModuleStatus) ModuleStatus {
+	return ModuleStatus{
+		Phase:              src.Phase,
+		ObservedGeneration: src.ObservedGeneration,
+		LastPlan:           (*ExecutionSummary)(src.LastPlan),
+		LastApply:          (*ExecutionSummary)(src.LastApply),
+		Conditions:         src.Conditions,
+		LastExecutionName:  src.LastExecutionName,
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+// ConvertTo converts this TofuExecution (v1beta1) to the Hub version (v1alpha1).
+func (src *TofuExecution) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.TofuExecution)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = convertExecutionSpecTo(src.Spec)
+	dst.Status = v1alpha1.TofuExecutionStatus{
+		ExecutionSummary: v1alpha1.ExecutionSummary(src.Status.ExecutionSummary),
+		Phase:            src.Status.Phase,
+		Image:            src.Status.Image,
+		FailureReason:    v1alpha1.FailureReason(src.Status.FailureReason),
+		Policies:         convertSlice(src.Status.Policies, convertPolicyResultTo),
+		Conditions:       src.Status.Conditions,
+	}
+	if lockfile := src.Status.Lockfile; lockfile != nil {
+		dst.Status.Lockfile = &v1alpha1.LockfileStatus{
+			Digest: lockfile.Digest,
+			Providers: convertSlice(lockfile.Providers, func(p ProviderLock) v1alpha1.ProviderLock {
+				return v1alpha1.ProviderLock(p)
+			}),
+		}
+	}
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1alpha1) to this TofuExecution (v1beta1).
+func (dst *TofuExecution) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.TofuExecution)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = convertExecutionSpecFrom(src.Spec)
+	dst.Status = TofuExecutionStatus{
+		ExecutionSummary: ExecutionSummary(src.Status.ExecutionSummary),
+		Phase:            src.Status.Phase,
+		Image:            src.Status.Image,
+		FailureReason:    FailureReason(src.Status.FailureReason),
+		Policies:         convertSlice(src.Status.Policies, convertPolicyResultFrom),
+		Conditions:       src.Status.Conditions,
+	}
+	if lockfile := src.Status.Lockfile; lockfile != nil {
+		dst.Status.Lockfile = &LockfileStatus{
+			Digest: lockfile.Digest,
+			Providers: convertSlice(lockfile.Providers, func(p v1alpha1.ProviderLock) ProviderLock {
+				return ProviderLock(p)
+			}),
+		}
+	}
+	return nil
+}
+
+func convertExecutionSpecTo(src TofuExecutionSpec) v1alpha1.TofuExecutionSpec {
+	dst := v1alpha1.TofuExecutionSpec{
+		Action:           src.Action,
+		ModuleRef:        v1alpha1.ObjectRef(src.ModuleRef),
+		JobTemplate:      convertJobTemplateTo(src.JobTemplate),
+		Engine:           v1alpha1.EngineSpec(src.Engine),
+		Workspace:        src.Workspace,
+		Variables:        src.Variables,
+		ValueSources:     convertMap(src.ValueSources, convertValueSourceTo),
+		Priority:         src.Priority,
+		Timeout:          src.Timeout,
+		Cancel:           src.Cancel,
+		LockfileReadonly: src.LockfileReadonly,
+		PlanRef:          src.PlanRef,
+	}
+	if retry := src.RetryPolicy; retry != nil {
+		dst.RetryPolicy = &v1alpha1.RetryPolicy{
+			MaxRetries: retry.MaxRetries,
+			Backoff:    retry.Backoff,
+			RetryOn: convertSlice(retry.RetryOn, func(r FailureReason) v1alpha1.FailureReason {
+				return v1alpha1.FailureReason(r)
+			}),
+		}
+	}
+	return dst
+}
+
+func convertExecutionSpecFrom(src v1alpha1.TofuExecutionSpec) TofuExecutionSpec {
+	dst := TofuExecutionSpec{
+		Action:           src.Action,
+		ModuleRef:        ObjectRef(src.ModuleRef),
+		JobTemplate:      convertJobTemplateFrom(src.JobTemplate),
+		Engine:           EngineSpec(src.Engine),
+		Workspace:        src.Workspace,
+		Variables:        src.Variables,
+		ValueSources:     convertMap(src.ValueSources, convertValueSourceFrom),
+		Priority:         src.Priority,
+		Timeout:          src.Timeout,
+		Cancel:           src.Cancel,
+		LockfileReadonly: src.LockfileReadonly,
+		PlanRef:          src.PlanRef,
+	}
+	if retry := src.RetryPolicy; retry != nil {
+		dst.RetryPolicy = &RetryPolicy{
+			MaxRetries: retry.MaxRetries,
+			Backoff:    retry.Backoff,
+			RetryOn: convertSlice(retry.RetryOn, func(r v1alpha1.FailureReason) FailureReason {
+				return FailureReason(r)
+			}),
+		}
+	}
+	return dst
+}
+
+func convertJobTemplateTo(src JobTemplateSpec) v1alpha1.JobTemplateSpec {
+	return v1alpha1.JobTemplateSpec{
+		Metadata:                v1alpha1.ObjectMetadata(src.Metadata),
+		Env:                     src.Env,
+		EnvFrom:                 src.EnvFrom,
+		ServiceAccountName:      src.ServiceAccountName,
+		Resources:               src.Resources,
+		VolumeMounts:            src.VolumeMounts,
+		NodeSelector:            src.NodeSelector,
+		Tolerations:             src.Tolerations,
+		Affinity:                src.Affinity,
+		Volumes:                 src.Volumes,
+		ImagePullSecrets:        src.ImagePullSecrets,
+		SecurityContext:         src.SecurityContext,
+		PriorityClassName:       src.PriorityClassName,
+		TTLSecondsAfterFinished: src.TTLSecondsAfterFinished,
+		BackoffLimit:            src.BackoffLimit,
+		PodSpecPatch:            src.PodSpecPatch,
+	}
+}
+
+func convertJobTemplateFrom(src v1alpha1.JobTemplateSpec) JobTemplateSpec {
+	return JobTemplateSpec{
+		Metadata:                ObjectMetadata(src.Metadata),
+		Env:                     src.Env,
+		EnvFrom:                 src.EnvFrom,
+		ServiceAccountName:      src.ServiceAccountName,
+		Resources:               src.Resources,
+		VolumeMounts:            src.VolumeMounts,
+		NodeSelector:            src.NodeSelector,
+		Tolerations:             src.Tolerations,
+		Affinity:                src.Affinity,
+		Volumes:                 src.Volumes,
+		ImagePullSecrets:        src.ImagePullSecrets,
+		SecurityContext:         src.SecurityContext,
+		PriorityClassName:       src.PriorityClassName,
+		TTLSecondsAfterFinished: src.TTLSecondsAfterFinished,
+		BackoffLimit:            src.BackoffLimit,
+		PodSpecPatch:            src.PodSpecPatch,
+	}
+}
+
+func convertPolicyResultTo(src PolicyResult) v1alpha1.PolicyResult {
+	return v1alpha1.PolicyResult{
+		Policy:      src.Policy,
+		Rule:        src.Rule,
+		Enforcement: v1alpha1.PolicyEnforcement(src.Enforcement),
+		Outcome:     v1alpha1.PolicyOutcome(src.Outcome),
+		Message:     src.Message,
+	}
+}
+
+func convertPolicyResultFrom(src v1alpha1.PolicyResult) PolicyResult {
+	return PolicyResult{
+		Policy:      src.Policy,
+		Rule:        src.Rule,
+		Enforcement: PolicyEnforcement(src.Enforcement),
+		Outcome:     PolicyOutcome(src.Outcome),
+		Message:     src.Message,
+	}
+}
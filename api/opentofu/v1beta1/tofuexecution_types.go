@@ -0,0 +1,250 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// JobTemplateSpec provides a template for generating a Kubernetes Job.
+type JobTemplateSpec struct {
+	// Metadata defines labels, annotations, and name generation for the job.
+	Metadata ObjectMetadata `json:"metadata"`
+	// Env specifies environment variables to inject into the container.
+	Env     []corev1.EnvVar        `json:"env,omitempty"`
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+	// ServiceAccountName is the name of the service account to run the job under.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// Resources sets the compute resources of the execution container.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// VolumeMounts are mounted into the execution container.
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+	// NodeSelector constrains the execution pod to matching nodes.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations allow the execution pod to schedule onto tainted nodes.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity sets the scheduling constraints of the execution pod.
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Volumes are added to the execution pod.
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+	// ImagePullSecrets are used to pull the execution image.
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// SecurityContext sets the pod-level security attributes of the execution pod.
+	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
+	// PriorityClassName sets the priority class of the execution pod.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// TTLSecondsAfterFinished lets Kubernetes delete the Job this long after it finished. The
+	// execution keeps its status either way.
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// BackoffLimit is the number of pod retries before the Job is marked failed.
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// PodSpecPatch is a strategic merge patch applied last to the generated pod spec, for anything
+	// the fields above do not cover. The execution container is named "engine".
+	PodSpecPatch *apiextv1.JSON `json:"podSpecPatch,omitempty"`
+}
+
+// EngineSpec selects the engine CLI and its version.
+type EngineSpec struct {
+	// +kubebuilder:validation:Enum=tofu;terraform
+	// Engine type: "tofu" (OpenTofu) or "terraform" (HashiCorp Terraform)
+	// +kubebuilder:default=tofu
+	Name string `json:"name,omitempty"`
+
+	// Optional: version to run, e.g. "1.6.2", or a version constraint such as "~> 1.6" that is
+	// resolved when the execution runs. "latest" runs the newest version allowed by the module's
+	// required_version.
+	// +kubebuilder:default=latest
+	// +kubebuilder:validation:Pattern=`^(latest|\s*(=|!=|>|>=|<|<=|~>)?\s*v?[0-9]+(\.[0-9]+){0,2}(-[0-9A-Za-z.]+)?\s*(,\s*(=|!=|>|>=|<|<=|~>)?\s*v?[0-9]+(\.[0-9]+){0,2}(-[0-9A-Za-z.]+)?\s*)*)$`
+	Version string `json:"version,omitempty"`
+}
+
+// TofuExecutionSpec defines the desired execution of a Terraform-compatible module.
+type TofuExecutionSpec struct {
+	// +kubebuilder:validation:Enum=plan;apply
+	// Action specifies the execution type: "plan" or "apply".
+	Action string `json:"action"`
+	// ModuleRef references the TofuModule to be executed. The namespace defaults to the
+	// execution's.
+	ModuleRef ObjectRef `json:"moduleRef"`
+	// JobTemplate optionally overrides the default job used to run the execution.
+	JobTemplate JobTemplateSpec `json:"jobTemplate,omitempty"`
+	// Engine specifies the engine (OpenTofu, Terraform) and its version.
+	Engine EngineSpec `json:"engine,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+$`
+	// Workspace selects (or creates) the named engine workspace before running the action.
+	Workspace string `json:"workspace,omitempty"`
+	// Variables are overlaid on top of the referenced module's variables.
+	Variables map[string]apiextv1.JSON `json:"variables,omitempty"`
+	// ValueSources are overlaid on top of the referenced module's value sources.
+	ValueSources map[string]ValueSource `json:"valueSources,omitempty"`
+	// Priority orders queued executions when concurrency limits are reached; higher runs first.
+	// Defaults to 100 for apply and 0 for plan.
+	Priority *int32 `json:"priority,omitempty"`
+	// Timeout bounds how long the execution's Job may run before it is stopped and marked TimedOut.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// RetryPolicy controls whether failed executions are retried by the controller owning them.
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+	// Cancel requests the execution to stop. The engine is interrupted so it can release its state
+	// lock before the pod is killed. Setting the CancelAnnotation to "true" has the same effect.
+	Cancel bool `json:"cancel,omitempty"`
+	// LockfileReadonly runs init with -lockfile=readonly: the module's .terraform.lock.hcl is used
+	// as is, and init fails instead of updating it when it does not cover the required providers.
+	LockfileReadonly bool `json:"lockfileReadonly,omitempty"`
+	// PlanRef names a plan TofuExecution of the same module and namespace this apply follows. The
+	// apply waits for the plan to finish and fails with LockfileMismatch unless it resolves the
+	// same dependency lock file as the plan.
+	PlanRef string `json:"planRef,omitempty"`
+}
+
+// FailureReason classifies why an execution failed.
+// +kubebuilder:validation:Enum=InitError;LockContention;PlanError;ApplyError;LockfileMismatch;PolicyDenied;Rejected;Unknown
+type FailureReason string
+
+const (
+	// FailureInitError means checking out the module, initializing it or selecting the workspace failed.
+	FailureInitError FailureReason = "InitError"
+	// FailureLockContention means the engine could not acquire the state lock.
+	FailureLockContention FailureReason = "LockContention"
+	// FailurePlanError means the plan action failed.
+	FailurePlanError FailureReason = "PlanError"
+	// FailureApplyError means the apply action failed.
+	FailureApplyError FailureReason = "ApplyError"
+	// FailureLockfileMismatch means an apply resolved a different dependency lock file than the
+	// plan it follows.
+	FailureLockfileMismatch FailureReason = "LockfileMismatch"
+	// FailurePolicyDenied means the plan violated a TofuPolicy with Deny enforcement, so the
+	// apply was not started.
+	FailurePolicyDenied FailureReason = "PolicyDenied"
+	// FailureRejected means the execution was refused before running, e.g. because its pod
+	// violates the pod security enforced in its namespace.
+	FailureRejected FailureReason = "Rejected"
+	// FailureUnknown means the failure could not be attributed to a stage, e.g. the pod was evicted.
+	FailureUnknown FailureReason = "Unknown"
+)
+
+// RetryPolicy describes how failed executions are retried. Each retry is a new TofuExecution
+// linked to the first attempt through annotations.
+type RetryPolicy struct {
+	// +kubebuilder:validation:Minimum=0
+	// MaxRetries is the number of attempts made after the first one fails.
+	MaxRetries int32 `json:"maxRetries"`
+	// Backoff is the delay before the first retry; it doubles with every further attempt.
+	// +kubebuilder:default="30s"
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+	// RetryOn lists the failure reasons worth retrying. Defaults to LockContention, PlanError and
+	// ApplyError; init errors usually point at a broken module and are not retried.
+	RetryOn []FailureReason `json:"retryOn,omitempty"`
+}
+
+// CancelAnnotation requests cancellation of a TofuExecution without editing its spec.
+const CancelAnnotation = "opentofu.soyplane.io/cancel"
+
+// ConditionLockfileMatched reports whether an apply used the same dependency lock file as the
+// plan named by its PlanRef.
+const ConditionLockfileMatched = "LockfileMatched"
+
+// LockfileStatus records the dependency lock file an execution ran with.
+type LockfileStatus struct {
+	// Digest is the SHA-256 of .terraform.lock.hcl after init.
+	Digest string `json:"digest"`
+	// Providers lists the locked provider versions and hashes.
+	Providers []ProviderLock `json:"providers,omitempty"`
+}
+
+// ProviderLock is a provider entry of the dependency lock file.
+type ProviderLock struct {
+	// Address is the provider source address, e.g. registry.opentofu.org/hashicorp/random.
+	Address string `json:"address"`
+	Version string `json:"version,omitempty"`
+	// Hashes are the package checksums accepted for the provider. They are omitted when the lock
+	// file is too large to be reported in full; the digest still covers them.
+	Hashes []string `json:"hashes,omitempty"`
+}
+
+// PolicyOutcome is the result of checking a plan against a policy rule.
+// +kubebuilder:validation:Enum=Pass;Fail;Error
+type PolicyOutcome string
+
+const (
+	PolicyPass PolicyOutcome = "Pass"
+	PolicyFail PolicyOutcome = "Fail"
+	// PolicyError means the rule could not be evaluated. It counts as a violation.
+	PolicyError PolicyOutcome = "Error"
+)
+
+// PolicyResult records the outcome of a TofuPolicy rule for an execution's plan.
+type PolicyResult struct {
+	Policy      string            `json:"policy"`
+	Rule        string            `json:"rule"`
+	Enforcement PolicyEnforcement `json:"enforcement"`
+	Outcome     PolicyOutcome     `json:"outcome"`
+	// Message is the rule's message for violations.
+	Message string `json:"message,omitempty"`
+}
+
+// TofuExecutionStatus defines the observed state of a TofuExecution.
+type TofuExecutionStatus struct {
+	ExecutionSummary `json:",inline"`
+	// +kubebuilder:validation:Enum=Pending;Queued;Running;Succeeded;Failed;Cancelled;TimedOut
+	// Phase represents the current lifecycle state of the execution. Queued executions wait for
+	// execution capacity or for another execution to release the module lock.
+	Phase string `json:"phase,omitempty"`
+	// Image is the execution image resolved from the engine and the settings' image table.
+	Image string `json:"image,omitempty"`
+	// FailureReason classifies the failure when Phase is Failed.
+	FailureReason FailureReason `json:"failureReason,omitempty"`
+	// Lockfile records the dependency lock file resolved by init.
+	Lockfile *LockfileStatus `json:"lockfile,omitempty"`
+	// Policies lists the outcome of every TofuPolicy rule checked against the plan.
+	Policies []PolicyResult `json:"policies,omitempty"`
+	// Conditions contains detailed condition objects for execution transitions.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=tfexec
+// +kubebuilder:printcolumn:name="Action",type=string,JSONPath=`.spec.action`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// TofuExecution is the Schema for the tofuexecutions API.
+type TofuExecution struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TofuExecutionSpec   `json:"spec,omitempty"`
+	Status TofuExecutionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TofuExecutionList contains a list of TofuExecution.
+type TofuExecutionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TofuExecution `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TofuExecution{}, &TofuExecutionList{})
+}
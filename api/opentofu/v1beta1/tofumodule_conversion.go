@@ -0,0 +1,133 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+// ConvertTo converts this TofuModule (v1beta1) to the Hub version (v1alpha1).
+func (src *TofuModule) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.TofuModule)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.TofuModuleSpec{
+		Source:  src.Spec.Source,
+		Version: src.Spec.Version,
+		Workdir: src.Spec.Workdir,
+		Backend: v1alpha1.BackendSpec{
+			Type:         src.Spec.Backend.Type,
+			RawConfig:    src.Spec.Backend.RawConfig,
+			Config:       src.Spec.Backend.Config,
+			ValueSources: convertMap(src.Spec.Backend.ValueSources, convertValueSourceTo),
+		},
+		Providers: convertSlice(src.Spec.Providers, func(p TofuProviderRef) v1alpha1.TofuProviderRef {
+			return v1alpha1.TofuProviderRef(p)
+		}),
+		Variables:    src.Spec.Variables,
+		ValueSources: convertMap(src.Spec.ValueSources, convertValueSourceTo),
+		Outputs: convertSlice(src.Spec.Outputs, func(o OutputSpec) v1alpha1.OutputSpec {
+			return v1alpha1.OutputSpec{
+				From: o.From,
+				To: convertSlice(o.To, func(t OutputTarget) v1alpha1.OutputTarget {
+					return v1alpha1.OutputTarget(t)
+				}),
+			}
+		}),
+		ExecutionTemplate:  convertExecutionTemplateTo(src.Spec.ExecutionTemplate),
+		AutoApply:          src.Spec.AutoApply,
+		DriftDetection:     (*v1alpha1.DriftDetectionSpec)(src.Spec.DriftDetection),
+		HistoryLimits:      (*v1alpha1.HistoryLimits)(src.Spec.HistoryLimits),
+		PullRequestComment: convertPullRequestCommentTo(src.Spec.PullRequestComment),
+	}
+	dst.Status = convertModuleStatusTo(src.Status.ModuleStatus)
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1alpha1) to this TofuModule (v1beta1).
+func (dst *TofuModule) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.TofuModule)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = TofuModuleSpec{
+		Source:  src.Spec.Source,
+		Version: src.Spec.Version,
+		Workdir: src.Spec.Workdir,
+		Backend: BackendSpec{
+			Type:         src.Spec.Backend.Type,
+			RawConfig:    src.Spec.Backend.RawConfig,
+			Config:       src.Spec.Backend.Config,
+			ValueSources: convertMap(src.Spec.Backend.ValueSources, convertValueSourceFrom),
+		},
+		Providers: convertSlice(src.Spec.Providers, func(p v1alpha1.TofuProviderRef) TofuProviderRef {
+			return TofuProviderRef(p)
+		}),
+		Variables:    src.Spec.Variables,
+		ValueSources: convertMap(src.Spec.ValueSources, convertValueSourceFrom),
+		Outputs: convertSlice(src.Spec.Outputs, func(o v1alpha1.OutputSpec) OutputSpec {
+			return OutputSpec{
+				From: o.From,
+				To: convertSlice(o.To, func(t v1alpha1.OutputTarget) OutputTarget {
+					return OutputTarget(t)
+				}),
+			}
+		}),
+		ExecutionTemplate:  convertExecutionTemplateFrom(src.Spec.ExecutionTemplate),
+		AutoApply:          src.Spec.AutoApply,
+		DriftDetection:     (*DriftDetectionSpec)(src.Spec.DriftDetection),
+		HistoryLimits:      (*HistoryLimits)(src.Spec.HistoryLimits),
+		PullRequestComment: convertPullRequestCommentFrom(src.Spec.PullRequestComment),
+	}
+	dst.Status = TofuModuleStatus{ModuleStatus: convertModuleStatusFrom(src.Status)}
+	return nil
+}
+
+func convertExecutionTemplateTo(src ExecutionTemplateSpec) v1alpha1.ExecutionTemplateSpec {
+	return v1alpha1.ExecutionTemplateSpec{
+		Metadata: v1alpha1.ObjectMetadata(src.Metadata),
+		Spec:     convertExecutionSpecTo(src.Spec),
+	}
+}
+
+func convertExecutionTemplateFrom(src v1alpha1.ExecutionTemplateSpec) ExecutionTemplateSpec {
+	return ExecutionTemplateSpec{
+		Metadata: ObjectMetadata(src.Metadata),
+		Spec:     convertExecutionSpecFrom(src.Spec),
+	}
+}
+
+func convertPullRequestCommentTo(src *PullRequestCommentSpec) *v1alpha1.PullRequestCommentSpec {
+	if src == nil {
+		return nil
+	}
+	return &v1alpha1.PullRequestCommentSpec{
+		Provider:       v1alpha1.GitProvider(src.Provider),
+		TokenSecretRef: v1alpha1.KeyRef(src.TokenSecretRef),
+		APIURL:         src.APIURL,
+	}
+}
+
+func convertPullRequestCommentFrom(src *v1alpha1.PullRequestCommentSpec) *PullRequestCommentSpec {
+	if src == nil {
+		return nil
+	}
+	return &PullRequestCommentSpec{
+		Provider:       GitProvider(src.Provider),
+		TokenSecretRef: SecretKeyRef(src.TokenSecretRef),
+		APIURL:         src.APIURL,
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackendSpec defines the backend configuration for state storage of a module.
+type BackendSpec struct {
+	Type         string                   `json:"type"`
+	RawConfig    string                   `json:"rawConfig,omitempty"`
+	Config       map[string]apiextv1.JSON `json:"config,omitempty"`
+	ValueSources map[string]ValueSource   `json:"valueSources,omitempty"`
+}
+
+// TofuProviderRef contains a reference to a Tofu provider.
+type TofuProviderRef struct {
+	Name  string `json:"name"`
+	Alias string `json:"alias,omitempty"`
+}
+
+// OutputSpec defines the output configuration for a module.
+type OutputSpec struct {
+	From string         `json:"from"`
+	To   []OutputTarget `json:"to"`
+}
+
+// OutputTarget defines the target for the output of a module.
+type OutputTarget struct {
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	// Kind indicates the type of output target object.
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+// ExecutionTemplateSpec defines the metadata and spec of the TofuExecutions created for a module
+// or stack.
+type ExecutionTemplateSpec struct {
+	// Metadata defines labels, annotations, and name generation for the execution.
+	Metadata ObjectMetadata `json:"metadata,omitempty"`
+	// Spec defines the TofuExecution runtime configuration.
+	Spec TofuExecutionSpec `json:"spec"`
+}
+
+// GitProvider names the service hosting a module repository.
+// +kubebuilder:validation:Enum=GitHub;GitLab;Gitea
+type GitProvider string
+
+const (
+	GitProviderGitHub GitProvider = "GitHub"
+	GitProviderGitLab GitProvider = "GitLab"
+	GitProviderGitea  GitProvider = "Gitea"
+)
+
+// PullRequestCommentSpec configures the comment plans of a module post on the pull request it
+// tracks.
+type PullRequestCommentSpec struct {
+	// Provider is the service hosting the repository the module is cloned from.
+	Provider GitProvider `json:"provider"`
+	// TokenSecretRef selects the key of a Secret in the module's namespace holding the API token
+	// comments are posted with.
+	TokenSecretRef SecretKeyRef `json:"tokenSecretRef"`
+	// APIURL is the API endpoint of the provider. It defaults to https://api.github.com for
+	// github.com and otherwise to https://<host>/api/v3 for GitHub, https://<host>/api/v4 for
+	// GitLab and https://<host>/api/v1 for Gitea, <host> being the host of the module source.
+	// +optional
+	APIURL string `json:"apiURL,omitempty"`
+}
+
+// TofuModuleSpec defines the desired state of a TofuModule resource.
+type TofuModuleSpec struct {
+	Source string `json:"source"`
+	// Version is the branch, tag, commit or ref checked out after cloning the source, e.g.
+	// main or refs/pull/42/head. The default branch is used when empty.
+	Version           string                   `json:"version,omitempty"`
+	Workdir           string                   `json:"workdir,omitempty"`
+	Backend           BackendSpec              `json:"backend,omitempty"`
+	Providers         []TofuProviderRef        `json:"providers,omitempty"`
+	Variables         map[string]apiextv1.JSON `json:"variables,omitempty"`
+	ValueSources      map[string]ValueSource   `json:"valueSources,omitempty"`
+	Outputs           []OutputSpec             `json:"outputs,omitempty"`
+	ExecutionTemplate ExecutionTemplateSpec    `json:"executionTemplate,omitempty"`
+	// AutoApply applies changes automatically when drift is detected.
+	AutoApply bool `json:"autoApply,omitempty"`
+	// DriftDetection configures periodic drift checks.
+	DriftDetection *DriftDetectionSpec `json:"driftDetection,omitempty"`
+	// HistoryLimits bounds how many finished executions of this module are kept.
+	HistoryLimits *HistoryLimits `json:"historyLimits,omitempty"`
+	// PullRequestComment posts the results of the module's plans as a comment on the pull
+	// request of the branch or pull request ref in Version, and updates it on every plan.
+	// +optional
+	PullRequestComment *PullRequestCommentSpec `json:"pullRequestComment,omitempty"`
+}
+
+// TofuModuleStatus defines the observed state of a TofuModule.
+type TofuModuleStatus struct {
+	ModuleStatus `json:",inline"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=tfmod
+// +kubebuilder:printcolumn:name="Source",type=string,JSONPath=`.spec.source`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.spec.version`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="LastExecution",type=string,JSONPath=`.status.lastExecution`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// TofuModule is the Schema for the tofumodules API.
+type TofuModule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TofuModuleSpec   `json:"spec,omitempty"`
+	Status TofuModuleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TofuModuleList contains a list of TofuModule.
+type TofuModuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TofuModule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TofuModule{}, &TofuModuleList{})
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+// ConvertTo converts this TofuModuleGrant (v1beta1) to the Hub version (v1alpha1).
+func (src *TofuModuleGrant) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.TofuModuleGrant)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.TofuModuleGrantSpec{
+		From: convertSlice(src.Spec.From, func(f TofuModuleGrantFrom) v1alpha1.TofuModuleGrantFrom {
+			return v1alpha1.TofuModuleGrantFrom(f)
+		}),
+		To: convertSlice(src.Spec.To, func(t TofuModuleGrantTo) v1alpha1.TofuModuleGrantTo {
+			return v1alpha1.TofuModuleGrantTo(t)
+		}),
+	}
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1alpha1) to this TofuModuleGrant (v1beta1).
+func (dst *TofuModuleGrant) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.TofuModuleGrant)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = TofuModuleGrantSpec{
+		From: convertSlice(src.Spec.From, func(f v1alpha1.TofuModuleGrantFrom) TofuModuleGrantFrom {
+			return TofuModuleGrantFrom(f)
+		}),
+		To: convertSlice(src.Spec.To, func(t v1alpha1.TofuModuleGrantTo) TofuModuleGrantTo {
+			return TofuModuleGrantTo(t)
+		}),
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TofuModuleGrantSpec lists the objects of other namespaces allowed to reference the TofuModules
+// of the grant's namespace. Cross-namespace module references are refused unless a grant in the
+// module's namespace permits them.
+type TofuModuleGrantSpec struct {
+	// From lists the kinds and namespaces allowed to reference modules of this namespace.
+	// +kubebuilder:validation:MinItems=1
+	From []TofuModuleGrantFrom `json:"from"`
+	// To restricts the grant to the named modules. An empty list grants every module of the
+	// namespace.
+	To []TofuModuleGrantTo `json:"to,omitempty"`
+}
+
+// TofuModuleGrantFrom describes the objects a grant applies to.
+type TofuModuleGrantFrom struct {
+	// Kind of the referencing object. Executions created by a TofuStack are permitted by a grant
+	// for the stack.
+	// +kubebuilder:validation:Enum=TofuExecution;TofuStack
+	Kind string `json:"kind"`
+	// Namespace of the referencing object.
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+}
+
+// TofuModuleGrantTo names a module a grant applies to.
+type TofuModuleGrantTo struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// TofuModuleGrantStatus holds observed state (currently unused).
+type TofuModuleGrantStatus struct {
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=tfgrant
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// TofuModuleGrant is the Schema for the tofumodulegrants API.
+type TofuModuleGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TofuModuleGrantSpec   `json:"spec,omitempty"`
+	Status TofuModuleGrantStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TofuModuleGrantList contains a list of TofuModuleGrant.
+type TofuModuleGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TofuModuleGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TofuModuleGrant{}, &TofuModuleGrantList{})
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+// ConvertTo converts this TofuNotification (v1beta1) to the Hub version (v1alpha1).
+func (src *TofuNotification) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.TofuNotification)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.TofuNotificationSpec{
+		Sink: v1alpha1.NotificationSink{
+			Type:         v1alpha1.NotificationSinkType(src.Spec.Sink.Type),
+			URL:          src.Spec.Sink.URL,
+			URLSecretRef: (*v1alpha1.NamespacedKeyRef)(src.Spec.Sink.URLSecretRef),
+		},
+		Events: convertSlice(src.Spec.Events, func(e NotificationEvent) v1alpha1.NotificationEvent {
+			return v1alpha1.NotificationEvent(e)
+		}),
+		NamespaceSelector: src.Spec.NamespaceSelector,
+		ModuleSelector:    src.Spec.ModuleSelector,
+		Template:          src.Spec.Template,
+	}
+	dst.Status = v1alpha1.TofuNotificationStatus(src.Status)
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1alpha1) to this TofuNotification (v1beta1).
+func (dst *TofuNotification) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.TofuNotification)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = TofuNotificationSpec{
+		Sink: NotificationSink{
+			Type:         NotificationSinkType(src.Spec.Sink.Type),
+			URL:          src.Spec.Sink.URL,
+			URLSecretRef: (*NamespacedKeyRef)(src.Spec.Sink.URLSecretRef),
+		},
+		Events: convertSlice(src.Spec.Events, func(e v1alpha1.NotificationEvent) NotificationEvent {
+			return NotificationEvent(e)
+		}),
+		NamespaceSelector: src.Spec.NamespaceSelector,
+		ModuleSelector:    src.Spec.ModuleSelector,
+		Template:          src.Spec.Template,
+	}
+	dst.Status = TofuNotificationStatus(src.Status)
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NotificationSinkType selects how notifications are formatted and delivered.
+// +kubebuilder:validation:Enum=Webhook;Slack;CloudEvents
+type NotificationSinkType string
+
+const (
+	// SinkWebhook posts a JSON document describing the event.
+	SinkWebhook NotificationSinkType = "Webhook"
+	// SinkSlack posts a message to a Slack-compatible incoming webhook.
+	SinkSlack NotificationSinkType = "Slack"
+	// SinkCloudEvents posts a CloudEvent in structured JSON mode.
+	SinkCloudEvents NotificationSinkType = "CloudEvents"
+)
+
+// NotificationEvent names an event notifications are sent for. The names match the reasons of
+// the Kubernetes Events recorded for executions and modules.
+// +kubebuilder:validation:Enum=PlanSucceeded;PlanHasChanges;PlanFailed;ApplySucceeded;ApplyFailed;TimedOut;Cancelled;Rejected;DriftDetected;ApprovalRequired
+type NotificationEvent string
+
+// TofuNotificationSpec defines which execution events are sent where.
+type TofuNotificationSpec struct {
+	// Sink is where notifications are delivered.
+	Sink NotificationSink `json:"sink"`
+	// Events lists the events notified. An empty list notifies every event.
+	// +optional
+	Events []NotificationEvent `json:"events,omitempty"`
+	// NamespaceSelector selects the namespaces whose executions are notified. An empty selector
+	// selects every namespace.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// ModuleSelector selects the TofuModules whose executions are notified. An empty selector
+	// selects every module.
+	// +optional
+	ModuleSelector *metav1.LabelSelector `json:"moduleSelector,omitempty"`
+	// Template is a Go template rendering the notification in place of the default one: the
+	// request body for Webhook sinks, the message text for Slack sinks and the event data for
+	// CloudEvents sinks. It receives .Event, .Message, .Time, .Kind, .Namespace and .Name, and
+	// .Object, the TofuExecution or TofuModule the event is about. The json function renders a
+	// value as JSON.
+	// +optional
+	Template string `json:"template,omitempty"`
+}
+
+// NotificationSink is an HTTP endpoint notifications are posted to.
+type NotificationSink struct {
+	// Type selects how notifications are formatted.
+	Type NotificationSinkType `json:"type"`
+	// URL notifications are posted to. Exactly one of URL and URLSecretRef must be set.
+	// +optional
+	URL string `json:"url,omitempty"`
+	// URLSecretRef reads the URL from a Secret, for URLs carrying credentials such as Slack
+	// incoming webhooks.
+	// +optional
+	URLSecretRef *NamespacedKeyRef `json:"urlSecretRef,omitempty"`
+}
+
+// NamespacedKeyRef selects a key of an object in a given namespace.
+type NamespacedKeyRef struct {
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// TofuNotificationStatus reports how deliveries went.
+type TofuNotificationStatus struct {
+	// LastDeliveredAt is when a notification was last delivered.
+	// +optional
+	LastDeliveredAt *metav1.Time `json:"lastDeliveredAt,omitempty"`
+	// LastFailedAt is when a notification was last dropped after all delivery attempts failed.
+	// +optional
+	LastFailedAt *metav1.Time `json:"lastFailedAt,omitempty"`
+	// LastFailure explains why the last dropped notification could not be delivered.
+	// +optional
+	LastFailure string `json:"lastFailure,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=tfnotif
+// +kubebuilder:printcolumn:name="Sink",type=string,JSONPath=`.spec.sink.type`
+// +kubebuilder:printcolumn:name="Last Delivered",type="date",JSONPath=".status.lastDeliveredAt"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// TofuNotification is the Schema for the tofunotifications API. Notifications are cluster-scoped:
+// they select executions across namespaces and read their sink URL from any namespace.
+type TofuNotification struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TofuNotificationSpec   `json:"spec,omitempty"`
+	Status TofuNotificationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TofuNotificationList contains a list of TofuNotification.
+type TofuNotificationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TofuNotification `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TofuNotification{}, &TofuNotificationList{})
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+// ConvertTo converts this TofuPolicy (v1beta1) to the Hub version (v1alpha1).
+func (src *TofuPolicy) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.TofuPolicy)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.TofuPolicySpec{
+		ModuleSelector: src.Spec.ModuleSelector,
+		Enforcement:    v1alpha1.PolicyEnforcement(src.Spec.Enforcement),
+		Rules: convertSlice(src.Spec.Rules, func(r PolicyRule) v1alpha1.PolicyRule {
+			return v1alpha1.PolicyRule(r)
+		}),
+	}
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1alpha1) to this TofuPolicy (v1beta1).
+func (dst *TofuPolicy) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.TofuPolicy)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = TofuPolicySpec{
+		ModuleSelector: src.Spec.ModuleSelector,
+		Enforcement:    PolicyEnforcement(src.Spec.Enforcement),
+		Rules: convertSlice(src.Spec.Rules, func(r v1alpha1.PolicyRule) PolicyRule {
+			return PolicyRule(r)
+		}),
+	}
+	return nil
+}
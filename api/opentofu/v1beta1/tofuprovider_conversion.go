@@ -0,0 +1,59 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+// ConvertTo converts this TofuProvider (v1beta1) to the Hub version (v1alpha1).
+func (src *TofuProvider) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.TofuProvider)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.TofuProviderSpec{
+		Type: src.Spec.Type,
+		ValueSources: convertMap(src.Spec.ValueSources, func(v ValueSource) v1alpha1.ValueSource {
+			return v1alpha1.ValueSource{
+				SecretRef:    (*v1alpha1.SecretKeyRef)(v.SecretRef),
+				ConfigMapRef: (*v1alpha1.ConfigMapKeyRef)(v.ConfigMapRef),
+			}
+		}),
+		RawConfig: src.Spec.RawConfig,
+		Config:    src.Spec.Config,
+	}
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1alpha1) to this TofuProvider (v1beta1).
+func (dst *TofuProvider) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.TofuProvider)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = TofuProviderSpec{
+		Type: src.Spec.Type,
+		ValueSources: convertMap(src.Spec.ValueSources, func(v v1alpha1.ValueSource) ValueSource {
+			return ValueSource{
+				SecretRef:    (*SecretKeyRef)(v.SecretRef),
+				ConfigMapRef: (*ConfigMapKeyRef)(v.ConfigMapRef),
+			}
+		}),
+		RawConfig: src.Spec.RawConfig,
+		Config:    src.Spec.Config,
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+// ConvertTo converts this TofuStack (v1beta1) to the Hub version (v1alpha1). The module
+// reference is serialized as moduleTemplate in v1alpha1.
+func (src *TofuStack) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.TofuStack)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.TofuStackSpec{
+		ModuleRef:         v1alpha1.ObjectRef(src.Spec.ModuleRef),
+		ExecutionTemplate: convertExecutionTemplateTo(src.Spec.ExecutionTemplate),
+		AutoApply:         src.Spec.AutoApply,
+		DriftDetection:    (*v1alpha1.DriftDetectionSpec)(src.Spec.DriftDetection),
+		Instances: convertSlice(src.Spec.Instances, func(i TofuStackInstance) v1alpha1.TofuStackInstance {
+			return v1alpha1.TofuStackInstance{
+				Name:         i.Name,
+				Workspace:    i.Workspace,
+				Variables:    i.Variables,
+				ValueSources: convertMap(i.ValueSources, convertValueSourceTo),
+				Wave:         i.Wave,
+			}
+		}),
+		Matrix: convertSlice(src.Spec.Matrix, func(a TofuStackMatrixAxis) v1alpha1.TofuStackMatrixAxis {
+			return v1alpha1.TofuStackMatrixAxis(a)
+		}),
+		Rollout:       (*v1alpha1.TofuStackRolloutSpec)(src.Spec.Rollout),
+		HistoryLimits: (*v1alpha1.HistoryLimits)(src.Spec.HistoryLimits),
+	}
+	status := convertModuleStatusTo(src.Status.ModuleStatus)
+	dst.Status = v1alpha1.TofuStackStatus{
+		Phase:              status.Phase,
+		ObservedGeneration: status.ObservedGeneration,
+		LastPlan:           status.LastPlan,
+		LastApply:          status.LastApply,
+		Conditions:         status.Conditions,
+		LastExecutionName:  status.LastExecutionName,
+		Instances: convertSlice(src.Status.Instances, func(i TofuStackInstanceStatus) v1alpha1.TofuStackInstanceStatus {
+			return v1alpha1.TofuStackInstanceStatus(i)
+		}),
+		CurrentWave: src.Status.CurrentWave,
+		Executions:  v1alpha1.TofuStackExecutionCounts(src.Status.Executions),
+	}
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1alpha1) to this TofuStack (v1beta1).
+func (dst *TofuStack) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.TofuStack)
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = TofuStackSpec{
+		ModuleRef:         ObjectRef(src.Spec.ModuleRef),
+		ExecutionTemplate: convertExecutionTemplateFrom(src.Spec.ExecutionTemplate),
+		AutoApply:         src.Spec.AutoApply,
+		DriftDetection:    (*DriftDetectionSpec)(src.Spec.DriftDetection),
+		Instances: convertSlice(src.Spec.Instances, func(i v1alpha1.TofuStackInstance) TofuStackInstance {
+			return TofuStackInstance{
+				Name:         i.Name,
+				Workspace:    i.Workspace,
+				Variables:    i.Variables,
+				ValueSources: convertMap(i.ValueSources, convertValueSourceFrom),
+				Wave:         i.Wave,
+			}
+		}),
+		Matrix: convertSlice(src.Spec.Matrix, func(a v1alpha1.TofuStackMatrixAxis) TofuStackMatrixAxis {
+			return TofuStackMatrixAxis(a)
+		}),
+		Rollout:       (*TofuStackRolloutSpec)(src.Spec.Rollout),
+		HistoryLimits: (*HistoryLimits)(src.Spec.HistoryLimits),
+	}
+	dst.Status = TofuStackStatus{
+		ModuleStatus: convertModuleStatusFrom(v1alpha1.TofuModuleStatus{
+			Phase:              src.Status.Phase,
+			ObservedGeneration: src.Status.ObservedGeneration,
+			LastPlan:           src.Status.LastPlan,
+			LastApply:          src.Status.LastApply,
+			Conditions:         src.Status.Conditions,
+			LastExecutionName:  src.Status.LastExecutionName,
+		}),
+		Instances: convertSlice(src.Status.Instances, func(i v1alpha1.TofuStackInstanceStatus) TofuStackInstanceStatus {
+			return TofuStackInstanceStatus(i)
+		}),
+		CurrentWave: src.Status.CurrentWave,
+		Executions:  TofuStackExecutionCounts(src.Status.Executions),
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TofuStackInstance describes one instantiation of the stack's module, e.g. an environment or region.
+type TofuStackInstance struct {
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	// Name identifies the instance within the stack.
+	Name string `json:"name"`
+	// Workspace overrides the engine workspace used by the instance. Defaults to the instance name.
+	Workspace string `json:"workspace,omitempty"`
+	// Variables are overlaid on top of the module and execution template variables.
+	Variables map[string]apiextv1.JSON `json:"variables,omitempty"`
+	// ValueSources are overlaid on top of the module and execution template value sources.
+	ValueSources map[string]ValueSource `json:"valueSources,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// Wave orders the instance when the rollout strategy is Waves; lower waves roll out first.
+	Wave int32 `json:"wave,omitempty"`
+}
+
+// TofuStackMatrixAxis is one dimension of a stack matrix. Each value is exposed to the
+// module as a variable named after the axis.
+type TofuStackMatrixAxis struct {
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_-]*$`
+	// Name is the variable the axis values are passed as.
+	Name string `json:"name"`
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:items:MaxLength=63
+	// Values are joined with dashes into the names of the generated instances, so they follow
+	// the rules of instance names.
+	Values []string `json:"values"`
+}
+
+// TofuStackRolloutSpec controls how changes are promoted across stack instances. A wave
+// promotes to the next one once all of its executions applied successfully.
+type TofuStackRolloutSpec struct {
+	// +kubebuilder:validation:Enum=Parallel;Sequential;Waves
+	// +kubebuilder:default=Parallel
+	// Strategy selects how instances are grouped into waves: Parallel runs every instance in a
+	// single wave, Sequential runs one instance per wave in declaration order, and Waves groups
+	// instances by their wave number.
+	Strategy string `json:"strategy,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// MaxParallel caps the number of in-flight executions within a wave. Zero means unlimited.
+	MaxParallel int32 `json:"maxParallel,omitempty"`
+	// StopOnFailure stops starting further instances of the current wave once one has failed.
+	// A wave with a failed instance never promotes to the next wave.
+	StopOnFailure bool `json:"stopOnFailure,omitempty"`
+}
+
+// TofuStackSpec defines the desired state of a TofuStack.
+type TofuStackSpec struct {
+	// ModuleRef references the TofuModule instantiated by the stack. The namespace defaults to the
+	// stack's.
+	ModuleRef ObjectRef `json:"moduleRef"`
+	// ExecutionTemplate is used to generate the stack's TofuExecutions.
+	ExecutionTemplate ExecutionTemplateSpec `json:"executionTemplate"`
+	// AutoApply applies changes automatically when drift is detected.
+	AutoApply bool `json:"autoApply,omitempty"`
+	// DriftDetection configures periodic drift checks.
+	DriftDetection *DriftDetectionSpec `json:"driftDetection,omitempty"`
+	// Instances lists the explicit instances generated by the stack, one execution each.
+	Instances []TofuStackInstance `json:"instances,omitempty"`
+	// Matrix generates one instance per combination of axis values, in addition to Instances.
+	Matrix []TofuStackMatrixAxis `json:"matrix,omitempty"`
+	// Rollout controls the order in which instances pick up a new stack generation.
+	Rollout *TofuStackRolloutSpec `json:"rollout,omitempty"`
+	// HistoryLimits bounds how many finished executions of this stack are kept.
+	HistoryLimits *HistoryLimits `json:"historyLimits,omitempty"`
+}
+
+// TofuStackInstanceStatus reports the observed state of a single stack instance.
+type TofuStackInstanceStatus struct {
+	Name string `json:"name"`
+	Wave int32  `json:"wave"`
+	// Phase is the phase of the instance's latest execution, or Waiting until the rollout
+	// reaches the instance.
+	Phase             string `json:"phase,omitempty"`
+	LastExecutionName string `json:"lastExecution,omitempty"`
+}
+
+// TofuStackExecutionCounts tallies the latest execution of each stack instance by outcome.
+type TofuStackExecutionCounts struct {
+	Succeeded int32 `json:"succeeded"`
+	Failed    int32 `json:"failed"`
+	// Pending includes running executions and instances waiting for their wave.
+	Pending int32 `json:"pending"`
+}
+
+// TofuStackStatus defines the observed state of a TofuStack.
+type TofuStackStatus struct {
+	ModuleStatus `json:",inline"`
+	// Instances reports per-instance phases when the stack fans out.
+	Instances []TofuStackInstanceStatus `json:"instances,omitempty"`
+	// CurrentWave is the wave currently being rolled out.
+	CurrentWave int32 `json:"currentWave,omitempty"`
+	// Executions counts the stack's current executions by outcome.
+	Executions TofuStackExecutionCounts `json:"executions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=tfstack
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Wave",type=integer,JSONPath=`.status.currentWave`,priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="LastExecution",type=string,JSONPath=`.status.lastExecution`
+
+// TofuStack is the Schema for the tofustacks API.
+type TofuStack struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TofuStackSpec   `json:"spec,omitempty"`
+	Status TofuStackStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TofuStackList contains a list of TofuStack.
+type TofuStackList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TofuStack `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TofuStack{}, &TofuStackList{})
+}
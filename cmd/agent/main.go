@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 
 	"github.com/soyplane-io/soyplane/internal/agent"
+	"github.com/soyplane-io/soyplane/internal/tracing"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
@@ -18,13 +22,51 @@ func main() {
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+
+	// The controller passes the execution's trace context when the execution is traced.
+	ctx := context.Background()
+	shutdownTracing := func(context.Context) error { return nil }
+	if traceParent := os.Getenv(tracing.TraceParentEnvVar); traceParent != "" && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" {
+		shutdown, err := tracing.Setup(ctx, tracing.Options{ServiceName: "soyplane-agent", SampleRatio: 1})
+		if err != nil {
+			log.Fatalf("Tracing setup failed: %v", err)
+		}
+		shutdownTracing = shutdown
+		ctx = tracing.ContextWithTraceParent(ctx, traceParent)
+	}
+
+	err := run(ctx)
+	if err := shutdownTracing(context.Background()); err != nil {
+		log.Printf("Unable to export traces: %v", err)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context) error {
+	// Execution pods run the agent's helper commands from images that do not ship it.
+	switch flag.Arg(0) {
+	case "install":
+		if err := agent.Install(flag.Arg(1)); err != nil {
+			return fmt.Errorf("agent install failed: %w", err)
+		}
+		return nil
+	case "policy-check":
+		if err := agent.CheckPolicies(ctx, flag.Arg(1)); err != nil {
+			return fmt.Errorf("policy check failed: %w", err)
+		}
+		return nil
+	}
+
 	name := os.Getenv("TOFU_EXECUTION_NAME")
 	namespace := os.Getenv("TOFU_EXECUTION_NAMESPACE")
 	if name == "" || namespace == "" {
-		log.Fatal("Missing TOFU_EXECUTION_NAME or TOFU_EXECUTION_NAMESPACE")
+		return errors.New("missing TOFU_EXECUTION_NAME or TOFU_EXECUTION_NAMESPACE")
 	}
 
-	if err := agent.Run(name, namespace); err != nil {
-		log.Fatalf("Agent run failed: %v", err)
+	if err := agent.Run(ctx, name, namespace); err != nil {
+		return fmt.Errorf("agent run failed: %w", err)
 	}
+	return nil
 }
@@ -17,10 +17,12 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -35,13 +37,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	opentofuv1beta1 "github.com/soyplane-io/soyplane/api/opentofu/v1beta1"
 	opentofucontroller "github.com/soyplane-io/soyplane/internal/controller/opentofu"
+	"github.com/soyplane-io/soyplane/internal/notification"
+	"github.com/soyplane-io/soyplane/internal/providermirror"
 	settings "github.com/soyplane-io/soyplane/internal/settings"
+	"github.com/soyplane-io/soyplane/internal/tracing"
+	webhookopentofuv1alpha1 "github.com/soyplane-io/soyplane/internal/webhook/opentofu/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -54,6 +62,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(opentofuv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(opentofuv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -232,27 +241,140 @@ func main() {
 		}
 	}
 	settings.ConfigureEvents(mgr.GetEventRecorderFor("settings"), eventRef)
+
+	mirrorCfg, err := settings.ProviderMirror()
+	if err != nil {
+		setupLog.Error(err, "failed to read provider mirror settings")
+		os.Exit(1)
+	}
+	if mirrorCfg.Enabled {
+		setupLog.Info("Initializing provider mirror", "dir", mirrorCfg.Dir, "cert-dir", mirrorCfg.CertDir)
+		mirrorCertWatcher, err := certwatcher.New(
+			filepath.Join(mirrorCfg.CertDir, "tls.crt"),
+			filepath.Join(mirrorCfg.CertDir, "tls.key"),
+		)
+		if err != nil {
+			setupLog.Error(err, "Failed to initialize provider mirror certificate watcher")
+			os.Exit(1)
+		}
+		if err := mgr.Add(mirrorCertWatcher); err != nil {
+			setupLog.Error(err, "unable to add provider mirror certificate watcher to manager")
+			os.Exit(1)
+		}
+		mirrorTLSOpts := append(slices.Clone(tlsOpts), func(config *tls.Config) {
+			config.GetCertificate = mirrorCertWatcher.GetCertificate
+		})
+		if err := mgr.Add(&providermirror.Server{
+			BindAddress: mirrorCfg.BindAddress,
+			Handler:     providermirror.NewHandler(mirrorCfg.Dir),
+			TLSOpts:     mirrorTLSOpts,
+		}); err != nil {
+			setupLog.Error(err, "unable to add provider mirror to manager")
+			os.Exit(1)
+		}
+	}
+
+	tracingCfg, err := settings.Tracing()
+	if err != nil {
+		setupLog.Error(err, "failed to read tracing settings")
+		os.Exit(1)
+	}
+	if tracingCfg.Enabled {
+		setupLog.Info("Exporting execution traces", "endpoint", tracingCfg.Endpoint, "sample-ratio", tracingCfg.SampleRatio)
+		shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+			ServiceName: "soyplane-manager",
+			Endpoint:    tracingCfg.Endpoint,
+			Insecure:    tracingCfg.Insecure,
+			SampleRatio: tracingCfg.SampleRatio,
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to set up tracing")
+			os.Exit(1)
+		}
+		// Flush the remaining spans when the manager stops.
+		if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			<-ctx.Done()
+			return shutdownTracing(context.Background())
+		})); err != nil {
+			setupLog.Error(err, "unable to add tracing shutdown to manager")
+			os.Exit(1)
+		}
+	}
+
+	// Notifications are sent for the Events the execution and module controllers record.
+	notifications := notification.NewDispatcher(mgr.GetClient(), mgr.GetAPIReader())
+	if err := mgr.Add(notifications); err != nil {
+		setupLog.Error(err, "unable to add notification dispatcher to manager")
+		os.Exit(1)
+	}
+
 	if err = (&opentofucontroller.TofuExecutionReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Recorder:  notifications.Recorder(mgr.GetEventRecorderFor("tofuexecution-controller")),
+		APIReader: mgr.GetAPIReader(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "TofuExecution")
 		os.Exit(1)
 	}
 	if err = (&opentofucontroller.TofuStackReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("tofustack-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "TofuStack")
 		os.Exit(1)
 	}
 	if err = (&opentofucontroller.TofuModuleReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: notifications.Recorder(mgr.GetEventRecorderFor("tofumodule-controller")),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "TofuModule")
 		os.Exit(1)
 	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhookopentofuv1alpha1.SetupTofuModuleWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "TofuModule")
+			os.Exit(1)
+		}
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhookopentofuv1alpha1.SetupTofuExecutionWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "TofuExecution")
+			os.Exit(1)
+		}
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhookopentofuv1alpha1.SetupTofuStackWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "TofuStack")
+			os.Exit(1)
+		}
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhookopentofuv1alpha1.SetupTofuProviderWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "TofuProvider")
+			os.Exit(1)
+		}
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhookopentofuv1alpha1.SetupTofuPolicyWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "TofuPolicy")
+			os.Exit(1)
+		}
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhookopentofuv1alpha1.SetupTofuNotificationWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "TofuNotification")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
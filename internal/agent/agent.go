@@ -3,6 +3,9 @@ package agent
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
@@ -18,7 +21,7 @@ func init() {
 	utilruntime.Must(opentofuv1alpha1.AddToScheme(scheme))
 }
 
-func Run(name, namespace string) error {
+func Run(ctx context.Context, name, namespace string) error {
 	log := ctrl.Log.WithName("agent")
 	cfg := ctrl.GetConfigOrDie()
 
@@ -27,15 +30,23 @@ func Run(name, namespace string) error {
 		return fmt.Errorf("failed to create k8s client: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// The pod receives SIGTERM when the Job is suspended (cancellation) or exceeds its deadline.
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	fetchCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	exec, err := FetchTofuExecution(ctx, cl, name, namespace)
+	exec, err := FetchTofuExecution(fetchCtx, cl, name, namespace)
 	if err != nil {
 		return fmt.Errorf("failed to fetch execution: %w", err)
 	}
 
-	module, err := FetchTofuModule(ctx, cl, exec.Spec.ModuleRef.Name, exec.Spec.ModuleRef.Namespace)
+	moduleNamespace := exec.Spec.ModuleRef.Namespace
+	if moduleNamespace == "" {
+		moduleNamespace = exec.Namespace
+	}
+	module, err := FetchTofuModule(fetchCtx, cl, exec.Spec.ModuleRef.Name, moduleNamespace)
 	if err != nil {
 		return fmt.Errorf("failed to fetch execution: %w", err)
 	}
@@ -0,0 +1,63 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// tenvCommands maps engine names to tenv subcommands.
+var tenvCommands = map[string]string{
+	"tofu":      "tofu",
+	"terraform": "tf",
+}
+
+// tenvVersionVars maps engine names to the variable selecting the version tenv's engine proxy runs.
+var tenvVersionVars = map[string]string{
+	"tofu":      "TOFUENV_TOFU_VERSION",
+	"terraform": "TFENV_TERRAFORM_VERSION",
+}
+
+// engineVersion is the version to install. "latest" and an empty version become
+// "latest-allowed", which tenv resolves against the module's required_version.
+func (r *Runner) engineVersion() string {
+	version := strings.TrimSpace(r.exec.Spec.Engine.Version)
+	if version == "" || version == "latest" {
+		return "latest-allowed"
+	}
+	return version
+}
+
+// installEngine installs the requested engine version with tenv, which downloads it into its
+// cache (TENV_ROOT, or a mirror configured through TOFUENV_REMOTE/TFENV_REMOTE) and verifies
+// checksums and signatures. Images shipping a fixed engine have no tenv and are used as is.
+func (r *Runner) installEngine(ctx context.Context, dir string) error {
+	if _, err := exec.LookPath("tenv"); err != nil {
+		r.logger.Info("tenv not found; using the engine shipped with the image")
+		return nil
+	}
+	version := r.engineVersion()
+	r.logger.Info("Installing engine", "engine", r.engineName(), "version", version)
+
+	cmd := exec.CommandContext(ctx, "tenv", tenvCommands[r.engineName()], "install", version)
+	// tenv reads required_version from the module in the working directory.
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = r.engineEnv()
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("tenv %s install %s: %w", tenvCommands[r.engineName()], version, err)
+	}
+	return nil
+}
+
+// engineEnv is the environment of engine and tenv commands. It pins the version so that tenv's
+// engine proxy runs the version installed by installEngine.
+func (r *Runner) engineEnv() []string {
+	return append(os.Environ(),
+		tenvVersionVars[r.engineName()]+"="+r.engineVersion(),
+		"TENV_AUTO_INSTALL=true",
+	)
+}
@@ -0,0 +1,101 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrLockfileMismatch is returned when an apply resolved a different dependency lock file than
+// the plan it follows.
+var ErrLockfileMismatch = errors.New("dependency lock file differs from the plan's")
+
+const (
+	lockfileName = ".terraform.lock.hcl"
+	// terminationLog is read by the controller to record the lock file in the execution status.
+	terminationLog        = "/dev/termination-log"
+	maxTerminationMessage = 4096
+	// maxLockfileReport leaves room in the termination message for policy results.
+	maxLockfileReport = 3072
+	// phaseReportReserve leaves room for the phase timings the engine script appends on exit.
+	phaseReportReserve = 256
+	// lockfileReportReserve leaves room for the lock file digest, which the engine script
+	// reports last.
+	lockfileReportReserve = 128
+)
+
+type providerLock struct {
+	address string
+	version string
+	hashes  []string
+}
+
+// checkLockfile reports the lock file resolved by init to the controller and, for an apply
+// following a plan, fails unless it is the plan's lock file.
+func (r *Runner) checkLockfile(dir string) error {
+	content, err := os.ReadFile(filepath.Join(dir, lockfileName))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	digest := ""
+	if content != nil {
+		sum := sha256.Sum256(content)
+		digest = hex.EncodeToString(sum[:])
+		summary := lockfileSummary(digest, parseLockfile(content))
+		if err := os.WriteFile(terminationLog, []byte(summary), 0o644); err != nil {
+			r.logger.Error(err, "Unable to report the dependency lock file")
+		}
+	}
+	if expected := os.Getenv("SOYPLANE_PLAN_LOCKFILE"); expected != "" && expected != digest {
+		return fmt.Errorf("%w: %q, plan used %q", ErrLockfileMismatch, digest, expected)
+	}
+	return nil
+}
+
+// parseLockfile extracts the provider blocks of a dependency lock file.
+func parseLockfile(content []byte) []providerLock {
+	var providers []providerLock
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch {
+		case len(fields) >= 2 && fields[0] == "provider":
+			providers = append(providers, providerLock{address: strings.Trim(fields[1], `"`)})
+		case len(providers) == 0:
+			// Nothing to attach to before the first provider block.
+		case len(fields) >= 3 && fields[0] == "version":
+			providers[len(providers)-1].version = strings.Trim(fields[2], `"`)
+		case len(fields) >= 1 && strings.HasPrefix(fields[0], `"`) && strings.Contains(fields[0], ":"):
+			current := &providers[len(providers)-1]
+			current.hashes = append(current.hashes, strings.Trim(fields[0], `",`))
+		}
+	}
+	return providers
+}
+
+// lockfileSummary renders the report the controller parses, leaving hashes out when the report
+// would take more than its share of the termination message.
+func lockfileSummary(digest string, providers []providerLock) string {
+	render := func(withHashes bool) string {
+		var b strings.Builder
+		fmt.Fprintf(&b, "lockfile %s\n", digest)
+		for _, p := range providers {
+			fmt.Fprintf(&b, "provider %s %s", p.address, p.version)
+			if withHashes && len(p.hashes) > 0 {
+				fmt.Fprintf(&b, " %s", strings.Join(p.hashes, ","))
+			}
+			b.WriteString("\n")
+		}
+		return b.String()
+	}
+	if summary := render(true); len(summary) <= maxLockfileReport {
+		return summary
+	}
+	return render(false)
+}
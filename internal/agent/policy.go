@@ -0,0 +1,160 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	"github.com/soyplane-io/soyplane/internal/policy"
+)
+
+// ErrPolicyDenied is returned when the plan violates a policy with Deny enforcement.
+var ErrPolicyDenied = errors.New("plan denied by policy")
+
+// policiesEnvVar carries the policies selecting the execution's module, as set by the controller.
+const policiesEnvVar = "SOYPLANE_POLICIES"
+
+// CheckPolicies checks the plan JSON at planPath against the policies passed by the controller
+// and reports the results. It returns ErrPolicyDenied when a Deny policy is violated.
+func CheckPolicies(ctx context.Context, planPath string) error {
+	return traceStep(ctx, "policy-check", func(context.Context) error {
+		planJSON, err := os.ReadFile(planPath)
+		if err != nil {
+			return err
+		}
+		results, err := checkPolicies(planJSON)
+		if err != nil {
+			return err
+		}
+		if policy.Denied(results) {
+			return ErrPolicyDenied
+		}
+		return nil
+	})
+}
+
+func checkPolicies(planJSON []byte) ([]opentofuv1alpha1.PolicyResult, error) {
+	var policies []policy.Policy
+	if err := json.Unmarshal([]byte(os.Getenv(policiesEnvVar)), &policies); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", policiesEnvVar, err)
+	}
+	results, err := policy.Evaluate(policies, planJSON)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		line := fmt.Sprintf("%s %s/%s: %s", result.Outcome, result.Policy, result.Rule, result.Enforcement)
+		if result.Message != "" {
+			line += " - " + result.Message
+		}
+		fmt.Fprintln(os.Stderr, line)
+	}
+	reportPolicyResults(results)
+	return results, nil
+}
+
+// reportPolicyResults appends the violations to the termination message, leaving room for the
+// reports the engine script writes on exit. The controller derives passing rules from the policies it handed to the execution,
+// unless the violations did not fit and the report is marked truncated.
+func reportPolicyResults(results []opentofuv1alpha1.PolicyResult) {
+	const header, truncated = "policies evaluated\n", "policies truncated\n"
+	budget := maxTerminationMessage - len(header) - len(truncated) - phaseReportReserve - lockfileReportReserve
+	if info, err := os.Stat(terminationLog); err == nil {
+		budget -= int(info.Size())
+	}
+	if budget < 0 {
+		return
+	}
+	var b strings.Builder
+	b.WriteString(header)
+	for _, result := range results {
+		if result.Outcome == opentofuv1alpha1.PolicyPass {
+			continue
+		}
+		line := fmt.Sprintf("policy %s %s %s\n", result.Policy, result.Rule, result.Outcome)
+		if b.Len()-len(header)+len(line) > budget {
+			b.WriteString(truncated)
+			break
+		}
+		b.WriteString(line)
+	}
+	f, err := os.OpenFile(terminationLog, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return
+	}
+	defer f.Close() //nolint:errcheck
+	_, _ = io.WriteString(f, b.String())
+}
+
+// runWithPolicies plans to a file, checks the plan against the policies and, for applies,
+// applies exactly the checked plan.
+func (r *Runner) runWithPolicies(ctx context.Context, dir string) error {
+	planFile := filepath.Join(os.TempDir(), "soyplane.tfplan")
+	var planJSON []byte
+	err := traceStep(ctx, "plan", func(ctx context.Context) error {
+		if err := r.runEngine(ctx, dir, "plan", "-input=false", "-out="+planFile); err != nil {
+			return fmt.Errorf("plan failed: %w", err)
+		}
+		show := exec.CommandContext(ctx, r.engineName(), "show", "-json", planFile)
+		show.Dir = dir
+		show.Stderr = os.Stderr
+		show.Env = r.engineEnv()
+		var err error
+		if planJSON, err = show.Output(); err != nil {
+			return fmt.Errorf("show plan failed: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	var results []opentofuv1alpha1.PolicyResult
+	if err := traceStep(ctx, "policy-check", func(context.Context) error {
+		results, err = checkPolicies(planJSON)
+		return err
+	}); err != nil {
+		return err
+	}
+	if r.exec.Spec.Action != "apply" {
+		return nil
+	}
+	if policy.Denied(results) {
+		return ErrPolicyDenied
+	}
+	return traceStep(ctx, "apply", func(ctx context.Context) error {
+		if err := r.runEngine(ctx, dir, "apply", "-input=false", planFile); err != nil {
+			return fmt.Errorf("apply failed: %w", err)
+		}
+		return nil
+	})
+}
+
+// Install copies the running agent binary into dir, so that execution images without the agent
+// can run it from a shared volume.
+func Install(dir string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	src, err := os.Open(self)
+	if err != nil {
+		return err
+	}
+	defer src.Close() //nolint:errcheck
+	dst, err := os.OpenFile(filepath.Join(dir, "agent"), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	return dst.Close()
+}
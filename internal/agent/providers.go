@@ -0,0 +1,22 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+)
+
+// prepareProviderInstallation writes the CLI configuration passed by the controller, which
+// points the engine at the provider mirror, and creates the plugin cache directory.
+func prepareProviderInstallation() error {
+	if config, path := os.Getenv("SOYPLANE_CLI_CONFIG"), os.Getenv("TF_CLI_CONFIG_FILE"); config != "" && path != "" {
+		if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+			return fmt.Errorf("failed to write CLI configuration: %w", err)
+		}
+	}
+	if dir := os.Getenv("TF_PLUGIN_CACHE_DIR"); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create plugin cache directory: %w", err)
+		}
+	}
+	return nil
+}
@@ -9,7 +9,10 @@ import (
 	"path"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/codes"
+
 	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	"github.com/soyplane-io/soyplane/internal/tracing"
 )
 
 type Runner struct {
@@ -27,15 +30,32 @@ func (r *Runner) Execute(ctx context.Context) error {
 
 	clonePath := "/tmp/tf-module" // or mount an emptyDir
 
-	if err := cloneGitModule(r.module.Spec.Source, r.module.Spec.Version, clonePath); err != nil {
-		return fmt.Errorf("module checkout failed: %w", err)
+	if err := traceStep(ctx, "clone", func(context.Context) error {
+		if err := cloneGitModule(r.module.Spec.Source, r.module.Spec.Version, clonePath); err != nil {
+			return fmt.Errorf("module checkout failed: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return err
 	}
 	modulePath := path.Join(clonePath, r.module.Spec.Workdir)
 	r.logger.Info("Module cloned", "path", modulePath)
+
+	if err := traceStep(ctx, "init", func(ctx context.Context) error {
+		return r.initModule(ctx, modulePath)
+	}); err != nil {
+		return err
+	}
+	if os.Getenv(policiesEnvVar) != "" {
+		if err := r.runWithPolicies(ctx, modulePath); err != nil {
+			return err
+		}
+	} else if err := traceStep(ctx, r.exec.Spec.Action, func(ctx context.Context) error {
+		return r.runEngine(ctx, modulePath, r.exec.Spec.Action, "-input=false")
+	}); err != nil {
+		return fmt.Errorf("%s failed: %w", r.exec.Spec.Action, err)
+	}
 	// TODO:
-	// - Checkout module
-	// - Init
-	// - Plan
 	// - Apply (if autoApply or approved)
 	// - Parse outputs
 	// - Call output exporter
@@ -44,6 +64,77 @@ func (r *Runner) Execute(ctx context.Context) error {
 	return nil
 }
 
+// initModule installs the engine, initializes the module in dir, selects the workspace and checks
+// the resolved dependency lock file.
+func (r *Runner) initModule(ctx context.Context, dir string) error {
+	if err := r.installEngine(ctx, dir); err != nil {
+		return fmt.Errorf("engine installation failed: %w", err)
+	}
+	if err := prepareProviderInstallation(); err != nil {
+		return err
+	}
+	initArgs := []string{"init", "-input=false"}
+	if r.exec.Spec.LockfileReadonly {
+		initArgs = append(initArgs, "-lockfile=readonly")
+	}
+	if err := r.runEngine(ctx, dir, initArgs...); err != nil {
+		return fmt.Errorf("init failed: %w", err)
+	}
+	if workspace := r.exec.Spec.Workspace; workspace != "" {
+		if err := r.runEngine(ctx, dir, "workspace", "select", "-or-create", workspace); err != nil {
+			return fmt.Errorf("workspace selection failed: %w", err)
+		}
+	}
+	return r.checkLockfile(dir)
+}
+
+// traceStep runs fn in a span named after the execution phase it performs.
+func traceStep(ctx context.Context, name string, fn func(context.Context) error) error {
+	ctx, span := tracing.Tracer().Start(ctx, name)
+	defer span.End()
+	err := fn(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+func (r *Runner) engineName() string {
+	if r.exec.Spec.Engine.Name != "" {
+		return r.exec.Spec.Engine.Name
+	}
+	return opentofuv1alpha1.DefaultEngine
+}
+
+// runEngine runs the engine with args in dir. When ctx is cancelled the engine receives SIGINT,
+// which makes it stop gracefully and release the state lock. It is not killed here: the pod's
+// termination grace period, set by the controller, bounds how long it may take.
+func (r *Runner) runEngine(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.Command(r.engineName(), args...)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = r.engineEnv()
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+
+	r.logger.Info("Interrupting engine", "reason", context.Cause(ctx))
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		r.logger.Error(err, "Failed to interrupt engine")
+	}
+	<-done
+	return context.Cause(ctx)
+}
+
 func cloneGitModule(source string, version string, workDir string) error {
 	if err := os.MkdirAll(workDir, 0755); err != nil {
 		return fmt.Errorf("failed to create working directory: %w", err)
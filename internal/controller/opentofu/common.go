@@ -16,14 +16,84 @@ limitations under the License.
 
 package opentofu
 
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	"github.com/soyplane-io/soyplane/internal/modulegrant"
+)
+
 const (
 	moduleGenerationAnnotation = "opentofu.soyplane.io/module-generation"
 	stackGenerationAnnotation  = "opentofu.soyplane.io/stack-generation"
+	stackInstanceLabel         = "opentofu.soyplane.io/stack-instance"
+
+	// workspaceEnvVar carries the selected workspace into the execution Job. TF_WORKSPACE is
+	// avoided on purpose: the engines refuse to switch workspaces while it is set.
+	workspaceEnvVar = "SOYPLANE_WORKSPACE"
+
+	// moduleRefEnvVar carries the module's version into the execution Job, which checks it out
+	// after cloning the source.
+	moduleRefEnvVar = "SOYPLANE_MODULE_REF"
+
+	// engineContainerName names the container running the engine in execution pods.
+	engineContainerName = "engine"
+	// reservedEnvPrefix prefixes the variables the controller passes to execution pods, which job
+	// templates must not override.
+	reservedEnvPrefix = "SOYPLANE_"
+
+	// planChangesReport prefixes the termination message line recording whether a plan has
+	// changes.
+	planChangesReport = "changes"
+
+	// plannedResourcesReport prefixes the termination message line counting the resources a
+	// plan adds, changes and destroys.
+	plannedResourcesReport = "resources"
+
+	// planFile is where executions save their plan.
+	planFile = "/tmp/soyplane.tfplan"
+
+	// engineGracePeriod is how long an interrupted engine gets to release its state lock before
+	// the execution pod is killed.
+	engineGracePeriod = 2 * time.Minute
 )
 
+// Standard condition types reported by parent resources.
+const (
+	conditionReady       = "Ready"
+	conditionProgressing = "Progressing"
+	conditionDegraded    = "Degraded"
+
+	// reasonReferenceNotPermitted reports a cross-namespace module reference no TofuModuleGrant
+	// permits.
+	reasonReferenceNotPermitted = "ReferenceNotPermitted"
+)
+
+// moduleKey returns the module the execution runs. Executions admitted without the defaulting
+// webhook may leave the module namespace empty, meaning their own.
+func moduleKey(execution *opentofuv1alpha1.TofuExecution) types.NamespacedName {
+	return modulegrant.ModuleKey(execution.Spec.ModuleRef, execution.Namespace)
+}
+
+// executionEngine returns the name of the engine the execution runs.
+func executionEngine(execution *opentofuv1alpha1.TofuExecution) string {
+	if execution.Spec.Engine.Name == "" {
+		return opentofuv1alpha1.DefaultEngine
+	}
+	return execution.Spec.Engine.Name
+}
+
 func isExecutionTerminal(phase string) bool {
+	return phase == "Succeeded" || isExecutionFailed(phase)
+}
+
+// isExecutionFailed reports whether the execution finished without succeeding, including
+// cancelled and timed out runs.
+func isExecutionFailed(phase string) bool {
 	switch phase {
-	case "Succeeded", "Failed":
+	case "Failed", "Cancelled", "TimedOut":
 		return true
 	default:
 		return false
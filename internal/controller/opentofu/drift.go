@@ -0,0 +1,41 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentofu
+
+import (
+	"k8s.io/utils/ptr"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+// driftCheckLabel marks the plans checking a module for drift.
+const driftCheckLabel = "opentofu.soyplane.io/drift-check"
+
+// detectedDrift returns the newest finished plan when it is a drift check that found changes.
+// executions must be sorted newest first.
+func detectedDrift(executions []*opentofuv1alpha1.TofuExecution) *opentofuv1alpha1.TofuExecution {
+	for _, exec := range executions {
+		if exec.Spec.Action != "plan" || !isExecutionTerminal(exec.Status.Phase) {
+			continue
+		}
+		if exec.Labels[driftCheckLabel] == "true" && ptr.Deref(exec.Status.HasChanges, false) {
+			return exec
+		}
+		return nil
+	}
+	return nil
+}
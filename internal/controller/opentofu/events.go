@@ -0,0 +1,109 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentofu
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+// Reasons of the Events recorded on modules, stacks and executions. Execution outcomes are
+// recorded as <Action>Succeeded and <Action>Failed, e.g. PlanSucceeded or ApplyFailed.
+const (
+	eventExecutionCreated = "ExecutionCreated"
+	eventRetrying         = "Retrying"
+	eventDriftDetected    = "DriftDetected"
+	eventJobCreated       = "JobCreated"
+	eventStarted          = "Started"
+	eventQueued           = "Queued"
+	eventRejected         = "Rejected"
+	eventCancelled        = "Cancelled"
+	eventTimedOut         = "TimedOut"
+	eventPlanHasChanges   = "PlanHasChanges"
+	eventApprovalRequired = "ApprovalRequired"
+
+	eventPullRequestCommented     = "PullRequestCommented"
+	eventPullRequestCommentFailed = "PullRequestCommentFailed"
+)
+
+// recordExecutionCreated records on parent that it created execution.
+func recordExecutionCreated(recorder record.EventRecorder, parent client.Object, execution *opentofuv1alpha1.TofuExecution, why string) {
+	recorder.Eventf(parent, corev1.EventTypeNormal, eventExecutionCreated, "Created %s execution %s %s", execution.Spec.Action, execution.Name, why)
+}
+
+// awaitingApproval returns the newest finished plan of the module, drift checks included, when it
+// succeeded with changes and none of namespaced references it through planRef. executions must be
+// sorted newest first; namespaced are the executions in the module's namespace.
+func awaitingApproval(executions []*opentofuv1alpha1.TofuExecution, namespaced []opentofuv1alpha1.TofuExecution) *opentofuv1alpha1.TofuExecution {
+	for _, exec := range executions {
+		if exec.Spec.Action != "plan" || !isExecutionTerminal(exec.Status.Phase) {
+			continue
+		}
+		if exec.Status.Phase != "Succeeded" || !ptr.Deref(exec.Status.HasChanges, false) {
+			return nil
+		}
+		for i := range namespaced {
+			if namespaced[i].Spec.PlanRef == exec.Name {
+				return nil
+			}
+		}
+		return exec
+	}
+	return nil
+}
+
+// actionTitle capitalizes an execution action for event reasons and messages.
+func actionTitle(action string) string {
+	if action == "" {
+		return action
+	}
+	return strings.ToUpper(action[:1]) + action[1:]
+}
+
+// recordExecutionOutcome records how a finished execution ended, and whether a successful plan
+// has changes along with the planned resource counts when the engine reported them.
+func recordExecutionOutcome(recorder record.EventRecorder, execution *opentofuv1alpha1.TofuExecution, planned *plannedResources) {
+	action := actionTitle(execution.Spec.Action)
+	switch execution.Status.Phase {
+	case "Succeeded":
+		recorder.Eventf(execution, corev1.EventTypeNormal, action+"Succeeded", "%s succeeded", action)
+		if ptr.Deref(execution.Status.HasChanges, false) {
+			message := "Plan has changes to apply"
+			if planned != nil {
+				message += fmt.Sprintf(": %d to add, %d to change, %d to destroy", planned.add, planned.change, planned.destroy)
+			}
+			recorder.Event(execution, corev1.EventTypeNormal, eventPlanHasChanges, message)
+		}
+	case "Failed":
+		message := action + " failed"
+		if execution.Status.FailureReason != "" {
+			message += ": " + string(execution.Status.FailureReason)
+		}
+		recorder.Event(execution, corev1.EventTypeWarning, action+"Failed", message)
+	case "TimedOut":
+		recorder.Eventf(execution, corev1.EventTypeWarning, eventTimedOut, "%s", execution.Status.Summary)
+	case "Cancelled":
+		recorder.Eventf(execution, corev1.EventTypeNormal, eventCancelled, "%s", execution.Status.Summary)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentofu
+
+import (
+	"context"
+	"fmt"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	settings "github.com/soyplane-io/soyplane/internal/settings"
+)
+
+// resolveHistoryLimits applies the settings defaults to the limits declared on a module or stack.
+func resolveHistoryLimits(limits *opentofuv1alpha1.HistoryLimits) (successful, failed int, err error) {
+	execCfg, err := settings.Execution()
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid settings: %w", err)
+	}
+	successful, failed = execCfg.SuccessfulHistoryLimit, execCfg.FailedHistoryLimit
+	if limits != nil && limits.Successful != nil {
+		successful = int(*limits.Successful)
+	}
+	if limits != nil && limits.Failed != nil {
+		failed = int(*limits.Failed)
+	}
+	return successful, failed, nil
+}
+
+// pendingPlanRefs returns the names of the plans that executions which have not finished yet
+// reference through planRef.
+func pendingPlanRefs(executions []opentofuv1alpha1.TofuExecution) map[string]bool {
+	refs := make(map[string]bool)
+	for i := range executions {
+		if ref := executions[i].Spec.PlanRef; ref != "" && !isExecutionTerminal(executions[i].Status.Phase) {
+			refs[ref] = true
+		}
+	}
+	return refs
+}
+
+// executionsToPrune selects the finished executions exceeding the history limits, oldest first
+// to go. executions must be sorted newest first. The newest execution of every stack instance
+// (or of the parent when it has no instances), the execution behind lastApply and the plans
+// named in planRefs are always kept, as are executions that have not finished.
+func executionsToPrune(executions []*opentofuv1alpha1.TofuExecution, successful, failed int, lastApply *opentofuv1alpha1.ExecutionSummary, planRefs map[string]bool) []*opentofuv1alpha1.TofuExecution {
+	newest := make(map[string]bool)
+	keptSucceeded, keptFailed := 0, 0
+	var prune []*opentofuv1alpha1.TofuExecution
+	for _, exec := range executions {
+		instance := exec.Labels[stackInstanceLabel]
+		protected := !newest[instance] ||
+			(lastApply != nil && lastApply.JobName != "" && exec.Status.JobName == lastApply.JobName) ||
+			(exec.Spec.Action == "plan" && planRefs[exec.Name])
+		newest[instance] = true
+
+		switch {
+		case exec.Status.Phase == "Succeeded":
+			keptSucceeded++
+			if keptSucceeded > successful && !protected {
+				prune = append(prune, exec)
+			}
+		case isExecutionFailed(exec.Status.Phase):
+			keptFailed++
+			if keptFailed > failed && !protected {
+				prune = append(prune, exec)
+			}
+		}
+	}
+	return prune
+}
+
+// pruneExecutionHistory deletes the executions exceeding the history limits, keeping the plans
+// that applies of the namespace still wait for. Deletion cascades to everything the execution
+// owns, such as its Job and pods.
+func pruneExecutionHistory(ctx context.Context, c client.Client, executions []*opentofuv1alpha1.TofuExecution, limits *opentofuv1alpha1.HistoryLimits, lastApply *opentofuv1alpha1.ExecutionSummary) error {
+	log := logf.FromContext(ctx)
+	if len(executions) == 0 {
+		return nil
+	}
+	successful, failed, err := resolveHistoryLimits(limits)
+	if err != nil {
+		return err
+	}
+	var namespaced opentofuv1alpha1.TofuExecutionList
+	if err := c.List(ctx, &namespaced, client.InNamespace(executions[0].Namespace)); err != nil {
+		return err
+	}
+	for _, exec := range executionsToPrune(executions, successful, failed, lastApply, pendingPlanRefs(namespaced.Items)) {
+		if err := c.Delete(ctx, exec, client.PropagationPolicy("Background")); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+		log.Info("Pruned TofuExecution from history", "execution", exec.Name, "phase", exec.Status.Phase)
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentofu
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	settings "github.com/soyplane-io/soyplane/internal/settings"
+)
+
+const (
+	// executionServiceAccountAnnotation names the service account the executions of a namespace
+	// must run as, overriding the execution.identity.serviceAccountName setting.
+	executionServiceAccountAnnotation = "opentofu.soyplane.io/execution-service-account"
+
+	// managedByLabel marks the service accounts and output targets created by the controller.
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedBy      = "soyplane"
+
+	// executionAccessLabel carries the UID of the execution an agent Role grants access for.
+	executionAccessLabel = "opentofu.soyplane.io/execution"
+
+	// executionAccessFinalizer marks an execution whose grants are not revoked yet, and holds back
+	// its deletion until they are: grants in another namespace cannot be owned by the execution.
+	executionAccessFinalizer = "opentofu.soyplane.io/execution-access"
+)
+
+// executionServiceAccount returns the service account the executions of namespace must run as,
+// or an empty string when job templates may pick one.
+func (r *TofuExecutionReconciler) executionServiceAccount(ctx context.Context, namespace string, cfg settings.IdentitySettings) (string, error) {
+	var ns corev1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return "", err
+		}
+	} else if name := ns.Annotations[executionServiceAccountAnnotation]; name != "" {
+		return name, nil
+	}
+	return cfg.ServiceAccountName, nil
+}
+
+// serviceAccountAnnotations renders the configured annotations for a service account.
+func serviceAccountAnnotations(cfg settings.IdentitySettings, namespace, name string) (map[string]string, error) {
+	annotations := make(map[string]string, len(cfg.ServiceAccountAnnotations))
+	for _, annotation := range cfg.ServiceAccountAnnotations {
+		tmpl, err := template.New("annotation").Option("missingkey=error").Parse(annotation.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service account annotation %s: %w", annotation.Name, err)
+		}
+		var value strings.Builder
+		if err := tmpl.Execute(&value, struct{ Namespace, ServiceAccount string }{namespace, name}); err != nil {
+			return nil, fmt.Errorf("invalid service account annotation %s: %w", annotation.Name, err)
+		}
+		annotations[annotation.Name] = value.String()
+	}
+	return annotations, nil
+}
+
+// ensureServiceAccount creates the mandatory service account of a namespace, or refreshes the
+// annotations of one the controller created before. Service accounts created by others are
+// left alone.
+func (r *TofuExecutionReconciler) ensureServiceAccount(ctx context.Context, key types.NamespacedName, cfg settings.IdentitySettings) error {
+	annotations, err := serviceAccountAnnotations(cfg, key.Namespace, key.Name)
+	if err != nil {
+		return err
+	}
+	var account corev1.ServiceAccount
+	if err := r.Get(ctx, key, &account); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+		account = corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+			Name:        key.Name,
+			Namespace:   key.Namespace,
+			Labels:      map[string]string{managedByLabel: managedBy},
+			Annotations: annotations,
+		}}
+		if err := r.Create(ctx, &account); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return err
+		}
+		logf.FromContext(ctx).Info("Created execution service account", "serviceAccount", key.Name)
+		return nil
+	}
+	if account.Labels[managedByLabel] != managedBy {
+		return nil
+	}
+	updated := maps.Clone(account.Annotations)
+	if updated == nil {
+		updated = make(map[string]string, len(annotations))
+	}
+	maps.Copy(updated, annotations)
+	if maps.Equal(updated, account.Annotations) {
+		return nil
+	}
+	account.Annotations = updated
+	return r.Update(ctx, &account)
+}
+
+// executionAccessRoles returns the Roles letting the agent of execution read the execution and
+// its module and write the module's output targets. A module of another namespace is read
+// through a second Role in that namespace. The Roles are named after the execution's UID, which
+// unlike its name fits in a Role name and is unique across namespaces.
+func executionAccessRoles(execution *opentofuv1alpha1.TofuExecution, module *opentofuv1alpha1.TofuModule) []rbacv1.Role {
+	name := "soyplane-agent-" + string(execution.UID)
+	role := rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: execution.Namespace},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups:     []string{opentofuv1alpha1.GroupVersion.Group},
+			Resources:     []string{"tofuexecutions"},
+			ResourceNames: []string{execution.Name},
+			Verbs:         []string{"get"},
+		}},
+	}
+	moduleRule := rbacv1.PolicyRule{
+		APIGroups:     []string{opentofuv1alpha1.GroupVersion.Group},
+		Resources:     []string{"tofumodules"},
+		ResourceNames: []string{module.Name},
+		Verbs:         []string{"get"},
+	}
+	var roles []rbacv1.Role
+	if module.Namespace == execution.Namespace {
+		role.Rules = append(role.Rules, moduleRule)
+	} else {
+		roles = append(roles, rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: module.Namespace},
+			Rules:      []rbacv1.PolicyRule{moduleRule},
+		})
+	}
+
+	targets := map[string][]string{}
+	for _, output := range module.Spec.Outputs {
+		for _, target := range output.To {
+			resource := "configmaps"
+			if target.Kind == "Secret" {
+				resource = "secrets"
+			}
+			if !slices.Contains(targets[resource], target.Name) {
+				targets[resource] = append(targets[resource], target.Name)
+			}
+		}
+	}
+	for _, resource := range slices.Sorted(maps.Keys(targets)) {
+		// Create is not granted: it cannot be limited to names, and creating Secrets would let the
+		// agent mint tokens of other service accounts. The controller creates the targets instead.
+		role.Rules = append(role.Rules, rbacv1.PolicyRule{
+			APIGroups:     []string{""},
+			Resources:     []string{resource},
+			ResourceNames: targets[resource],
+			Verbs:         []string{"get", "update", "patch"},
+		})
+	}
+
+	roles = append([]rbacv1.Role{role}, roles...)
+	for i := range roles {
+		roles[i].Labels = map[string]string{executionAccessLabel: string(execution.UID)}
+	}
+	return roles
+}
+
+// grantExecutionAccess prepares the identity the Job of execution runs as: it creates the
+// namespace's mandatory service account and, with managed RBAC, the agent's Roles and
+// RoleBindings and missing output targets. The grants are revoked by revokeExecutionAccess.
+func (r *TofuExecutionReconciler) grantExecutionAccess(ctx context.Context, execution *opentofuv1alpha1.TofuExecution, pod *corev1.PodSpec) error {
+	execCfg, err := settings.Execution()
+	if err != nil {
+		return fmt.Errorf("invalid settings: %w", err)
+	}
+	cfg := execCfg.Identity
+	serviceAccount := pod.ServiceAccountName
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+	mandatory, err := r.executionServiceAccount(ctx, execution.Namespace, cfg)
+	if err != nil {
+		return err
+	}
+	if mandatory != "" && cfg.CreateServiceAccount {
+		if err := r.ensureServiceAccount(ctx, types.NamespacedName{Namespace: execution.Namespace, Name: mandatory}, cfg); err != nil {
+			return err
+		}
+	}
+	if !cfg.ManageRBAC {
+		return nil
+	}
+
+	module := opentofuv1alpha1.TofuModule{}
+	if err := r.Get(ctx, moduleKey(execution), &module); err != nil {
+		return err
+	}
+	if err := r.ensureOutputTargets(ctx, execution.Namespace, &module); err != nil {
+		return err
+	}
+	return r.ensureExecutionRoles(ctx, execution, executionAccessRoles(execution, &module), serviceAccount)
+}
+
+// ensureExecutionRoles creates the Roles and RoleBindings granting serviceAccount the access of
+// roles. Grants left by an earlier attempt are updated, as the module may have changed since.
+// The execution gets executionAccessFinalizer first, so that revokeExecutionAccess finds them.
+func (r *TofuExecutionReconciler) ensureExecutionRoles(ctx context.Context, execution *opentofuv1alpha1.TofuExecution, roles []rbacv1.Role, serviceAccount string) error {
+	if controllerutil.AddFinalizer(execution, executionAccessFinalizer) {
+		if err := r.Update(ctx, execution); err != nil {
+			return err
+		}
+	}
+	for _, role := range roles {
+		binding := &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: role.Name, Namespace: role.Namespace, Labels: role.Labels},
+			Subjects: []rbacv1.Subject{{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      serviceAccount,
+				Namespace: execution.Namespace,
+			}},
+			RoleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: role.Name},
+		}
+		if role.Namespace == execution.Namespace {
+			// Owned grants go away with the execution even if its finalizer is removed by hand.
+			if err := ctrl.SetControllerReference(execution, &role, r.Scheme); err != nil {
+				return err
+			}
+			if err := ctrl.SetControllerReference(execution, binding, r.Scheme); err != nil {
+				return err
+			}
+		}
+		for _, object := range []client.Object{&role, binding} {
+			err := r.Create(ctx, object)
+			if k8serrors.IsAlreadyExists(err) {
+				err = r.Patch(ctx, object, client.Merge)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ensureOutputTargets creates the missing Secrets and ConfigMaps the module writes its outputs
+// to, empty, so that the agent only needs to update them.
+func (r *TofuExecutionReconciler) ensureOutputTargets(ctx context.Context, namespace string, module *opentofuv1alpha1.TofuModule) error {
+	for _, output := range module.Spec.Outputs {
+		for _, target := range output.To {
+			meta := metav1.ObjectMeta{Name: target.Name, Namespace: namespace, Labels: map[string]string{managedByLabel: managedBy}}
+			var object client.Object = &corev1.ConfigMap{ObjectMeta: meta}
+			if target.Kind == "Secret" {
+				object = &corev1.Secret{ObjectMeta: meta}
+			}
+			if err := r.Create(ctx, object); err != nil && !k8serrors.IsAlreadyExists(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// revokeExecutionAccess deletes the Roles and RoleBindings granted to the agent of a finished
+// or deleted execution, and then releases the execution's finalizer. Executions without the
+// finalizer have nothing left to revoke.
+func (r *TofuExecutionReconciler) revokeExecutionAccess(ctx context.Context, execution *opentofuv1alpha1.TofuExecution) error {
+	if !controllerutil.ContainsFinalizer(execution, executionAccessFinalizer) {
+		return nil
+	}
+	module := &opentofuv1alpha1.TofuModule{ObjectMeta: metav1.ObjectMeta{Name: moduleKey(execution).Name, Namespace: moduleKey(execution).Namespace}}
+	for _, role := range executionAccessRoles(execution, module) {
+		meta := metav1.ObjectMeta{Name: role.Name, Namespace: role.Namespace}
+		for _, object := range []client.Object{&rbacv1.RoleBinding{ObjectMeta: meta}, &rbacv1.Role{ObjectMeta: meta}} {
+			if err := r.Delete(ctx, object); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+	}
+	controllerutil.RemoveFinalizer(execution, executionAccessFinalizer)
+	return r.Update(ctx, execution)
+}
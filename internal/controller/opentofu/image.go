@@ -0,0 +1,125 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentofu
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/go-version"
+	corev1 "k8s.io/api/core/v1"
+
+	settings "github.com/soyplane-io/soyplane/internal/settings"
+)
+
+// resolveEngineImage picks the execution image for the requested engine version. Exact
+// versions are looked up in the settings' image table. Everything else runs on the default
+// image, which installs the engine at run time (see engineInstallEnv): "latest", an empty
+// version and constraints depend on the module's required_version and the versions available
+// then, and an exact version without a dedicated image is simply downloaded. install reports
+// whether the engine must be installed at run time, that is whether no image rule matched.
+func resolveEngineImage(cfg settings.ExecutionSettings, engine, requested string) (image string, install bool, err error) {
+	requested = strings.TrimSpace(requested)
+	if requested == "" || requested == "latest" {
+		return cfg.DefaultImage, true, nil
+	}
+
+	exact, err := version.NewSemver(requested)
+	if err != nil {
+		if _, err := version.NewConstraint(requested); err != nil {
+			return "", false, &rejectionError{reason: fmt.Sprintf("invalid engine version %q", requested)}
+		}
+		return cfg.DefaultImage, true, nil
+	}
+
+	for _, rule := range cfg.EngineImages {
+		if rule.Engine != engine {
+			continue
+		}
+		if rule.Versions != "" {
+			constraint, err := version.NewConstraint(rule.Versions)
+			if err != nil {
+				return "", false, fmt.Errorf("invalid engine image rule %q: %w", rule.Versions, err)
+			}
+			if !constraint.Check(exact) {
+				continue
+			}
+		}
+		image, err := renderImage(rule.Image, engine, exact.String())
+		return image, false, err
+	}
+	return cfg.DefaultImage, true, nil
+}
+
+// tenvEngines maps engine names to tenv's subcommand and environment variable prefix.
+var tenvEngines = map[string]struct{ command, versionVar, remoteVar string }{
+	"tofu":      {command: "tofu", versionVar: "TOFUENV_TOFU_VERSION", remoteVar: "TOFUENV_REMOTE"},
+	"terraform": {command: "tf", versionVar: "TFENV_TERRAFORM_VERSION", remoteVar: "TFENV_REMOTE"},
+}
+
+// engineInstallVersion is the version tenv is asked to install. "latest" and an empty version
+// become "latest-allowed": the newest release satisfying the module's required_version, or the
+// newest release when the module declares none.
+func engineInstallVersion(requested string) string {
+	requested = strings.TrimSpace(requested)
+	if requested == "" || requested == "latest" {
+		return "latest-allowed"
+	}
+	return requested
+}
+
+// engineInstallEnv configures tenv in the default image: which version to install, where to
+// cache it and which mirror to download it from. tenv verifies the checksums and signatures of
+// everything it downloads.
+func engineInstallEnv(cfg settings.EngineInstallSettings, engine, requested string) []corev1.EnvVar {
+	names := tenvEngines[engine]
+	env := []corev1.EnvVar{
+		{Name: names.versionVar, Value: engineInstallVersion(requested)},
+		{Name: "TENV_AUTO_INSTALL", Value: "true"},
+	}
+	if cfg.CacheDir != "" {
+		env = append(env, corev1.EnvVar{Name: "TENV_ROOT", Value: cfg.CacheDir})
+	}
+	mirror := cfg.TofuMirror
+	if engine == "terraform" {
+		mirror = cfg.TerraformMirror
+	}
+	if mirror != "" {
+		env = append(env, corev1.EnvVar{Name: names.remoteVar, Value: mirror})
+	}
+	return env
+}
+
+// engineInstallScript installs the engine before init so that download and verification
+// failures surface as init errors with a clear message.
+func engineInstallScript(engine string) string {
+	names := tenvEngines[engine]
+	return fmt.Sprintf(`tenv %s install "$%s" || exit %d;`, names.command, names.versionVar, exitCodeInitError)
+}
+
+func renderImage(image, engine, requested string) (string, error) {
+	tmpl, err := template.New("image").Option("missingkey=error").Parse(image)
+	if err != nil {
+		return "", fmt.Errorf("invalid engine image template %q: %w", image, err)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, struct{ Engine, Version string }{engine, requested}); err != nil {
+		return "", fmt.Errorf("invalid engine image template %q: %w", image, err)
+	}
+	return out.String(), nil
+}
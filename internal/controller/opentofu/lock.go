@@ -0,0 +1,194 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentofu
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+const (
+	// moduleLockDuration is how long a module lock stays valid without being renewed. Holders
+	// renew on every reconcile, which happens at least every few seconds while they run.
+	moduleLockDuration = 5 * time.Minute
+	moduleLockLabel    = "opentofu.soyplane.io/module-lock"
+)
+
+// moduleLockKey identifies the Lease serializing executions against a module's state. Executions
+// selecting different workspaces use different state and therefore different locks, named after
+// a hash of the module and workspace: workspaces need not be valid in Lease names, and joining
+// them to the module name would let foo's workspace bar share the lock of module foo-bar.
+func moduleLockKey(execution *opentofuv1alpha1.TofuExecution) types.NamespacedName {
+	namespace := moduleKey(execution).Namespace
+	name := execution.Spec.ModuleRef.Name + "-lock"
+	if execution.Spec.Workspace != "" {
+		sum := sha256.Sum256([]byte(execution.Spec.ModuleRef.Name + "/" + execution.Spec.Workspace))
+		name += "-" + hex.EncodeToString(sum[:8])
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}
+}
+
+func lockHolderIdentity(execution *opentofuv1alpha1.TofuExecution) string {
+	return execution.Namespace + "/" + execution.Name
+}
+
+// acquireModuleLock takes or renews the module lock for execution. When the lock is held by
+// another live execution it returns false along with the holder identity.
+func (r *TofuExecutionReconciler) acquireModuleLock(ctx context.Context, execution *opentofuv1alpha1.TofuExecution) (bool, string, error) {
+	log := logf.FromContext(ctx)
+	key := moduleLockKey(execution)
+	identity := lockHolderIdentity(execution)
+	now := metav1.NewMicroTime(time.Now())
+
+	var lease coordinationv1.Lease
+	if err := r.Get(ctx, key, &lease); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return false, "", err
+		}
+		lease = coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels:    map[string]string{moduleLockLabel: execution.Spec.ModuleRef.Name},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(identity),
+				LeaseDurationSeconds: ptr.To(int32(moduleLockDuration.Seconds())),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		if err := r.Create(ctx, &lease); err != nil {
+			if k8serrors.IsAlreadyExists(err) {
+				return false, "", nil
+			}
+			return false, "", err
+		}
+		log.Info("Acquired module lock", "lease", key.Name)
+		return true, identity, nil
+	}
+
+	holder := ptr.Deref(lease.Spec.HolderIdentity, "")
+	if holder == identity {
+		if lease.Spec.RenewTime == nil || now.Sub(lease.Spec.RenewTime.Time) > moduleLockDuration/3 {
+			lease.Spec.RenewTime = &now
+			if err := r.Update(ctx, &lease); err != nil && !k8serrors.IsConflict(err) {
+				return false, "", err
+			}
+		}
+		return true, identity, nil
+	}
+
+	stale, err := r.moduleLockIsStale(ctx, &lease)
+	if err != nil {
+		return false, holder, err
+	}
+	if !stale {
+		return false, holder, nil
+	}
+
+	lease.Spec.HolderIdentity = ptr.To(identity)
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(moduleLockDuration.Seconds()))
+	lease.Spec.AcquireTime = &now
+	lease.Spec.RenewTime = &now
+	lease.Spec.LeaseTransitions = ptr.To(ptr.Deref(lease.Spec.LeaseTransitions, 0) + 1)
+	if err := r.Update(ctx, &lease); err != nil {
+		if k8serrors.IsConflict(err) {
+			return false, holder, nil
+		}
+		return false, holder, err
+	}
+	log.Info("Took over stale module lock", "lease", key.Name, "previousHolder", holder)
+	return true, identity, nil
+}
+
+// moduleLockIsStale reports whether the lease can be taken over: it is free or expired, its
+// holder is gone or finished, or the holder's Job disappeared.
+func (r *TofuExecutionReconciler) moduleLockIsStale(ctx context.Context, lease *coordinationv1.Lease) (bool, error) {
+	holder := ptr.Deref(lease.Spec.HolderIdentity, "")
+	if holder == "" {
+		return true, nil
+	}
+	if lease.Spec.RenewTime != nil {
+		duration := time.Duration(ptr.Deref(lease.Spec.LeaseDurationSeconds, 0)) * time.Second
+		if time.Since(lease.Spec.RenewTime.Time) > duration {
+			return true, nil
+		}
+	}
+
+	namespace, name, found := strings.Cut(holder, "/")
+	if !found {
+		return true, nil
+	}
+	var holderExecution opentofuv1alpha1.TofuExecution
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &holderExecution); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	if isExecutionTerminal(holderExecution.Status.Phase) {
+		return true, nil
+	}
+	if holderExecution.Status.JobName == "" {
+		// The holder has not recorded its Job yet.
+		return false, nil
+	}
+	var jobs batchv1.JobList
+	if err := r.List(ctx, &jobs, client.InNamespace(namespace)); err != nil {
+		return false, err
+	}
+	for i := range jobs.Items {
+		if metav1.IsControlledBy(&jobs.Items[i], &holderExecution) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// releaseModuleLock deletes the module lock if execution holds it.
+func (r *TofuExecutionReconciler) releaseModuleLock(ctx context.Context, execution *opentofuv1alpha1.TofuExecution) error {
+	var lease coordinationv1.Lease
+	if err := r.Get(ctx, moduleLockKey(execution), &lease); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if ptr.Deref(lease.Spec.HolderIdentity, "") != lockHolderIdentity(execution) {
+		return nil
+	}
+	err := r.Delete(ctx, &lease, client.Preconditions{UID: &lease.UID, ResourceVersion: &lease.ResourceVersion})
+	if k8serrors.IsNotFound(err) || k8serrors.IsConflict(err) {
+		return nil
+	}
+	if err == nil {
+		logf.FromContext(ctx).Info("Released module lock", "lease", lease.Name)
+	}
+	return err
+}
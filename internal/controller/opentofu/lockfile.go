@@ -0,0 +1,165 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentofu
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+const (
+	// exitCodeLockfileMismatch is reported when an apply resolved a different lock file than its plan.
+	exitCodeLockfileMismatch = 14
+	// planLockfileEnvVar carries the lock file digest of the plan an apply follows.
+	planLockfileEnvVar = "SOYPLANE_PLAN_LOCKFILE"
+	// maxTerminationMessage is how much of the termination message the kubelet keeps. The lock
+	// file report is written last and gets whatever room the other reports left.
+	maxTerminationMessage = 4096
+	// lockfileSummaryFile and lockfileBriefFile hold the lock file report, with and without
+	// hashes, until the engine script exits.
+	lockfileSummaryFile = "/tmp/lockfile.summary"
+	lockfileBriefFile   = "/tmp/lockfile.brief"
+)
+
+// lockfileSummaryAWK prints a "provider <address> <version> <hashes>" line per provider block of
+// a dependency lock file. Hashes are left out when nohashes is set.
+const lockfileSummaryAWK = `$1 == "provider" { if (addr != "") print "provider", addr, version, hashes; addr = $2; gsub(/"/, "", addr); version = ""; hashes = "" }
+	$1 == "version" { version = $3; gsub(/"/, "", version) }
+	$1 ~ /^"[a-z0-9]+:/ { h = $1; gsub(/[",]/, "", h); if (!nohashes) hashes = hashes == "" ? h : hashes "," h }
+	END { if (addr != "") print "provider", addr, version, hashes }`
+
+// lockfileScript prepares the report of the lock file resolved by init, which
+// lockfileReportScript writes once the engine script exits, and fails an apply whose lock file
+// differs from its plan's.
+func lockfileScript() string {
+	return fmt.Sprintf(`lockfile=;
+	if [ -f .terraform.lock.hcl ]; then
+	lockfile=$(sha256sum .terraform.lock.hcl | cut -d' ' -f1);
+	{ echo "lockfile $lockfile"; awk '%[1]s' .terraform.lock.hcl; } > %[2]s;
+	{ echo "lockfile $lockfile"; awk -v nohashes=1 '%[1]s' .terraform.lock.hcl; } > %[3]s;
+	fi;
+	if [ -n "$%[4]s" ] && [ "$%[4]s" != "$lockfile" ]; then echo "The dependency lock file ($lockfile) differs from the plan's ($%[4]s)" >&2; exit %[5]d; fi;`,
+		lockfileSummaryAWK, lockfileSummaryFile, lockfileBriefFile, planLockfileEnvVar, exitCodeLockfileMismatch)
+}
+
+// lockfileReportScript appends the lock file report to the termination message, which the
+// controller records in the execution status. It comes last so that it cannot crowd out the
+// other reports: hashes are dropped when the full report does not fit the room left, and
+// provider lines past it are left out.
+func lockfileReportScript() string {
+	return fmt.Sprintf(`if [ -f %[2]s ]; then
+	room=$((%[1]d - $(cat /dev/termination-log 2>/dev/null | wc -c)));
+	if [ $(wc -c < %[2]s) -le $room ]; then cat %[2]s; else awk -v room=$room '{ n += length($0) + 1; if (n > room) exit; print }' %[3]s; fi >> /dev/termination-log;
+	fi;`, maxTerminationMessage, lockfileSummaryFile, lockfileBriefFile)
+}
+
+// parseLockfileSummary reads the lock file report written by lockfileScript. It returns nil
+// when the message holds no report.
+func parseLockfileSummary(message string) *opentofuv1alpha1.LockfileStatus {
+	var lockfile *opentofuv1alpha1.LockfileStatus
+	for _, line := range strings.Split(message, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 2 && fields[0] == "lockfile":
+			lockfile = &opentofuv1alpha1.LockfileStatus{Digest: fields[1]}
+		case len(fields) >= 3 && fields[0] == "provider" && lockfile != nil:
+			provider := opentofuv1alpha1.ProviderLock{Address: fields[1], Version: fields[2]}
+			if len(fields) > 3 {
+				provider.Hashes = strings.Split(fields[3], ",")
+			}
+			lockfile.Providers = append(lockfile.Providers, provider)
+		}
+	}
+	return lockfile
+}
+
+// planLockfile returns the lock file digest of the plan an apply follows. It returns an empty
+// digest while the plan is still running, and a rejectionError when the plan cannot be used.
+func (r *TofuExecutionReconciler) planLockfile(ctx context.Context, execution *opentofuv1alpha1.TofuExecution) (string, error) {
+	var plan opentofuv1alpha1.TofuExecution
+	if err := r.Get(ctx, types.NamespacedName{Namespace: execution.Namespace, Name: execution.Spec.PlanRef}, &plan); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", &rejectionError{reason: fmt.Sprintf("plan %s not found", execution.Spec.PlanRef)}
+		}
+		return "", err
+	}
+	if plan.Spec.Action != "plan" || moduleKey(&plan) != moduleKey(execution) {
+		return "", &rejectionError{reason: fmt.Sprintf("%s is not a plan of module %s", plan.Name, execution.Spec.ModuleRef.Name)}
+	}
+	if !isExecutionTerminal(plan.Status.Phase) {
+		return "", nil
+	}
+	if plan.Status.Lockfile == nil || plan.Status.Lockfile.Digest == "" {
+		return "", &rejectionError{reason: fmt.Sprintf("plan %s recorded no dependency lock file", plan.Name)}
+	}
+	return plan.Status.Lockfile.Digest, nil
+}
+
+// jobPlanLockfile returns the plan lock file digest the Job was created to check against.
+func jobPlanLockfile(job *batchv1.Job) string {
+	for _, container := range job.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			if env.Name == planLockfileEnvVar {
+				return env.Value
+			}
+		}
+	}
+	return ""
+}
+
+// setLockfileMatchedCondition records whether the apply used the lock file digest expected from
+// its plan. It reports whether the conditions changed.
+func setLockfileMatchedCondition(execution *opentofuv1alpha1.TofuExecution, expected string) bool {
+	condition := metav1.Condition{
+		Type:               opentofuv1alpha1.ConditionLockfileMatched,
+		ObservedGeneration: execution.Generation,
+	}
+	switch {
+	case execution.Status.FailureReason == opentofuv1alpha1.FailureLockfileMismatch:
+		actual := "none"
+		if execution.Status.Lockfile != nil {
+			actual = execution.Status.Lockfile.Digest
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(opentofuv1alpha1.FailureLockfileMismatch)
+		condition.Message = fmt.Sprintf("Dependency lock file %s differs from %s used by plan %s", actual, expected, execution.Spec.PlanRef)
+	case execution.Status.Lockfile != nil && execution.Status.Lockfile.Digest == expected:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "LockfileMatched"
+		condition.Message = fmt.Sprintf("Dependency lock file matches plan %s", execution.Spec.PlanRef)
+	default:
+		return false
+	}
+	return meta.SetStatusCondition(&execution.Status.Conditions, condition)
+}
+
+// initArgs returns the extra init arguments of the execution.
+func initArgs(execution *opentofuv1alpha1.TofuExecution) string {
+	if execution.Spec.LockfileReadonly {
+		return " -lockfile=readonly"
+	}
+	return ""
+}
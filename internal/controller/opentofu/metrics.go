@@ -0,0 +1,181 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentofu
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	ctrmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+var (
+	executionDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "soyplane",
+			Subsystem: "execution",
+			Name:      "duration_seconds",
+			Help:      "Duration of finished executions partitioned by action, engine and outcome.",
+			Buckets:   []float64{10, 30, 60, 120, 300, 600, 1200, 1800, 3600, 7200},
+		},
+		[]string{"action", "engine", "outcome"},
+	)
+
+	executionQueueWait = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "soyplane",
+			Subsystem: "execution",
+			Name:      "queue_wait_seconds",
+			Help:      "Time executions waited between their creation and the creation of their Job.",
+			Buckets:   []float64{1, 5, 15, 30, 60, 300, 600, 1800, 3600},
+		},
+		[]string{"action"},
+	)
+
+	executionQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "soyplane",
+			Subsystem: "execution",
+			Name:      "queue_depth",
+			Help:      "Number of queued executions partitioned by namespace.",
+		},
+		[]string{"namespace"},
+	)
+
+	plannedResourceChanges = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "soyplane",
+			Subsystem: "plan",
+			Name:      "resource_changes_total",
+			Help:      "Number of resource changes proposed by successful plans partitioned by change.",
+		},
+		[]string{"change"},
+	)
+
+	modulesByPhase = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "soyplane",
+			Name:      "modules",
+			Help:      "Number of TofuModules partitioned by phase.",
+		},
+		[]string{"phase"},
+	)
+
+	modulesDrifted = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "soyplane",
+			Name:      "modules_drifted",
+			Help:      "Number of TofuModules whose latest drift check found changes partitioned by namespace.",
+		},
+		[]string{"namespace"},
+	)
+)
+
+var (
+	queuedExecutions = newStateGauge(executionQueueDepth)
+	modulePhases     = newStateGauge(modulesByPhase)
+	driftedModules   = newStateGauge(modulesDrifted)
+)
+
+func init() {
+	ctrmetrics.Registry.MustRegister(
+		executionDuration,
+		executionQueueWait,
+		executionQueueDepth,
+		plannedResourceChanges,
+		modulesByPhase,
+		modulesDrifted,
+	)
+}
+
+// stateGauge counts objects by a label value derived from their state. Objects are tracked by
+// key, so reconciling the same object repeatedly counts it once.
+type stateGauge struct {
+	mu     sync.Mutex
+	values map[types.NamespacedName]string
+	gauge  *prometheus.GaugeVec
+}
+
+func newStateGauge(gauge *prometheus.GaugeVec) *stateGauge {
+	return &stateGauge{values: map[types.NamespacedName]string{}, gauge: gauge}
+}
+
+// set counts the object under value. An empty value stops counting the object.
+func (g *stateGauge) set(key types.NamespacedName, value string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	previous, tracked := g.values[key]
+	if tracked && previous == value {
+		return
+	}
+	if tracked {
+		g.gauge.WithLabelValues(previous).Dec()
+		delete(g.values, key)
+	}
+	if value != "" {
+		g.gauge.WithLabelValues(value).Inc()
+		g.values[key] = value
+	}
+}
+
+// observeExecutionQueue counts the execution in the queue depth while it is queued.
+func observeExecutionQueue(key types.NamespacedName, execution *opentofuv1alpha1.TofuExecution) {
+	value := ""
+	if execution != nil && execution.Status.Phase == "Queued" {
+		value = key.Namespace
+	}
+	queuedExecutions.set(key, value)
+}
+
+// observeModule counts the module under its phase and, when drifted, as drifted. A nil module
+// stops counting it.
+func observeModule(key types.NamespacedName, module *opentofuv1alpha1.TofuModule, drifted bool) {
+	phase, namespace := "", ""
+	if module != nil {
+		phase = module.Status.Phase
+		if drifted {
+			namespace = key.Namespace
+		}
+	}
+	modulePhases.set(key, phase)
+	driftedModules.set(key, namespace)
+}
+
+// observeExecutionOutcome records the duration of a finished execution and, for plans, the
+// resource changes it proposed.
+func observeExecutionOutcome(execution *opentofuv1alpha1.TofuExecution, engine string, planned *plannedResources) {
+	summary := execution.Status.ExecutionSummary
+	if summary.StartedAt != nil && summary.FinishedAt != nil {
+		executionDuration.WithLabelValues(execution.Spec.Action, engine, execution.Status.Phase).
+			Observe(summary.FinishedAt.Sub(summary.StartedAt.Time).Seconds())
+	}
+	if planned != nil {
+		plannedResourceChanges.WithLabelValues("add").Add(float64(planned.add))
+		plannedResourceChanges.WithLabelValues("change").Add(float64(planned.change))
+		plannedResourceChanges.WithLabelValues("destroy").Add(float64(planned.destroy))
+	}
+}
+
+// observeQueueWait records how long the execution waited before its Job was created.
+func observeQueueWait(execution *opentofuv1alpha1.TofuExecution, now time.Time) {
+	executionQueueWait.WithLabelValues(execution.Spec.Action).
+		Observe(max(0, now.Sub(execution.CreationTimestamp.Time).Seconds()))
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentofu
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	settings "github.com/soyplane-io/soyplane/internal/settings"
+)
+
+const (
+	// enforcePodSecurityLabel opts a namespace into rejecting executions whose pods do not meet
+	// the restricted Pod Security Standard.
+	enforcePodSecurityLabel = "opentofu.soyplane.io/enforce-pod-security"
+
+	// workspaceMountPath is the writable scratch directory execution pods run in when their root
+	// filesystem is read-only. It doubles as HOME for the engine's plugin cache and credentials.
+	workspaceMountPath = "/workspace"
+)
+
+// hardenPodSpec applies the restricted security defaults to a generated pod spec: non-root
+// user, RuntimeDefault seccomp, no privilege escalation, all capabilities dropped and a
+// read-only root filesystem with emptyDir volumes for the workspace and /tmp.
+func hardenPodSpec(spec *corev1.PodSpec, cfg settings.PodSecuritySettings) {
+	spec.AutomountServiceAccountToken = ptr.To(cfg.AutomountServiceAccountToken)
+	if cfg.Profile != settings.PodSecurityRestricted {
+		return
+	}
+
+	spec.SecurityContext = &corev1.PodSecurityContext{
+		RunAsNonRoot:   ptr.To(true),
+		RunAsUser:      ptr.To(cfg.RunAsUser),
+		RunAsGroup:     ptr.To(cfg.RunAsGroup),
+		FSGroup:        ptr.To(cfg.FSGroup),
+		SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+	spec.Volumes = append([]corev1.Volume{
+		{Name: "workspace", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+	}, spec.Volumes...)
+	for i := range len(spec.InitContainers) + len(spec.Containers) {
+		container := podContainer(spec, i)
+		container.SecurityContext = &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			ReadOnlyRootFilesystem:   ptr.To(true),
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		}
+		container.VolumeMounts = append([]corev1.VolumeMount{
+			{Name: "workspace", MountPath: workspaceMountPath},
+			{Name: "tmp", MountPath: "/tmp"},
+		}, container.VolumeMounts...)
+		container.WorkingDir = workspaceMountPath
+		// Templates may still override HOME: the last duplicate wins.
+		container.Env = append([]corev1.EnvVar{{Name: "HOME", Value: workspaceMountPath}}, container.Env...)
+	}
+}
+
+// podContainer returns the i-th container of spec, counting init containers first.
+func podContainer(spec *corev1.PodSpec, i int) *corev1.Container {
+	if i < len(spec.InitContainers) {
+		return &spec.InitContainers[i]
+	}
+	return &spec.Containers[i-len(spec.InitContainers)]
+}
+
+// podSecurityViolations lists how spec falls short of the restricted Pod Security Standard and
+// of the read-only root filesystem applied by hardenPodSpec.
+func podSecurityViolations(spec *corev1.PodSpec) []string {
+	var violations []string
+	if spec.HostNetwork || spec.HostPID || spec.HostIPC {
+		violations = append(violations, "host namespaces must not be shared")
+	}
+	for _, volume := range spec.Volumes {
+		if volume.HostPath != nil {
+			violations = append(violations, fmt.Sprintf("volume %q must not use hostPath", volume.Name))
+		}
+	}
+
+	podContext := spec.SecurityContext
+	if podContext == nil {
+		podContext = &corev1.PodSecurityContext{}
+	}
+	if podContext.SeccompProfile != nil && podContext.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+		violations = append(violations, "seccomp profile must not be Unconfined")
+	}
+	if ptr.Deref(podContext.RunAsUser, -1) == 0 {
+		violations = append(violations, "pod must not run as UID 0")
+	}
+
+	containers := append(slices.Clone(spec.InitContainers), spec.Containers...)
+	for _, container := range containers {
+		ctx := container.SecurityContext
+		if ctx == nil {
+			ctx = &corev1.SecurityContext{}
+		}
+		prefix := fmt.Sprintf("container %q", container.Name)
+		if ptr.Deref(ctx.Privileged, false) {
+			violations = append(violations, prefix+" must not be privileged")
+		}
+		if ptr.Deref(ctx.AllowPrivilegeEscalation, true) {
+			violations = append(violations, prefix+" must set allowPrivilegeEscalation=false")
+		}
+		if !ptr.Deref(ctx.ReadOnlyRootFilesystem, false) {
+			violations = append(violations, prefix+" must set readOnlyRootFilesystem=true")
+		}
+		if !ptr.Deref(ctx.RunAsNonRoot, ptr.Deref(podContext.RunAsNonRoot, false)) {
+			violations = append(violations, prefix+" must run as non-root")
+		}
+		if ptr.Deref(ctx.RunAsUser, -1) == 0 {
+			violations = append(violations, prefix+" must not run as UID 0")
+		}
+		seccomp := ctx.SeccompProfile
+		if seccomp == nil {
+			seccomp = podContext.SeccompProfile
+		}
+		if seccomp == nil || (seccomp.Type != corev1.SeccompProfileTypeRuntimeDefault && seccomp.Type != corev1.SeccompProfileTypeLocalhost) {
+			violations = append(violations, prefix+" must use the RuntimeDefault or a Localhost seccomp profile")
+		}
+		if ctx.Capabilities == nil || !slices.Contains(ctx.Capabilities.Drop, "ALL") {
+			violations = append(violations, prefix+" must drop ALL capabilities")
+		}
+		if ctx.Capabilities != nil {
+			for _, capability := range ctx.Capabilities.Add {
+				if capability != "NET_BIND_SERVICE" {
+					violations = append(violations, fmt.Sprintf("%s must not add capability %s", prefix, capability))
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// enforcesPodSecurity reports whether namespace opted into rejecting non-restricted execution pods.
+func (r *TofuExecutionReconciler) enforcesPodSecurity(ctx context.Context, namespace string) (bool, error) {
+	var ns corev1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return ns.Labels[enforcePodSecurityLabel] == "true", nil
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentofu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	"github.com/soyplane-io/soyplane/internal/policy"
+)
+
+const (
+	// policiesEnvVar carries the policies the agent checks the plan against.
+	policiesEnvVar = "SOYPLANE_POLICIES"
+	// agentVolumeName is the emptyDir the agent init container copies the agent binary into.
+	agentVolumeName = "soyplane-agent"
+	agentBinDir     = "/soyplane/bin"
+	policyPlanJSON  = "/tmp/soyplane-plan.json"
+	// agentInitContainerName names the init container installing the agent.
+	agentInitContainerName = "install-agent"
+	// policyErrorMessage stands in for evaluation errors, which only the execution logs carry.
+	policyErrorMessage = "The rule could not be evaluated; see the execution logs"
+)
+
+// modulePolicies returns the TofuPolicies selecting the module, sorted by name: those of the
+// module's namespace and those of sharedNamespace, which check the modules of every namespace and
+// are named <namespace>/<name>. Policies without a module selector select every module.
+func (r *TofuExecutionReconciler) modulePolicies(ctx context.Context, module *opentofuv1alpha1.TofuModule, sharedNamespace string) ([]policy.Policy, error) {
+	namespaces := []string{module.Namespace}
+	if sharedNamespace != "" && sharedNamespace != module.Namespace {
+		namespaces = append(namespaces, sharedNamespace)
+	}
+	var policies []policy.Policy
+	for _, namespace := range namespaces {
+		var list opentofuv1alpha1.TofuPolicyList
+		if err := r.List(ctx, &list, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			p := policy.FromTofuPolicy(&list.Items[i])
+			if namespace != module.Namespace {
+				p.Name = namespace + "/" + p.Name
+			}
+			if selector := list.Items[i].Spec.ModuleSelector; selector != nil {
+				selector, err := metav1.LabelSelectorAsSelector(selector)
+				if err != nil {
+					// Fail closed: the policy may have been meant to select this module.
+					return nil, &rejectionError{reason: fmt.Sprintf("TofuPolicy %s has an invalid module selector: %v", p.Name, err)}
+				}
+				if !selector.Matches(labels.Set(module.Labels)) {
+					continue
+				}
+			}
+			policies = append(policies, p)
+		}
+	}
+	slices.SortFunc(policies, func(a, b policy.Policy) int { return strings.Compare(a.Name, b.Name) })
+	return policies, nil
+}
+
+// addPolicyAgent makes the agent available to the engine container: an init container copies it
+// from the agent image into a shared emptyDir, as execution images do not ship it.
+func addPolicyAgent(spec *corev1.PodSpec, image string) {
+	mount := corev1.VolumeMount{Name: agentVolumeName, MountPath: agentBinDir}
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name:         agentVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+	spec.InitContainers = append(spec.InitContainers, corev1.Container{
+		Name:         agentInitContainerName,
+		Image:        image,
+		Command:      []string{"/agent", "install", agentBinDir},
+		VolumeMounts: []corev1.VolumeMount{mount},
+	})
+	for i := range spec.Containers {
+		spec.Containers[i].VolumeMounts = append(spec.Containers[i].VolumeMounts, mount)
+	}
+}
+
+// policyEngineScript replaces engineScript when policies apply: it plans to a file, checks the
+// plan JSON with the agent and, for applies, applies exactly the checked plan. Violations of
+// Deny policies fail an apply before it changes anything; plans only report them.
+func policyEngineScript(engineName, action string) string {
+	plan := fmt.Sprintf("%s plan -input=false -out=%s", engineName, planFile)
+	step := engineStep(plan, exitCodePlanError)
+	if action != "apply" {
+		step = planStep(engineName, plan)
+	}
+	steps := []string{
+		phaseMark("plan"),
+		step,
+		fmt.Sprintf("%s show -json %s > %s || exit %d;", engineName, planFile, policyPlanJSON, exitCodePlanError),
+		phaseMark("policy-check"),
+	}
+	check := fmt.Sprintf("%s/agent policy-check %s", agentBinDir, policyPlanJSON)
+	if action != "apply" {
+		return strings.Join(append(steps, check+" || true;"), "\n\t")
+	}
+	steps = append(steps,
+		fmt.Sprintf("%s || exit %d;", check, exitCodePolicyDenied),
+		phaseMark("apply"),
+		engineStep(fmt.Sprintf("%s apply -input=false %s", engineName, planFile), exitCodeApplyError))
+	return strings.Join(steps, "\n\t")
+}
+
+// policiesEnv passes policies to the agent.
+func policiesEnv(policies []policy.Policy) (corev1.EnvVar, error) {
+	raw, err := json.Marshal(policies)
+	if err != nil {
+		return corev1.EnvVar{}, err
+	}
+	return corev1.EnvVar{Name: policiesEnvVar, Value: string(raw)}, nil
+}
+
+// jobPolicies returns the policies the Job was created to check.
+func jobPolicies(job *batchv1.Job) []policy.Policy {
+	for _, container := range job.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			if env.Name != policiesEnvVar {
+				continue
+			}
+			var policies []policy.Policy
+			if err := json.Unmarshal([]byte(env.Value), &policies); err != nil {
+				return nil
+			}
+			return policies
+		}
+	}
+	return nil
+}
+
+// parsePolicyResults reads the violations reported by the agent after the lock file report and
+// completes them with the passing rules of policies. It returns nil when the plan was not
+// checked. Rules that may have been cut from a truncated report are left out.
+func parsePolicyResults(message string, policies []policy.Policy) []opentofuv1alpha1.PolicyResult {
+	evaluated, truncated := false, false
+	outcomes := make(map[string]opentofuv1alpha1.PolicyOutcome)
+	for _, line := range strings.Split(message, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case line == "policies evaluated":
+			evaluated = true
+		case line == "policies truncated":
+			truncated = true
+		case len(fields) == 4 && fields[0] == "policy":
+			outcomes[fields[1]+"/"+fields[2]] = opentofuv1alpha1.PolicyOutcome(fields[3])
+		}
+	}
+	if !evaluated {
+		return nil
+	}
+
+	results := []opentofuv1alpha1.PolicyResult{}
+	for _, p := range policies {
+		for _, rule := range p.Rules {
+			result := opentofuv1alpha1.PolicyResult{
+				Policy:      p.Name,
+				Rule:        rule.Name,
+				Enforcement: p.Enforcement,
+				Outcome:     opentofuv1alpha1.PolicyPass,
+			}
+			outcome, found := outcomes[p.Name+"/"+rule.Name]
+			switch {
+			case !found && truncated:
+				continue
+			case outcome == opentofuv1alpha1.PolicyFail:
+				result.Outcome = outcome
+				result.Message = rule.Message
+			case outcome == opentofuv1alpha1.PolicyError:
+				result.Outcome = outcome
+				result.Message = policyErrorMessage
+			}
+			results = append(results, result)
+		}
+	}
+	return results
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentofu
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	"github.com/soyplane-io/soyplane/internal/pullrequest"
+)
+
+// jobModuleRef returns the module version the execution Job checked out.
+func jobModuleRef(job *batchv1.Job) string {
+	for _, container := range job.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			if env.Name == moduleRefEnvVar {
+				return env.Value
+			}
+		}
+	}
+	return ""
+}
+
+// commentPullRequest posts the results of a finished plan on the pull request of the ref the
+// Job checked out, when its module asks for it. Failures are recorded as Events rather than
+// retried: the plan is over and the next one refreshes the comment.
+func (r *TofuExecutionReconciler) commentPullRequest(ctx context.Context, execution *opentofuv1alpha1.TofuExecution, job *batchv1.Job, planned *plannedResources) {
+	log := logf.FromContext(ctx)
+	ref := jobModuleRef(job)
+	if ref == "" {
+		return
+	}
+	var module opentofuv1alpha1.TofuModule
+	if err := r.Get(ctx, moduleKey(execution), &module); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.Error(err, "Unable to get the module to comment on its pull request")
+		}
+		return
+	}
+	if module.Spec.PullRequestComment == nil {
+		return
+	}
+	number, err := r.postPullRequestComment(ctx, &module, execution, ref, planned)
+	switch {
+	case err != nil:
+		log.Error(err, "Unable to comment the plan on the pull request", "ref", ref)
+		r.Recorder.Eventf(execution, corev1.EventTypeWarning, eventPullRequestCommentFailed, "Unable to comment the plan on the pull request of %s: %v", ref, err)
+	case number > 0:
+		r.Recorder.Eventf(execution, corev1.EventTypeNormal, eventPullRequestCommented, "Commented the plan on pull request #%d", number)
+	default:
+		log.Info("No open pull request to comment the plan on", "ref", ref)
+	}
+}
+
+// postPullRequestComment creates or updates the module's comment on the pull request of ref. It
+// returns the number of the pull request, or 0 when ref has no open pull request.
+func (r *TofuExecutionReconciler) postPullRequestComment(ctx context.Context, module *opentofuv1alpha1.TofuModule, execution *opentofuv1alpha1.TofuExecution, ref string, planned *plannedResources) (int, error) {
+	spec := module.Spec.PullRequestComment
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	var secret corev1.Secret
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: module.Namespace, Name: spec.TokenSecretRef.Name}, &secret); err != nil {
+		return 0, fmt.Errorf("reading the token: %w", err)
+	}
+	token, ok := secret.Data[spec.TokenSecretRef.Key]
+	if !ok {
+		return 0, fmt.Errorf("secret %s has no key %q", spec.TokenSecretRef.Name, spec.TokenSecretRef.Key)
+	}
+	pr, err := pullrequest.NewClient(*spec, module.Spec.Source, strings.TrimSpace(string(token)))
+	if err != nil {
+		return 0, err
+	}
+	number, found, err := pr.Number(ctx, ref)
+	if err != nil || !found {
+		return 0, err
+	}
+	marker := fmt.Sprintf("<!-- soyplane:%s/%s -->", module.Namespace, module.Name)
+	if err := pr.Comment(ctx, number, marker, pullRequestCommentBody(module, execution, ref, planned)); err != nil {
+		return 0, err
+	}
+	return number, nil
+}
+
+// pullRequestCommentBody renders the outcome of a plan and its policy results in Markdown.
+func pullRequestCommentBody(module *opentofuv1alpha1.TofuModule, execution *opentofuv1alpha1.TofuExecution, ref string, planned *plannedResources) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#### Soyplane plan for `%s/%s`\n\n", module.Namespace, module.Name)
+	switch {
+	case execution.Status.Phase != "Succeeded":
+		fmt.Fprintf(&b, "**%s**\n", execution.Status.Summary)
+	case !ptr.Deref(execution.Status.HasChanges, false):
+		b.WriteString("**No changes.** The infrastructure matches the configuration.\n")
+	case planned != nil:
+		fmt.Fprintf(&b, "**Plan: %d to add, %d to change, %d to destroy.**\n", planned.add, planned.change, planned.destroy)
+	default:
+		b.WriteString("**The plan has changes.**\n")
+	}
+	fmt.Fprintf(&b, "\nRef `%s`, execution `%s` (%s).\n", ref, execution.Name, executionEngine(execution))
+
+	if len(execution.Status.Policies) > 0 {
+		b.WriteString("\n| Policy | Rule | Enforcement | Outcome | Message |\n|---|---|---|---|---|\n")
+		for _, result := range execution.Status.Policies {
+			message := strings.NewReplacer("|", `\|`, "\n", " ").Replace(result.Message)
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", result.Policy, result.Rule, result.Enforcement, result.Outcome, message)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentofu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	settings "github.com/soyplane-io/soyplane/internal/settings"
+)
+
+const (
+	// cliConfigEnvVar carries the engine CLI configuration, which the execution script writes
+	// to cliConfigPath before init.
+	cliConfigEnvVar = "SOYPLANE_CLI_CONFIG"
+	cliConfigPath   = "/tmp/soyplane.tfrc"
+)
+
+// providerInstallationConfig renders the CLI configuration pointing the engine at the network
+// mirror, or returns an empty string when providers come from their origin registries.
+func providerInstallationConfig(cfg settings.ProviderInstallationSettings) string {
+	if cfg.NetworkMirrorURL == "" {
+		return ""
+	}
+	url := cfg.NetworkMirrorURL
+	if !strings.HasSuffix(url, "/") {
+		url += "/"
+	}
+	var b strings.Builder
+	b.WriteString("provider_installation {\n")
+	fmt.Fprintf(&b, "  network_mirror {\n    url = %s\n  }\n", strconv.Quote(url))
+	if cfg.Direct {
+		b.WriteString("  direct {}\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// providerInstallationEnv configures provider installation for the engine: the CLI
+// configuration written by providerInstallationScript and the plugin cache.
+func providerInstallationEnv(cfg settings.ProviderInstallationSettings) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	if config := providerInstallationConfig(cfg); config != "" {
+		env = append(env,
+			corev1.EnvVar{Name: cliConfigEnvVar, Value: config},
+			corev1.EnvVar{Name: "TF_CLI_CONFIG_FILE", Value: cliConfigPath},
+		)
+	}
+	if cfg.PluginCacheDir != "" {
+		env = append(env, corev1.EnvVar{Name: "TF_PLUGIN_CACHE_DIR", Value: cfg.PluginCacheDir})
+	}
+	return env
+}
+
+// providerInstallationScript writes the CLI configuration and creates the plugin cache
+// directory, which the engine expects to exist.
+func providerInstallationScript(cfg settings.ProviderInstallationSettings) string {
+	var steps []string
+	if cfg.PluginCacheDir != "" {
+		steps = append(steps, `mkdir -p "$TF_PLUGIN_CACHE_DIR";`)
+	}
+	if cfg.NetworkMirrorURL != "" {
+		steps = append(steps, fmt.Sprintf(`printf '%%s' "$%s" > "$TF_CLI_CONFIG_FILE";`, cliConfigEnvVar))
+	}
+	return strings.Join(steps, "\n\t")
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentofu
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/ptr"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	settings "github.com/soyplane-io/soyplane/internal/settings"
+)
+
+// executionPriority returns the effective priority of an execution.
+func executionPriority(execution *opentofuv1alpha1.TofuExecution) int32 {
+	if execution.Spec.Priority != nil {
+		return *execution.Spec.Priority
+	}
+	if execution.Spec.Action == "apply" {
+		return opentofuv1alpha1.DefaultApplyPriority
+	}
+	return opentofuv1alpha1.DefaultPlanPriority
+}
+
+// isExecutionAdmitted reports whether the execution already started its Job and therefore
+// occupies a concurrency slot.
+func isExecutionAdmitted(execution *opentofuv1alpha1.TofuExecution) bool {
+	return execution.Status.JobName != "" && !isExecutionTerminal(execution.Status.Phase)
+}
+
+// admitExecution decides whether execution may start its Job under the configured global and
+// per-namespace concurrency limits. When it is not admitted a short explanation is returned.
+func (r *TofuExecutionReconciler) admitExecution(ctx context.Context, execution *opentofuv1alpha1.TofuExecution) (bool, string, error) {
+	execCfg, err := settings.Execution()
+	if err != nil {
+		return false, "", fmt.Errorf("invalid settings: %w", err)
+	}
+	if execCfg.MaxConcurrent == 0 && execCfg.NamespaceLimit(execution.Namespace) == 0 {
+		return true, "", nil
+	}
+
+	var executions opentofuv1alpha1.TofuExecutionList
+	if err := r.List(ctx, &executions); err != nil {
+		return false, "", err
+	}
+	candidates := make([]*opentofuv1alpha1.TofuExecution, 0, len(executions.Items))
+	for i := range executions.Items {
+		candidate := &executions.Items[i]
+		if candidate.UID == execution.UID || isExecutionTerminal(candidate.Status.Phase) {
+			continue
+		}
+		if !isExecutionAdmitted(candidate) {
+			// Executions waiting on a module lock cannot start anyway and must not hold up the queue.
+			blocked, err := r.isBlockedByModuleLock(ctx, candidate)
+			if err != nil {
+				return false, "", err
+			}
+			if blocked {
+				continue
+			}
+		}
+		candidates = append(candidates, candidate)
+	}
+	admitted, reason := admitFromQueue(execCfg, execution, candidates)
+	return admitted, reason, nil
+}
+
+// admitFromQueue applies the concurrency limits to execution given the other non-terminal
+// executions. Running executions occupy a slot each; waiting ones are admitted by descending
+// priority, oldest first.
+func admitFromQueue(execCfg settings.ExecutionSettings, execution *opentofuv1alpha1.TofuExecution, others []*opentofuv1alpha1.TofuExecution) (bool, string) {
+	running, namespaceRunning := 0, 0
+	waiting := []*opentofuv1alpha1.TofuExecution{execution}
+	for _, candidate := range others {
+		if isExecutionAdmitted(candidate) {
+			running++
+			if candidate.Namespace == execution.Namespace {
+				namespaceRunning++
+			}
+			continue
+		}
+		waiting = append(waiting, candidate)
+	}
+	slices.SortStableFunc(waiting, compareQueuedExecutions)
+
+	ahead, namespaceAhead := 0, 0
+	for _, candidate := range waiting {
+		if candidate == execution {
+			break
+		}
+		ahead++
+		if candidate.Namespace == execution.Namespace {
+			namespaceAhead++
+		}
+	}
+
+	if limit := execCfg.MaxConcurrent; limit > 0 && running+ahead >= limit {
+		return false, fmt.Sprintf("Waiting for execution capacity (%d running, limit %d)", running, limit)
+	}
+	if limit := execCfg.NamespaceLimit(execution.Namespace); limit > 0 && namespaceRunning+namespaceAhead >= limit {
+		return false, fmt.Sprintf("Waiting for namespace execution capacity (%d running, limit %d)", namespaceRunning, limit)
+	}
+	return true, ""
+}
+
+// compareQueuedExecutions orders executions by descending priority, then by age.
+func compareQueuedExecutions(a, b *opentofuv1alpha1.TofuExecution) int {
+	if pa, pb := executionPriority(a), executionPriority(b); pa != pb {
+		if pa > pb {
+			return -1
+		}
+		return 1
+	}
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		if a.CreationTimestamp.Before(&b.CreationTimestamp) {
+			return -1
+		}
+		return 1
+	}
+	if a.Namespace != b.Namespace {
+		if a.Namespace < b.Namespace {
+			return -1
+		}
+		return 1
+	}
+	if a.Name < b.Name {
+		return -1
+	} else if a.Name > b.Name {
+		return 1
+	}
+	return 0
+}
+
+// isBlockedByModuleLock reports whether another execution holds the module lock the candidate
+// needs, in which case it should not hold up the capacity queue.
+func (r *TofuExecutionReconciler) isBlockedByModuleLock(ctx context.Context, candidate *opentofuv1alpha1.TofuExecution) (bool, error) {
+	var lease coordinationv1.Lease
+	if err := r.Get(ctx, moduleLockKey(candidate), &lease); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	holder := ptr.Deref(lease.Spec.HolderIdentity, "")
+	return holder != "" && holder != lockHolderIdentity(candidate), nil
+}
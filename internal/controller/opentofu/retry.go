@@ -0,0 +1,197 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentofu
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+const (
+	// attemptAnnotation numbers the attempts of a retried execution, starting at 1.
+	attemptAnnotation = "opentofu.soyplane.io/attempt"
+	// retryOfAnnotation names the first attempt on every retry of it.
+	retryOfAnnotation = "opentofu.soyplane.io/retry-of"
+
+	// defaultRetryBackoff applies when RetryPolicy.Backoff is unset.
+	defaultRetryBackoff = 30 * time.Second
+	// maxRetryBackoff caps the exponential backoff between attempts.
+	maxRetryBackoff = 30 * time.Minute
+)
+
+// Exit codes used by the execution script to report the stage that failed.
+const (
+	exitCodeInitError      = 10
+	exitCodeLockContention = 11
+	exitCodePlanError      = 12
+	exitCodeApplyError     = 13
+	exitCodePolicyDenied   = 15
+)
+
+// defaultRetryOn lists the failure reasons retried when RetryPolicy.RetryOn is empty.
+var defaultRetryOn = []opentofuv1alpha1.FailureReason{
+	opentofuv1alpha1.FailureLockContention,
+	opentofuv1alpha1.FailurePlanError,
+	opentofuv1alpha1.FailureApplyError,
+}
+
+func failureReasonForExitCode(code int32) opentofuv1alpha1.FailureReason {
+	switch code {
+	case exitCodeInitError:
+		return opentofuv1alpha1.FailureInitError
+	case exitCodeLockContention:
+		return opentofuv1alpha1.FailureLockContention
+	case exitCodePlanError:
+		return opentofuv1alpha1.FailurePlanError
+	case exitCodeApplyError:
+		return opentofuv1alpha1.FailureApplyError
+	case exitCodeLockfileMismatch:
+		return opentofuv1alpha1.FailureLockfileMismatch
+	case exitCodePolicyDenied:
+		return opentofuv1alpha1.FailurePolicyDenied
+	default:
+		return opentofuv1alpha1.FailureUnknown
+	}
+}
+
+// actionExitCode is the exit code reported when the engine action itself fails.
+func actionExitCode(action string) int {
+	if action == "apply" {
+		return exitCodeApplyError
+	}
+	return exitCodePlanError
+}
+
+// classifyFailure derives the failure reason from the exit code of the Job's most recently
+// terminated container.
+func (r *TofuExecutionReconciler) classifyFailure(ctx context.Context, job *batchv1.Job) (opentofuv1alpha1.FailureReason, error) {
+	latest, err := r.latestTermination(ctx, job, true)
+	if err != nil {
+		return "", err
+	}
+	if latest == nil {
+		return opentofuv1alpha1.FailureUnknown, nil
+	}
+	return failureReasonForExitCode(latest.ExitCode), nil
+}
+
+// latestTermination returns the most recent termination state of the Job's containers, only
+// considering failed containers when failedOnly is set.
+func (r *TofuExecutionReconciler) latestTermination(ctx context.Context, job *batchv1.Job, failedOnly bool) (*corev1.ContainerStateTerminated, error) {
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(job.Namespace), client.MatchingLabels{batchv1.JobNameLabel: job.Name}); err != nil {
+		return nil, err
+	}
+	var latest *corev1.ContainerStateTerminated
+	for i := range pods.Items {
+		for _, status := range pods.Items[i].Status.ContainerStatuses {
+			terminated := status.State.Terminated
+			if terminated == nil || (failedOnly && terminated.ExitCode == 0) {
+				continue
+			}
+			if latest == nil || latest.FinishedAt.Before(&terminated.FinishedAt) {
+				latest = terminated
+			}
+		}
+	}
+	return latest, nil
+}
+
+// executionAttempt returns the attempt number of the execution, 1 for the first attempt.
+func executionAttempt(execution *opentofuv1alpha1.TofuExecution) int32 {
+	attempt, err := strconv.ParseInt(execution.Annotations[attemptAnnotation], 10, 32)
+	if err != nil || attempt < 1 {
+		return 1
+	}
+	return int32(attempt)
+}
+
+// retryDelay reports whether the failed execution should be retried under its retry policy and,
+// if so, how long to wait from now before creating the next attempt.
+func retryDelay(execution *opentofuv1alpha1.TofuExecution, now time.Time) (bool, time.Duration) {
+	policy := execution.Spec.RetryPolicy
+	if policy == nil || execution.Status.Phase != "Failed" {
+		return false, 0
+	}
+	attempt := executionAttempt(execution)
+	if attempt > policy.MaxRetries {
+		return false, 0
+	}
+	retryOn := policy.RetryOn
+	if len(retryOn) == 0 {
+		retryOn = defaultRetryOn
+	}
+	if !slices.Contains(retryOn, execution.Status.FailureReason) {
+		return false, 0
+	}
+
+	backoff := defaultRetryBackoff
+	if policy.Backoff != nil {
+		backoff = policy.Backoff.Duration
+	}
+	for i := int32(1); i < attempt && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, maxRetryBackoff)
+
+	failedAt := execution.CreationTimestamp.Time
+	if execution.Status.FinishedAt != nil {
+		failedAt = execution.Status.FinishedAt.Time
+	}
+	return true, max(failedAt.Add(backoff).Sub(now), 0)
+}
+
+// newRetryExecution builds the next attempt of a failed execution. It keeps the failed
+// execution's spec, labels and owner, and links back to the first attempt.
+func newRetryExecution(failed *opentofuv1alpha1.TofuExecution) *opentofuv1alpha1.TofuExecution {
+	generateName := failed.GenerateName
+	if generateName == "" {
+		generateName = failed.Name + "-"
+	}
+	annotations := maps.Clone(failed.Annotations)
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	delete(annotations, opentofuv1alpha1.CancelAnnotation)
+	if _, ok := annotations[retryOfAnnotation]; !ok {
+		annotations[retryOfAnnotation] = failed.Name
+	}
+	annotations[attemptAnnotation] = strconv.Itoa(int(executionAttempt(failed) + 1))
+
+	retry := &opentofuv1alpha1.TofuExecution{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName:    generateName,
+			Namespace:       failed.Namespace,
+			Labels:          maps.Clone(failed.Labels),
+			Annotations:     annotations,
+			OwnerReferences: slices.Clone(failed.OwnerReferences),
+		},
+		Spec: *failed.Spec.DeepCopy(),
+	}
+	retry.Spec.Cancel = false
+	return retry
+}
@@ -18,29 +18,47 @@ package opentofu
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"maps"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	"github.com/soyplane-io/soyplane/internal/modulegrant"
 	settings "github.com/soyplane-io/soyplane/internal/settings"
+	"github.com/soyplane-io/soyplane/internal/tracing"
 )
 
 // TofuExecutionReconciler reconciles a TofuExecution object
 type TofuExecutionReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// APIReader reads the Secrets holding pull request comment tokens, bypassing the cache so
+	// that Secrets are not watched. The client is used when it is unset.
+	APIReader client.Reader
 }
 
 // +kubebuilder:rbac:groups=opentofu.soyplane.io,resources=tofuexecutions,verbs=get;list;watch;create;update;patch;delete
@@ -48,6 +66,15 @@ type TofuExecutionReconciler struct {
 // +kubebuilder:rbac:groups=opentofu.soyplane.io,resources=tofuexecutions/finalizers,verbs=update
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=batch,resources=jobs/status,verbs=get
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=opentofu.soyplane.io,resources=tofupolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=opentofu.soyplane.io,resources=tofumodulegrants,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;create;update;patch
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=create;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -61,10 +88,20 @@ type TofuExecutionReconciler struct {
 func (r *TofuExecutionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 	var execution opentofuv1alpha1.TofuExecution
+	// Deleted executions leave the queue along with those that were dequeued.
+	defer observeExecutionQueue(req.NamespacedName, &execution)
 	if err := r.Get(ctx, req.NamespacedName, &execution); err != nil {
 		log.Error(err, "Unable to fetch TofuExecution")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
+	if !execution.DeletionTimestamp.IsZero() {
+		// Owned objects are garbage collected, but grants in other namespaces are not.
+		return ctrl.Result{}, r.revokeExecutionAccess(ctx, &execution)
+	}
+
+	ctx, span := tracing.Tracer().Start(tracing.ExecutionContext(ctx, execution.UID), "Reconcile",
+		trace.WithAttributes(attribute.String("soyplane.phase", execution.Status.Phase)))
+	defer span.End()
 
 	job, err := r.job(ctx, &execution)
 	if err != nil {
@@ -72,27 +109,110 @@ func (r *TofuExecutionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	if job == nil {
-		newJob, err := r.constructJobFromExecution(ctx, &execution)
+		if isExecutionTerminal(execution.Status.Phase) {
+			// The Job was cleaned up after the run finished; nothing left to drive.
+			return ctrl.Result{}, r.releaseExecution(ctx, &execution)
+		}
+		if execution.CancelRequested() {
+			// Cancelled before a Job was started: nothing to interrupt.
+			execution.Status.Phase = "Cancelled"
+			execution.Status.Summary = "Cancelled before the run started"
+			if err := r.Status().Update(ctx, &execution); err != nil {
+				return ctrl.Result{}, err
+			}
+			log.Info("TofuExecution cancelled before start")
+			r.Recorder.Event(&execution, corev1.EventTypeNormal, eventCancelled, execution.Status.Summary)
+			return ctrl.Result{}, r.releaseModuleLock(ctx, &execution)
+		}
+
+		if granted, err := r.moduleReferenceGranted(ctx, &execution); err != nil {
+			log.Error(err, "Unable to check TofuModuleGrants")
+			return ctrl.Result{}, err
+		} else if !granted {
+			return r.rejectExecution(ctx, &execution, &rejectionError{
+				reason: fmt.Sprintf("no TofuModuleGrant in namespace %s permits references to module %s", moduleKey(&execution).Namespace, moduleKey(&execution).Name),
+			})
+		}
+
+		if execution.Spec.PlanRef != "" {
+			// Other errors, including rejections, surface when the Job is constructed.
+			if digest, err := r.planLockfile(ctx, &execution); err == nil && digest == "" {
+				return r.queueExecution(ctx, &execution, fmt.Sprintf("Waiting for plan %s to finish", execution.Spec.PlanRef))
+			}
+		}
+
+		admitted, reason, err := r.admitExecution(ctx, &execution)
 		if err != nil {
-			log.Error(err, "Unable to construct Job from TofuExecution")
+			log.Error(err, "Unable to evaluate execution capacity")
 			return ctrl.Result{}, err
 		}
+		if !admitted {
+			return r.queueExecution(ctx, &execution, reason)
+		}
 
-		if err := r.Create(ctx, newJob); err != nil {
-			log.Error(err, "Unable to create Job for TofuExecution")
+		acquired, holder, err := r.acquireModuleLock(ctx, &execution)
+		if err != nil {
+			log.Error(err, "Unable to acquire module lock")
+			return ctrl.Result{}, err
+		}
+		if !acquired {
+			reason := "Waiting for the module lock"
+			if holder != "" {
+				reason = fmt.Sprintf("Waiting for the module lock held by %s", holder)
+			}
+			return r.queueExecution(ctx, &execution, reason)
+		}
+
+		newJob, err := r.createJob(ctx, &execution)
+		var rejected *rejectionError
+		if errors.As(err, &rejected) {
+			return r.rejectExecution(ctx, &execution, rejected)
+		}
+		if err != nil {
 			return ctrl.Result{}, err
 		}
 
 		log.Info("Created new Job for TofuExecution", "Job", newJob.Name)
+		r.Recorder.Eventf(&execution, corev1.EventTypeNormal, eventJobCreated, "Created Job %s", newJob.Name)
+		observeQueueWait(&execution, time.Now())
+
+		// Record the Job right away so concurrency accounting sees the slot as taken.
+		execution.Status.JobName = newJob.Name
+		execution.Status.Image = newJob.Spec.Template.Spec.Containers[0].Image
+		execution.Status.Phase = "Pending"
+		if err := r.Status().Update(ctx, &execution); err != nil {
+			return ctrl.Result{}, err
+		}
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	cancelling := execution.CancelRequested() && !isExecutionTerminal(execution.Status.Phase) && !jobFinished(job)
+	if cancelling && !ptr.Deref(job.Spec.Suspend, false) {
+		// Suspending the Job makes the Job controller terminate its pods gracefully: the engine is
+		// interrupted and gets the termination grace period to release its state lock.
+		job.Spec.Suspend = ptr.To(true)
+		if err := r.Update(ctx, job); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("Suspended Job to cancel TofuExecution", "job", job.Name)
+	}
+
 	var phase string
-	if job.Status.Failed > 0 {
+	if isExecutionTerminal(execution.Status.Phase) {
+		// Terminal phases are final; a late cancellation must not rewrite the outcome.
+		phase = execution.Status.Phase
+	} else if jobDeadlineExceeded(job) {
+		phase = "TimedOut"
+	} else if cancelling {
+		phase = "Cancelled"
+		if jobPodsRunning(job) {
+			phase = "Running"
+		}
+	} else if job.Status.Failed > 0 {
 		phase = "Failed"
 	} else if job.Status.Succeeded > 0 {
 		phase = "Succeeded"
-	} else if job.Status.Active > 0 || job.Status.Terminating != nil || job.Status.UncountedTerminatedPods != nil {
+	} else if jobPodsRunning(job) {
 		phase = "Running"
 	} else {
 		phase = "Pending"
@@ -112,10 +232,62 @@ func (r *TofuExecutionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		}
 	}
 
-	if job.Status.CompletionTime != nil {
+	// Failed Jobs have no completion time; fall back to when the failure was recorded.
+	finishedAt := job.Status.CompletionTime
+	if finishedAt == nil {
+		if condition := jobFailedCondition(job); condition != nil {
+			finishedAt = &condition.LastTransitionTime
+		}
+	}
+	if finishedAt != nil {
 		if execution.Status.ExecutionSummary.FinishedAt == nil ||
-			!execution.Status.ExecutionSummary.FinishedAt.Equal(job.Status.CompletionTime) {
-			execution.Status.ExecutionSummary.FinishedAt = job.Status.CompletionTime.DeepCopy()
+			!execution.Status.ExecutionSummary.FinishedAt.Equal(finishedAt) {
+			execution.Status.ExecutionSummary.FinishedAt = finishedAt.DeepCopy()
+			summaryChanged = true
+		}
+	}
+
+	if phase == "Failed" && execution.Status.FailureReason == "" {
+		reason, err := r.classifyFailure(ctx, job)
+		if err != nil {
+			log.Error(err, "Unable to classify execution failure")
+			return ctrl.Result{}, err
+		}
+		execution.Status.FailureReason = reason
+		summaryChanged = true
+	}
+
+	policies := jobPolicies(job)
+	recordPolicies := len(policies) > 0 && execution.Status.Policies == nil
+	recordChanges := phase == "Succeeded" && execution.Spec.Action == "plan" && execution.Status.HasChanges == nil
+	var planned *plannedResources
+	var phases []executionPhase
+	finishing := isExecutionTerminal(phase) && !isExecutionTerminal(execution.Status.Phase)
+	if isExecutionTerminal(phase) && (finishing || execution.Status.Lockfile == nil || recordPolicies || recordChanges) {
+		terminated, err := r.latestTermination(ctx, job, false)
+		if err != nil {
+			log.Error(err, "Unable to read the execution report")
+			return ctrl.Result{}, err
+		}
+		if terminated != nil {
+			phases = parseExecutionPhases(terminated.Message)
+			if lockfile := parseLockfileSummary(terminated.Message); lockfile != nil && execution.Status.Lockfile == nil {
+				execution.Status.Lockfile = lockfile
+				summaryChanged = true
+			}
+			if results := parsePolicyResults(terminated.Message, policies); results != nil && recordPolicies {
+				execution.Status.Policies = results
+				summaryChanged = true
+			}
+			if changes := parsePlanChanges(terminated.Message); changes != nil && recordChanges {
+				execution.Status.HasChanges = changes
+				summaryChanged = true
+				planned = parsePlannedResources(terminated.Message)
+			}
+		}
+	}
+	if expected := jobPlanLockfile(job); expected != "" && isExecutionTerminal(phase) {
+		if setLockfileMatchedCondition(&execution, expected) {
 			summaryChanged = true
 		}
 	}
@@ -126,8 +298,21 @@ func (r *TofuExecutionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		summaryMsg = "Plan completed successfully"
 	case "Failed":
 		summaryMsg = "Plan failed"
+		if execution.Status.FailureReason != "" {
+			summaryMsg = fmt.Sprintf("Plan failed: %s", execution.Status.FailureReason)
+		}
+	case "TimedOut":
+		summaryMsg = fmt.Sprintf("%s timed out", actionTitle(execution.Spec.Action))
+		if execution.Spec.Timeout != nil {
+			summaryMsg = fmt.Sprintf("%s timed out after %s", actionTitle(execution.Spec.Action), execution.Spec.Timeout.Duration)
+		}
+	case "Cancelled":
+		summaryMsg = fmt.Sprintf("%s cancelled", actionTitle(execution.Spec.Action))
 	case "Running":
 		summaryMsg = "Plan is running"
+		if cancelling {
+			summaryMsg = "Cancelling: waiting for the engine to stop"
+		}
 	default:
 		summaryMsg = "Plan pending"
 	}
@@ -138,6 +323,7 @@ func (r *TofuExecutionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	statusChanged := summaryChanged
+	previousPhase := execution.Status.Phase
 	if execution.Status.Phase != phase {
 		execution.Status.Phase = phase
 		statusChanged = true
@@ -148,17 +334,129 @@ func (r *TofuExecutionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			return ctrl.Result{}, err
 		}
 		log.Info("TofuExecution status updated", "phase", phase, "job", job.Name)
+		if phase == "Running" && previousPhase != "Running" && !cancelling {
+			r.Recorder.Eventf(&execution, corev1.EventTypeNormal, eventStarted, "Job %s started", job.Name)
+		}
+		if isExecutionTerminal(phase) && !isExecutionTerminal(previousPhase) {
+			recordExecutionOutcome(r.Recorder, &execution, planned)
+			observeExecutionOutcome(&execution, executionEngine(&execution), planned)
+			traceExecution(ctx, &execution, phases)
+			if execution.Spec.Action == "plan" {
+				r.commentPullRequest(ctx, &execution, job, planned)
+			}
+		}
 	} else {
 		log.Info("TofuExecution reconciled, nothing to update", "phase", phase, "job", job.Name)
 	}
 
 	if phase == "Running" || phase == "Pending" {
+		if _, _, err := r.acquireModuleLock(ctx, &execution); err != nil {
+			log.Error(err, "Unable to renew module lock")
+		}
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 	}
 
+	if err := r.releaseExecution(ctx, &execution); err != nil {
+		log.Error(err, "Unable to release the finished execution")
+		return ctrl.Result{}, err
+	}
 	return ctrl.Result{}, nil
 }
 
+// createJob constructs the execution's Job, grants its pod access to the execution's resources
+// and creates it. The Job continues the execution's trace from the span covering its creation.
+func (r *TofuExecutionReconciler) createJob(ctx context.Context, execution *opentofuv1alpha1.TofuExecution) (*batchv1.Job, error) {
+	log := logf.FromContext(ctx)
+	ctx, span := tracing.Tracer().Start(ctx, "CreateJob")
+	defer span.End()
+
+	job, err := r.constructJobFromExecution(ctx, execution)
+	if err != nil {
+		var rejected *rejectionError
+		if !errors.As(err, &rejected) {
+			log.Error(err, "Unable to construct Job from TofuExecution")
+		}
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if err := r.grantExecutionAccess(ctx, execution, &job.Spec.Template.Spec); err != nil {
+		log.Error(err, "Unable to grant the execution access to its resources")
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if err := r.Create(ctx, job); err != nil {
+		log.Error(err, "Unable to create Job for TofuExecution")
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("k8s.job.name", job.Name))
+	return job, nil
+}
+
+// releaseExecution releases the module lock and the access granted to a finished execution.
+func (r *TofuExecutionReconciler) releaseExecution(ctx context.Context, execution *opentofuv1alpha1.TofuExecution) error {
+	if err := r.releaseModuleLock(ctx, execution); err != nil {
+		return err
+	}
+	return r.revokeExecutionAccess(ctx, execution)
+}
+
+// rejectionError marks problems with the execution itself, such as an invalid engine version or
+// a pod violating the namespace's pod security, that retrying cannot fix.
+type rejectionError struct {
+	reason string
+}
+
+func (e *rejectionError) Error() string {
+	return e.reason
+}
+
+// rejectExecution fails the execution with FailureRejected and releases its module lock.
+func (r *TofuExecutionReconciler) rejectExecution(ctx context.Context, execution *opentofuv1alpha1.TofuExecution, rejected *rejectionError) (ctrl.Result, error) {
+	logf.FromContext(ctx).Info("Rejected TofuExecution", "reason", rejected.Error())
+	execution.Status.Phase = "Failed"
+	execution.Status.FailureReason = opentofuv1alpha1.FailureRejected
+	execution.Status.Summary = "Rejected: " + rejected.Error()
+	if err := r.Status().Update(ctx, execution); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Event(execution, corev1.EventTypeWarning, eventRejected, rejected.Error())
+	return ctrl.Result{}, r.releaseModuleLock(ctx, execution)
+}
+
+// moduleReferenceGranted reports whether the execution may run its module. Executions of modules
+// in other namespaces need a TofuModuleGrant there, checked again here in case the webhook was
+// bypassed or the grant was revoked since admission.
+func (r *TofuExecutionReconciler) moduleReferenceGranted(ctx context.Context, execution *opentofuv1alpha1.TofuExecution) (bool, error) {
+	module := moduleKey(execution)
+	if module.Namespace == execution.Namespace {
+		return true, nil
+	}
+	kind, err := modulegrant.ExecutionKind(ctx, r.Client, execution)
+	if err != nil {
+		return false, err
+	}
+	return modulegrant.Permitted(ctx, r.Client, kind, execution.Namespace, module)
+}
+
+// queueExecution parks the execution in the Queued phase while it waits for capacity or for
+// another execution to release the module lock.
+func (r *TofuExecutionReconciler) queueExecution(ctx context.Context, execution *opentofuv1alpha1.TofuExecution, reason string) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+	if execution.Status.Phase != "Queued" || execution.Status.Summary != reason {
+		execution.Status.Phase = "Queued"
+		execution.Status.Summary = reason
+		if err := r.Status().Update(ctx, execution); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("TofuExecution queued", "reason", reason)
+		r.Recorder.Event(execution, corev1.EventTypeNormal, eventQueued, reason)
+	}
+	return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+}
+
 func (r *TofuExecutionReconciler) getModule(ctx context.Context, module *opentofuv1alpha1.TofuModule, name types.NamespacedName) error {
 	if err := r.Get(ctx, name, module); err != nil {
 		return err
@@ -173,48 +471,93 @@ func (r *TofuExecutionReconciler) constructJobFromExecution(ctx context.Context,
 		jobName = execution.Spec.JobTemplate.Metadata.GenerateName
 	}
 	engine := execution.Spec.Engine
-	engineName := engine.Name
-	if engineName == "" {
-		engineName = "tofu"
-	}
+	engineName := executionEngine(execution)
 
-	moduleName := types.NamespacedName{
-		Namespace: execution.Spec.ModuleRef.Namespace,
-		Name:      execution.Spec.ModuleRef.Name,
-	}
 	module := opentofuv1alpha1.TofuModule{}
-	if err := r.Get(ctx, moduleName, &module); err != nil {
+	if err := r.Get(ctx, moduleKey(execution), &module); err != nil {
 		return nil, err
 	}
 	workdir := module.Spec.Workdir
 	if workdir == "" {
-		workdir = "."
+		workdir = opentofuv1alpha1.DefaultWorkdir
 	}
 	execCfg, err := settings.Execution()
 	if err != nil {
 		return nil, fmt.Errorf("invalid settings: %w", err)
 	}
-	image := execCfg.DefaultImage
-	switch engineName {
-	case "terraform":
-		if engine.Version != "" {
-			image = "hashicorp/terraform:" + engine.Version
+	image, installEngine, err := resolveEngineImage(execCfg, engineName, engine.Version)
+	if err != nil {
+		return nil, err
+	}
+	selectWorkspace := ""
+	if execution.Spec.Workspace != "" {
+		selectWorkspace = fmt.Sprintf(`
+	%s workspace select -or-create "$%s" || exit %d;`, engineName, workspaceEnvVar, exitCodeInitError)
+	}
+	checkout := ""
+	if module.Spec.Version != "" {
+		checkout = "\n\t" + checkoutScript()
+	}
+	prepare := ""
+	if installEngine {
+		prepare = "\n\t" + engineInstallScript(engineName)
+	}
+	if script := providerInstallationScript(execCfg.ProviderInstallation); script != "" {
+		prepare += "\n\t" + script
+	}
+	policies, err := r.modulePolicies(ctx, &module, execCfg.PolicyNamespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) > 0 && execCfg.AgentImage == "" {
+		return nil, &rejectionError{reason: fmt.Sprintf("TofuPolicies select module %s but no agent image is configured to check them", module.Name)}
+	}
+	run := engineScript(engineName, execution.Spec.Action)
+	if len(policies) > 0 {
+		run = policyEngineScript(engineName, execution.Spec.Action)
+	}
+	cmd := fmt.Sprintf(`%s
+	mkdir workspace;
+	cd workspace;
+	%s
+	git clone %s . || exit %d;%s
+	cd %s;
+	%s%s
+	%s init%s || exit %d;%s
+	%s
+	%s`, exitReportTrap(), phaseMark("clone"), module.Spec.Source, exitCodeInitError, checkout, workdir, phaseMark("init"), prepare,
+		engineName, initArgs(execution), exitCodeInitError, selectWorkspace, lockfileScript(), run)
+	env, err := engineEnv(&module, execution)
+	if err != nil {
+		return nil, err
+	}
+	if installEngine {
+		env = append(engineInstallEnv(execCfg.EngineInstall, engineName, engine.Version), env...)
+	}
+	env = append(providerInstallationEnv(execCfg.ProviderInstallation), env...)
+	tracingCfg, err := settings.Tracing()
+	if err != nil {
+		return nil, fmt.Errorf("invalid settings: %w", err)
+	}
+	traceEnv := tracingEnv(ctx, tracingCfg)
+	env = append(env, traceEnv...)
+	if execution.Spec.PlanRef != "" {
+		digest, err := r.planLockfile(ctx, execution)
+		if err != nil {
+			return nil, err
 		}
-	case "tofu":
-		if engine.Version != "" {
-			image = "ghcr.io/opentofu/opentofu:" + engine.Version
+		if digest == "" {
+			return nil, fmt.Errorf("plan %s has not finished", execution.Spec.PlanRef)
 		}
-	default:
-		if engine.Version != "" {
-			image = fmt.Sprintf("%s:%s", engineName, engine.Version)
+		env = append(env, corev1.EnvVar{Name: planLockfileEnvVar, Value: digest})
+	}
+	if len(policies) > 0 {
+		policyEnv, err := policiesEnv(policies)
+		if err != nil {
+			return nil, err
 		}
+		env = append(env, policyEnv)
 	}
-	cmd := fmt.Sprintf(`mkdir workspace;
-	cd workspace;
-	git clone %s .;
-	cd %s;
-	%s init;
-	%s %s`, module.Spec.Source, workdir, engineName, engineName, execution.Spec.Action)
 	// TODO: replace the inline shell script with the dedicated agent binary once the agent pipeline
 	// is implemented (will handle variables, state backends, and richer status reporting).
 	jobLabels := maps.Clone(execution.Spec.JobTemplate.Metadata.Labels)
@@ -232,11 +575,93 @@ func (r *TofuExecutionReconciler) constructJobFromExecution(ctx context.Context,
 		podLabels = make(map[string]string)
 	}
 
+	if len(traceEnv) > 0 {
+		jobAnnotations[tracing.TraceParentAnnotation] = tracing.TraceParent(ctx)
+	}
+
 	podAnnotations := maps.Clone(execution.Spec.JobTemplate.Metadata.Annotations)
 	if podAnnotations == nil {
 		podAnnotations = make(map[string]string)
 	}
 
+	template := execution.Spec.JobTemplate
+	for _, e := range template.Env {
+		if strings.HasPrefix(e.Name, reservedEnvPrefix) {
+			return nil, &rejectionError{reason: fmt.Sprintf("jobTemplate.env must not set %s: variables prefixed with %s are reserved", e.Name, reservedEnvPrefix)}
+		}
+	}
+	serviceAccount, err := r.executionServiceAccount(ctx, execution.Namespace, execCfg.Identity)
+	if err != nil {
+		return nil, err
+	}
+	podServiceAccount := template.ServiceAccountName
+	if podServiceAccount == "" {
+		podServiceAccount = serviceAccount
+	}
+	container := corev1.Container{
+		Name:         engineContainerName,
+		Image:        image,
+		Command:      []string{"sh", "-exc", cmd},
+		Env:          append(env, template.Env...),
+		EnvFrom:      template.EnvFrom,
+		VolumeMounts: template.VolumeMounts,
+	}
+	if template.Resources != nil {
+		container.Resources = *template.Resources
+	}
+	podSpec := corev1.PodSpec{
+		RestartPolicy:                 corev1.RestartPolicyNever,
+		TerminationGracePeriodSeconds: ptr.To(int64(engineGracePeriod.Seconds())),
+		ServiceAccountName:            podServiceAccount,
+		NodeSelector:                  template.NodeSelector,
+		Tolerations:                   template.Tolerations,
+		Affinity:                      template.Affinity,
+		Volumes:                       template.Volumes,
+		ImagePullSecrets:              template.ImagePullSecrets,
+		PriorityClassName:             template.PriorityClassName,
+		Containers:                    []corev1.Container{container},
+	}
+	if len(policies) > 0 {
+		addPolicyAgent(&podSpec, execCfg.AgentImage)
+	}
+	hardenPodSpec(&podSpec, execCfg.PodSecurity)
+	if execCfg.Identity.ManageRBAC {
+		// The agent needs the token to use the access grantExecutionAccess gives it.
+		podSpec.AutomountServiceAccountToken = ptr.To(true)
+	}
+	generated := podSpec.DeepCopy()
+	if template.SecurityContext != nil {
+		// Merge rather than replace, so a template setting e.g. only fsGroup keeps the other defaults.
+		raw, err := json.Marshal(map[string]any{"securityContext": template.SecurityContext})
+		if err != nil {
+			return nil, err
+		}
+		if podSpec, err = patchPodSpec(podSpec, &apiextv1.JSON{Raw: raw}); err != nil {
+			return nil, err
+		}
+	}
+	podSpec, err = patchPodSpec(podSpec, template.PodSpecPatch)
+	if err != nil {
+		return nil, err
+	}
+	if changed := protectedPodSpecChanges(generated, &podSpec); len(changed) > 0 {
+		return nil, &rejectionError{reason: "podSpecPatch must not change " + strings.Join(changed, ", ")}
+	}
+
+	if serviceAccount != "" && podSpec.ServiceAccountName != serviceAccount {
+		return nil, &rejectionError{reason: fmt.Sprintf("executions of namespace %s must run as service account %s, not %q", execution.Namespace, serviceAccount, podSpec.ServiceAccountName)}
+	}
+
+	enforced, err := r.enforcesPodSecurity(ctx, execution.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if enforced {
+		if violations := podSecurityViolations(&podSpec); len(violations) > 0 {
+			return nil, &rejectionError{reason: "pod violates the restricted pod security profile: " + strings.Join(violations, "; ")}
+		}
+	}
+
 	newJob := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: jobName + "-",
@@ -245,28 +670,22 @@ func (r *TofuExecutionReconciler) constructJobFromExecution(ctx context.Context,
 			Annotations:  jobAnnotations,
 		},
 		Spec: batchv1.JobSpec{
+			TTLSecondsAfterFinished: template.TTLSecondsAfterFinished,
+			BackoffLimit:            template.BackoffLimit,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      podLabels,
 					Annotations: podAnnotations,
 				},
-				Spec: corev1.PodSpec{
-					RestartPolicy:      corev1.RestartPolicyNever,
-					ServiceAccountName: execution.Spec.JobTemplate.ServiceAccountName,
-					Containers: []corev1.Container{
-						{
-							Name:    "simulate-tofu",
-							Image:   image,
-							Command: []string{"sh", "-exc", cmd},
-							Env:     execution.Spec.JobTemplate.Env,
-							EnvFrom: execution.Spec.JobTemplate.EnvFrom,
-						},
-					},
-				},
+				Spec: podSpec,
 			},
 		},
 	}
 
+	if execution.Spec.Timeout != nil {
+		newJob.Spec.ActiveDeadlineSeconds = ptr.To(int64(execution.Spec.Timeout.Seconds()))
+	}
+
 	if err := ctrl.SetControllerReference(execution, newJob, r.Scheme); err != nil {
 		return nil, err
 	}
@@ -274,6 +693,256 @@ func (r *TofuExecutionReconciler) constructJobFromExecution(ctx context.Context,
 	return newJob, nil
 }
 
+// patchPodSpec applies the template's strategic merge patch, if any, to the generated pod spec.
+func patchPodSpec(spec corev1.PodSpec, patch *apiextv1.JSON) (corev1.PodSpec, error) {
+	if patch == nil || len(patch.Raw) == 0 {
+		return spec, nil
+	}
+	original, err := json.Marshal(spec)
+	if err != nil {
+		return spec, err
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, patch.Raw, corev1.PodSpec{})
+	if err != nil {
+		return spec, fmt.Errorf("invalid podSpecPatch: %w", err)
+	}
+	var result corev1.PodSpec
+	if err := json.Unmarshal(patched, &result); err != nil {
+		return spec, fmt.Errorf("invalid podSpecPatch: %w", err)
+	}
+	return result, nil
+}
+
+// protectedPodSpecChanges lists what a patch changed in the parts of the generated pod spec
+// that enforce policies and plan lock files: the engine script, the reserved environment
+// variables the engine container finally sees, and the agent with its volume.
+func protectedPodSpecChanges(generated, patched *corev1.PodSpec) []string {
+	var changed []string
+	before, after := findContainer(generated.Containers, engineContainerName), findContainer(patched.Containers, engineContainerName)
+	if after == nil {
+		return []string{"the engine container"}
+	}
+	if !slices.Equal(before.Command, after.Command) || !slices.Equal(before.Args, after.Args) {
+		changed = append(changed, "the engine command")
+	}
+	if !equality.Semantic.DeepEqual(reservedEnv(before.Env), reservedEnv(after.Env)) {
+		changed = append(changed, "the "+reservedEnvPrefix+"* variables")
+	}
+	if agent := findContainer(generated.InitContainers, agentInitContainerName); agent != nil {
+		patchedAgent := findContainer(patched.InitContainers, agentInitContainerName)
+		if patchedAgent == nil || !equality.Semantic.DeepEqual(agent, patchedAgent) {
+			changed = append(changed, "the "+agentInitContainerName+" init container")
+		}
+		volume := func(spec *corev1.PodSpec) *corev1.Volume {
+			for i := range spec.Volumes {
+				if spec.Volumes[i].Name == agentVolumeName {
+					return &spec.Volumes[i]
+				}
+			}
+			return nil
+		}
+		if !equality.Semantic.DeepEqual(volume(generated), volume(patched)) {
+			changed = append(changed, "the "+agentVolumeName+" volume")
+		}
+	}
+	return changed
+}
+
+func findContainer(containers []corev1.Container, name string) *corev1.Container {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+// reservedEnv returns the reserved variables of env as the container sees them: the last of
+// duplicate entries wins.
+func reservedEnv(env []corev1.EnvVar) map[string]corev1.EnvVar {
+	reserved := map[string]corev1.EnvVar{}
+	for _, e := range env {
+		if strings.HasPrefix(e.Name, reservedEnvPrefix) {
+			reserved[e.Name] = e
+		}
+	}
+	return reserved
+}
+
+// engineScript runs the engine action. Failures exit with a code identifying lock contention
+// or the failed action.
+func engineScript(engineName, action string) string {
+	if action == "plan" {
+		return phaseMark("plan") + "\n\t" + planStep(engineName, fmt.Sprintf("%s plan -out=%s", engineName, planFile))
+	}
+	return phaseMark(action) + "\n\t" + engineStep(engineName+" "+action, actionExitCode(action))
+}
+
+// exitReportTrap writes the reports kept for the end to the termination message when the engine
+// script exits, whether it succeeds or fails: the phase lines, then the lock file report.
+func exitReportTrap() string {
+	return fmt.Sprintf("soyplane_report() {\n\t%s\n\t%s\n\t}; trap soyplane_report EXIT;", phaseReportScript(), lockfileReportScript())
+}
+
+// checkoutScript checks out the module version after cloning. Branches, tags and commits are in
+// the clone; pull request refs are not, so refs the clone lacks are fetched first.
+func checkoutScript() string {
+	return fmt.Sprintf(`{ git checkout -q "$%[1]s" || { git fetch -q origin "$%[1]s" && git checkout -q FETCH_HEAD; }; } || exit %[2]d;`,
+		moduleRefEnvVar, exitCodeInitError)
+}
+
+// engineStep runs an engine command in the background and forwards SIGTERM as SIGINT, so that
+// a cancelled or timed out run stops gracefully and releases its state lock. The engine would not
+// see the signal otherwise: the shell only runs traps once its foreground command returns.
+// Failures exit with exitCode, or with the lock contention code when the state lock was taken.
+func engineStep(command string, exitCode int) string {
+	return reportingEngineStep(command, exitCode, "")
+}
+
+// planStep runs a plan command saving the plan to planFile with -detailed-exitcode, under which
+// the engine exits with 2 when the plan has changes. It reports whether the plan had any through
+// the termination message and, when it did, how many resources it adds, changes and destroys.
+func planStep(engineName, command string) string {
+	report := fmt.Sprintf(`if [ $rc -eq 2 ]; then rc=0; echo "%[1]s true" >> /dev/termination-log;
+	%[3]s show -no-color %[4]s | sed -n 's/.* \([0-9][0-9]*\) to add, \([0-9][0-9]*\) to change, \([0-9][0-9]*\) to destroy.*/%[2]s \1 \2 \3/p' >> /dev/termination-log;
+	elif [ $rc -eq 0 ]; then echo "%[1]s false" >> /dev/termination-log; fi;
+	`, planChangesReport, plannedResourcesReport, engineName, planFile)
+	return reportingEngineStep(command+" -detailed-exitcode", exitCodePlanError, report)
+}
+
+// reportingEngineStep is engineStep running report, which may reset rc, before checking the
+// exit status.
+func reportingEngineStep(command string, exitCode int, report string) string {
+	return fmt.Sprintf(`%[1]s 2>/tmp/engine.err &
+	engine=$!;
+	trap 'kill -INT $engine; wait $engine' TERM INT;
+	rc=0;
+	wait $engine || rc=$?;
+	cat /tmp/engine.err >&2;
+	%[4]sif [ $rc -ne 0 ]; then grep -q "Error acquiring the state lock" /tmp/engine.err && exit %[2]d; exit %[3]d; fi`,
+		command, exitCodeLockContention, exitCode, report)
+}
+
+// parsePlanChanges reads the report written by planStep. It returns nil when the message holds
+// no report.
+func parsePlanChanges(message string) *bool {
+	for _, line := range strings.Split(message, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == planChangesReport {
+			return ptr.To(fields[1] == "true")
+		}
+	}
+	return nil
+}
+
+// plannedResources counts the resource changes a plan proposes.
+type plannedResources struct {
+	add, change, destroy int
+}
+
+// parsePlannedResources reads the resource counts written by planStep. It returns nil when the
+// message holds no counts.
+func parsePlannedResources(message string) *plannedResources {
+	for _, line := range strings.Split(message, "\n") {
+		var planned plannedResources
+		var prefix string
+		if _, err := fmt.Sscanf(line, "%s %d %d %d", &prefix, &planned.add, &planned.change, &planned.destroy); err == nil && prefix == plannedResourcesReport {
+			return &planned
+		}
+	}
+	return nil
+}
+
+// jobFinished reports whether the Job reached a terminal state.
+func jobFinished(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	// Failed pods alone do not finish a Job: it may still retry them.
+	return job.Status.Succeeded > 0
+}
+
+// jobPodsRunning reports whether the Job still has pods that have not fully terminated.
+func jobPodsRunning(job *batchv1.Job) bool {
+	return job.Status.Active > 0 || ptr.Deref(job.Status.Terminating, 0) > 0 || job.Status.UncountedTerminatedPods != nil
+}
+
+// jobFailedCondition returns the Job's Failed condition when it is set.
+func jobFailedCondition(job *batchv1.Job) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		condition := &job.Status.Conditions[i]
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return condition
+		}
+	}
+	return nil
+}
+
+// jobDeadlineExceeded reports whether the Job was stopped because it ran past activeDeadlineSeconds.
+func jobDeadlineExceeded(job *batchv1.Job) bool {
+	condition := jobFailedCondition(job)
+	return condition != nil && condition.Reason == batchv1.JobReasonDeadlineExceeded
+}
+
+// engineEnv renders the module variables, overlaid with the execution's, as TF_VAR_*
+// environment variables together with the selected workspace.
+func engineEnv(module *opentofuv1alpha1.TofuModule, execution *opentofuv1alpha1.TofuExecution) ([]corev1.EnvVar, error) {
+	variables := maps.Clone(module.Spec.Variables)
+	if variables == nil {
+		variables = make(map[string]apiextv1.JSON)
+	}
+	maps.Copy(variables, execution.Spec.Variables)
+
+	valueSources := maps.Clone(module.Spec.ValueSources)
+	if valueSources == nil {
+		valueSources = make(map[string]opentofuv1alpha1.ValueFrom)
+	}
+	maps.Copy(valueSources, execution.Spec.ValueSources)
+
+	env := make([]corev1.EnvVar, 0, len(variables)+len(valueSources)+1)
+	for _, name := range slices.Sorted(maps.Keys(variables)) {
+		if _, ok := valueSources[name]; ok {
+			continue
+		}
+		value := variables[name]
+		var str string
+		if err := json.Unmarshal(value.Raw, &str); err != nil {
+			// Non-string values are passed through as literals; the engine parses them as HCL.
+			str = string(value.Raw)
+		}
+		env = append(env, corev1.EnvVar{Name: "TF_VAR_" + name, Value: str})
+	}
+	for _, name := range slices.Sorted(maps.Keys(valueSources)) {
+		source := valueSources[name]
+		envVar := corev1.EnvVar{Name: "TF_VAR_" + name, ValueFrom: &corev1.EnvVarSource{}}
+		switch {
+		case source.SecretRef != nil:
+			envVar.ValueFrom.SecretKeyRef = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: source.SecretRef.Name},
+				Key:                  source.SecretRef.Key,
+			}
+		case source.ConfigMapRef != nil:
+			envVar.ValueFrom.ConfigMapKeyRef = &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: source.ConfigMapRef.Name},
+				Key:                  source.ConfigMapRef.Key,
+			}
+		default:
+			return nil, fmt.Errorf("value source %q references neither a Secret nor a ConfigMap", name)
+		}
+		env = append(env, envVar)
+	}
+
+	if execution.Spec.Workspace != "" {
+		env = append(env, corev1.EnvVar{Name: workspaceEnvVar, Value: execution.Spec.Workspace})
+	}
+	if module.Spec.Version != "" {
+		env = append(env, corev1.EnvVar{Name: moduleRefEnvVar, Value: module.Spec.Version})
+	}
+	return env, nil
+}
+
 func (r *TofuExecutionReconciler) job(ctx context.Context, execution *opentofuv1alpha1.TofuExecution) (*batchv1.Job, error) {
 	log := logf.FromContext(ctx)
 	var childJobs batchv1.JobList
@@ -18,19 +18,47 @@ package opentofu
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	batchv1 "k8s.io/api/batch/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	"github.com/soyplane-io/soyplane/internal/policy"
+	settings "github.com/soyplane-io/soyplane/internal/settings"
 )
 
 var _ = Describe("TofuExecution Controller", func() {
@@ -120,8 +148,9 @@ var _ = Describe("TofuExecution Controller", func() {
 		})
 		It("should create a Job and update status when the Job completes", func() {
 			controllerReconciler := &TofuExecutionReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(100),
 			}
 
 			By("Reconciling to create the Job")
@@ -206,7 +235,7 @@ var _ = Describe("TofuExecution job helper", func() {
 		}
 
 		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(exec, oldJob, newJob).Build()
-		reconciler := &TofuExecutionReconciler{Client: fakeClient, Scheme: scheme}
+		reconciler := &TofuExecutionReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(100)}
 
 		ctx := context.Background()
 		reqJob, err := reconciler.job(ctx, exec)
@@ -219,3 +248,1407 @@ var _ = Describe("TofuExecution job helper", func() {
 		Expect(remaining.Items[0].Name).To(Equal("new-job"))
 	})
 })
+
+var _ = Describe("TofuExecution module lock", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *TofuExecutionReconciler
+	)
+
+	newExecution := func(name string) *opentofuv1alpha1.TofuExecution {
+		return &opentofuv1alpha1.TofuExecution{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name + "-uid")},
+			Spec: opentofuv1alpha1.TofuExecutionSpec{
+				Action:    "plan",
+				ModuleRef: opentofuv1alpha1.ObjectRef{Name: "network", Namespace: "default"},
+			},
+		}
+	}
+	reconcileExecution := func(name string) *opentofuv1alpha1.TofuExecution {
+		key := types.NamespacedName{Name: name, Namespace: "default"}
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		exec := &opentofuv1alpha1.TofuExecution{}
+		Expect(fakeClient.Get(ctx, key, exec)).To(Succeed())
+		return exec
+	}
+	ownedJobs := func(exec *opentofuv1alpha1.TofuExecution) []batchv1.Job {
+		var jobs batchv1.JobList
+		Expect(fakeClient.List(ctx, &jobs)).To(Succeed())
+		owned := []batchv1.Job{}
+		for _, job := range jobs.Items {
+			if metav1.IsControlledBy(&job, exec) {
+				owned = append(owned, job)
+			}
+		}
+		return owned
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(opentofuv1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		Expect(coordinationv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+		module := &opentofuv1alpha1.TofuModule{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "default"},
+			Spec:       opentofuv1alpha1.TofuModuleSpec{Source: "https://example.com/repo.git"},
+		}
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(module, newExecution("first"), newExecution("second")).
+			WithStatusSubresource(&opentofuv1alpha1.TofuExecution{}, &batchv1.Job{}).
+			Build()
+		reconciler = &TofuExecutionReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(100)}
+	})
+
+	It("queues executions against a locked module until the holder finishes", func() {
+		first := reconcileExecution("first")
+		Expect(ownedJobs(first)).To(HaveLen(1))
+
+		second := reconcileExecution("second")
+		Expect(second.Status.Phase).To(Equal("Queued"))
+		Expect(second.Status.Summary).To(ContainSubstring("default/first"))
+		Expect(ownedJobs(second)).To(BeEmpty())
+
+		job := ownedJobs(first)[0]
+		job.Status.Succeeded = 1
+		Expect(fakeClient.Status().Update(ctx, &job)).To(Succeed())
+		first = reconcileExecution("first")
+		Expect(first.Status.Phase).To(Equal("Succeeded"))
+
+		var lease coordinationv1.Lease
+		err := fakeClient.Get(ctx, moduleLockKey(first), &lease)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		second = reconcileExecution("second")
+		Expect(ownedJobs(second)).To(HaveLen(1))
+	})
+
+	It("takes over the lock when the holder's Job disappeared", func() {
+		first := reconcileExecution("first")
+		first = reconcileExecution("first")
+		Expect(first.Status.JobName).NotTo(BeEmpty())
+
+		job := ownedJobs(first)[0]
+		Expect(fakeClient.Delete(ctx, &job)).To(Succeed())
+
+		second := reconcileExecution("second")
+		Expect(ownedJobs(second)).To(HaveLen(1))
+
+		var lease coordinationv1.Lease
+		Expect(fakeClient.Get(ctx, moduleLockKey(second), &lease)).To(Succeed())
+		Expect(*lease.Spec.HolderIdentity).To(Equal("default/second"))
+
+		first = reconcileExecution("first")
+		Expect(first.Status.Phase).To(Equal("Queued"))
+		Expect(ownedJobs(first)).To(BeEmpty())
+	})
+
+	It("uses separate locks per workspace", func() {
+		dev := newExecution("dev")
+		dev.Spec.Workspace = "Dev_EU"
+		Expect(moduleLockKey(dev).Namespace).To(Equal("default"))
+		Expect(moduleLockKey(dev).Name).To(MatchRegexp(`^network-lock-[0-9a-f]{16}$`))
+		Expect(moduleLockKey(newExecution("plain")).Name).To(Equal("network-lock"))
+
+		devEU := newExecution("dev-eu")
+		devEU.Spec.Workspace = "dev-eu"
+		Expect(moduleLockKey(devEU)).NotTo(Equal(moduleLockKey(dev)))
+	})
+
+	It("does not share locks between modules whose names join to the same string", func() {
+		foo := newExecution("foo")
+		foo.Spec.ModuleRef.Name = "foo"
+		foo.Spec.Workspace = "bar"
+		fooBar := newExecution("foo-bar")
+		fooBar.Spec.ModuleRef.Name = "foo-bar"
+		fooBarLock := newExecution("foo-bar-lock")
+		fooBarLock.Spec.ModuleRef.Name = "foo-bar"
+		fooBarLock.Spec.Workspace = "default"
+
+		Expect(moduleLockKey(foo)).NotTo(Equal(moduleLockKey(fooBar)))
+		Expect(moduleLockKey(foo)).NotTo(Equal(moduleLockKey(fooBarLock)))
+		Expect(moduleLockKey(fooBar)).NotTo(Equal(moduleLockKey(fooBarLock)))
+	})
+})
+
+var _ = Describe("TofuExecution cancellation and timeouts", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *TofuExecutionReconciler
+		recorder   *record.FakeRecorder
+	)
+
+	key := types.NamespacedName{Name: "run", Namespace: "default"}
+	reconcileExecution := func() *opentofuv1alpha1.TofuExecution {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		exec := &opentofuv1alpha1.TofuExecution{}
+		Expect(fakeClient.Get(ctx, key, exec)).To(Succeed())
+		return exec
+	}
+	currentJob := func(exec *opentofuv1alpha1.TofuExecution) *batchv1.Job {
+		job := &batchv1.Job{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: exec.Status.JobName, Namespace: "default"}, job)).To(Succeed())
+		return job
+	}
+	setup := func(mutate func(*opentofuv1alpha1.TofuExecution)) {
+		scheme := runtime.NewScheme()
+		Expect(opentofuv1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		Expect(coordinationv1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+		execution := &opentofuv1alpha1.TofuExecution{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, UID: "run-uid"},
+			Spec: opentofuv1alpha1.TofuExecutionSpec{
+				Action:    "apply",
+				ModuleRef: opentofuv1alpha1.ObjectRef{Name: "network", Namespace: "default"},
+			},
+		}
+		mutate(execution)
+		module := &opentofuv1alpha1.TofuModule{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "default"},
+			Spec:       opentofuv1alpha1.TofuModuleSpec{Source: "https://example.com/repo.git"},
+		}
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(module, execution).
+			WithStatusSubresource(&opentofuv1alpha1.TofuExecution{}, &batchv1.Job{}, &corev1.Pod{}).
+			Build()
+		recorder = record.NewFakeRecorder(100)
+		reconciler = &TofuExecutionReconciler{Client: fakeClient, Scheme: scheme, Recorder: recorder}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("maps the timeout to the Job deadline and reports TimedOut", func() {
+		setup(func(exec *opentofuv1alpha1.TofuExecution) {
+			exec.Spec.Timeout = &metav1.Duration{Duration: 15 * time.Minute}
+		})
+		exec := reconcileExecution()
+		job := currentJob(exec)
+		Expect(job.Spec.ActiveDeadlineSeconds).To(Equal(ptr.To(int64(900))))
+		Expect(job.Spec.Template.Spec.TerminationGracePeriodSeconds).NotTo(BeNil())
+		Expect(job.Spec.Template.Spec.Containers[0].Command[2]).To(ContainSubstring("kill -INT"))
+
+		job.Status.Failed = 1
+		job.Status.Conditions = []batchv1.JobCondition{{
+			Type:   batchv1.JobFailed,
+			Status: corev1.ConditionTrue,
+			Reason: batchv1.JobReasonDeadlineExceeded,
+		}}
+		Expect(fakeClient.Status().Update(ctx, job)).To(Succeed())
+
+		exec = reconcileExecution()
+		Expect(exec.Status.Phase).To(Equal("TimedOut"))
+		Expect(exec.Status.Summary).To(Equal("Apply timed out after 15m0s"))
+	})
+
+	It("suspends the Job on cancellation and reports Cancelled once the engine stopped", func() {
+		setup(func(*opentofuv1alpha1.TofuExecution) {})
+		exec := reconcileExecution()
+		job := currentJob(exec)
+		job.Status.Active = 1
+		Expect(fakeClient.Status().Update(ctx, job)).To(Succeed())
+
+		exec.Annotations = map[string]string{opentofuv1alpha1.CancelAnnotation: "true"}
+		Expect(fakeClient.Update(ctx, exec)).To(Succeed())
+
+		exec = reconcileExecution()
+		Expect(exec.Status.Phase).To(Equal("Running"))
+		job = currentJob(exec)
+		Expect(job.Spec.Suspend).To(Equal(ptr.To(true)))
+
+		job.Status.Active = 0
+		job.Status.Failed = 1
+		Expect(fakeClient.Status().Update(ctx, job)).To(Succeed())
+		exec = reconcileExecution()
+		Expect(exec.Status.Phase).To(Equal("Cancelled"))
+		Expect(exec.Status.Summary).To(Equal("Apply cancelled"))
+
+		var lease coordinationv1.Lease
+		Expect(errors.IsNotFound(fakeClient.Get(ctx, moduleLockKey(exec), &lease))).To(BeTrue())
+	})
+
+	It("classifies failures from the exit code of the engine container", func() {
+		setup(func(*opentofuv1alpha1.TofuExecution) {})
+		exec := reconcileExecution()
+		job := currentJob(exec)
+		Expect(job.Spec.Template.Spec.Containers[0].Command[2]).To(ContainSubstring("exit 13"))
+
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      job.Name + "-pod",
+				Namespace: "default",
+				Labels:    map[string]string{batchv1.JobNameLabel: job.Name},
+			},
+		}
+		Expect(fakeClient.Create(ctx, pod)).To(Succeed())
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  engineContainerName,
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCodeLockContention}},
+		}}
+		Expect(fakeClient.Status().Update(ctx, pod)).To(Succeed())
+
+		failedAt := metav1.NewTime(time.Now().Truncate(time.Second))
+		job.Status.Failed = 1
+		job.Status.Conditions = []batchv1.JobCondition{{
+			Type:               batchv1.JobFailed,
+			Status:             corev1.ConditionTrue,
+			Reason:             batchv1.JobReasonBackoffLimitExceeded,
+			LastTransitionTime: failedAt,
+		}}
+		Expect(fakeClient.Status().Update(ctx, job)).To(Succeed())
+
+		exec = reconcileExecution()
+		Expect(exec.Status.Phase).To(Equal("Failed"))
+		Expect(exec.Status.FailureReason).To(Equal(opentofuv1alpha1.FailureLockContention))
+		Expect(exec.Status.FinishedAt.Equal(&failedAt)).To(BeTrue())
+	})
+
+	It("applies pod customizations and the strategic merge patch from the job template", func() {
+		setup(func(exec *opentofuv1alpha1.TofuExecution) {
+			exec.Spec.JobTemplate = opentofuv1alpha1.JobTemplateSpec{
+				NodeSelector: map[string]string{"pool": "infra"},
+				Tolerations:  []corev1.Toleration{{Key: "dedicated", Value: "infra", Effect: corev1.TaintEffectNoSchedule}},
+				Resources: &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+				PriorityClassName:       "infra-high",
+				TTLSecondsAfterFinished: ptr.To(int32(3600)),
+				BackoffLimit:            ptr.To(int32(0)),
+				PodSpecPatch: &apiextv1.JSON{Raw: []byte(`{
+					"containers": [{"name": "engine", "workingDir": "/work"}, {"name": "proxy", "image": "envoy"}],
+					"hostAliases": [{"ip": "10.0.0.1", "hostnames": ["registry.internal"]}]
+				}`)},
+			}
+		})
+		exec := reconcileExecution()
+		Expect(exec.Status.Image).To(Equal("tofuutils/tenv:latest"))
+		job := currentJob(exec)
+		Expect(job.Spec.TTLSecondsAfterFinished).To(Equal(ptr.To(int32(3600))))
+		Expect(job.Spec.BackoffLimit).To(Equal(ptr.To(int32(0))))
+
+		pod := job.Spec.Template.Spec
+		Expect(pod.NodeSelector).To(HaveKeyWithValue("pool", "infra"))
+		Expect(pod.Tolerations).To(HaveLen(1))
+		Expect(pod.PriorityClassName).To(Equal("infra-high"))
+		Expect(pod.HostAliases).To(HaveLen(1))
+		Expect(pod.Containers).To(HaveLen(2))
+
+		engine := pod.Containers[0]
+		Expect(engine.Name).To(Equal(engineContainerName))
+		Expect(engine.WorkingDir).To(Equal("/work"))
+		Expect(engine.Command).NotTo(BeEmpty())
+		Expect(engine.Command[len(engine.Command)-1]).To(ContainSubstring(`tenv tofu install "$TOFUENV_TOFU_VERSION" || exit 10;`))
+		Expect(engine.Env).To(ContainElement(corev1.EnvVar{Name: "TOFUENV_TOFU_VERSION", Value: "latest-allowed"}))
+		Expect(engine.Resources.Limits.Memory().String()).To(Equal("1Gi"))
+	})
+
+	It("hardens execution pods by default", func() {
+		setup(func(exec *opentofuv1alpha1.TofuExecution) {
+			exec.Spec.JobTemplate.SecurityContext = &corev1.PodSecurityContext{FSGroup: ptr.To(int64(2000))}
+		})
+		pod := currentJob(reconcileExecution()).Spec.Template.Spec
+		Expect(pod.AutomountServiceAccountToken).To(Equal(ptr.To(false)))
+		Expect(pod.SecurityContext.RunAsNonRoot).To(Equal(ptr.To(true)))
+		Expect(pod.SecurityContext.FSGroup).To(Equal(ptr.To(int64(2000))))
+		Expect(pod.SecurityContext.SeccompProfile.Type).To(Equal(corev1.SeccompProfileTypeRuntimeDefault))
+
+		engine := pod.Containers[0]
+		Expect(engine.SecurityContext.ReadOnlyRootFilesystem).To(Equal(ptr.To(true)))
+		Expect(engine.SecurityContext.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")))
+		Expect(engine.WorkingDir).To(Equal(workspaceMountPath))
+		Expect(podSecurityViolations(&pod)).To(BeEmpty())
+	})
+
+	It("rejects weakened pods in namespaces enforcing pod security", func() {
+		setup(func(exec *opentofuv1alpha1.TofuExecution) {
+			exec.Spec.JobTemplate.PodSpecPatch = &apiextv1.JSON{Raw: []byte(
+				`{"containers": [{"name": "engine", "securityContext": {"privileged": true}}]}`)}
+		})
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "default",
+			Labels: map[string]string{enforcePodSecurityLabel: "true"},
+		}}
+		Expect(fakeClient.Create(ctx, namespace)).To(Succeed())
+
+		exec := reconcileExecution()
+		Expect(exec.Status.Phase).To(Equal("Failed"))
+		Expect(exec.Status.FailureReason).To(Equal(opentofuv1alpha1.FailureRejected))
+		Expect(exec.Status.Summary).To(ContainSubstring("must not be privileged"))
+
+		var jobs batchv1.JobList
+		Expect(fakeClient.List(ctx, &jobs)).To(Succeed())
+		Expect(jobs.Items).To(BeEmpty())
+	})
+
+	It("runs executions as the service account their namespace mandates", func() {
+		setup(func(*opentofuv1alpha1.TofuExecution) {})
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "default",
+			Annotations: map[string]string{executionServiceAccountAnnotation: "tenant-a"},
+		}}
+		Expect(fakeClient.Create(ctx, namespace)).To(Succeed())
+
+		pod := currentJob(reconcileExecution()).Spec.Template.Spec
+		Expect(pod.ServiceAccountName).To(Equal("tenant-a"))
+	})
+
+	It("rejects executions picking another service account than the mandatory one", func() {
+		setup(func(exec *opentofuv1alpha1.TofuExecution) {
+			exec.Spec.JobTemplate.PodSpecPatch = &apiextv1.JSON{Raw: []byte(`{"serviceAccountName": "admin"}`)}
+		})
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "default",
+			Annotations: map[string]string{executionServiceAccountAnnotation: "tenant-a"},
+		}}
+		Expect(fakeClient.Create(ctx, namespace)).To(Succeed())
+
+		exec := reconcileExecution()
+		Expect(exec.Status.FailureReason).To(Equal(opentofuv1alpha1.FailureRejected))
+		Expect(exec.Status.Summary).To(ContainSubstring("must run as service account tenant-a"))
+	})
+
+	terminatePod := func(job *batchv1.Job, exitCode int32, message string) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      job.Name + "-pod",
+				Namespace: "default",
+				Labels:    map[string]string{batchv1.JobNameLabel: job.Name},
+			},
+		}
+		Expect(fakeClient.Create(ctx, pod)).To(Succeed())
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name: engineContainerName,
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: exitCode,
+				Message:  message,
+			}},
+		}}
+		Expect(fakeClient.Status().Update(ctx, pod)).To(Succeed())
+	}
+
+	It("records the dependency lock file reported by the engine container", func() {
+		setup(func(exec *opentofuv1alpha1.TofuExecution) {
+			exec.Spec.LockfileReadonly = true
+		})
+		exec := reconcileExecution()
+		job := currentJob(exec)
+		script := job.Spec.Template.Spec.Containers[0].Command[2]
+		Expect(script).To(ContainSubstring("init -lockfile=readonly || exit 10"))
+		Expect(script).To(ContainSubstring("/dev/termination-log"))
+
+		terminatePod(job, 0, "lockfile 0a1b\n"+
+			"provider registry.opentofu.org/hashicorp/random 3.6.0 h1:abc=,zh:0123\n"+
+			"provider registry.opentofu.org/hashicorp/null 3.2.2\n")
+		job.Status.Succeeded = 1
+		Expect(fakeClient.Status().Update(ctx, job)).To(Succeed())
+
+		exec = reconcileExecution()
+		Expect(exec.Status.Phase).To(Equal("Succeeded"))
+		Expect(exec.Status.Lockfile).To(Equal(&opentofuv1alpha1.LockfileStatus{
+			Digest: "0a1b",
+			Providers: []opentofuv1alpha1.ProviderLock{
+				{Address: "registry.opentofu.org/hashicorp/random", Version: "3.6.0", Hashes: []string{"h1:abc=", "zh:0123"}},
+				{Address: "registry.opentofu.org/hashicorp/null", Version: "3.2.2"},
+			},
+		}))
+	})
+
+	It("records whether plans have changes and tells the story in events", func() {
+		setup(func(exec *opentofuv1alpha1.TofuExecution) {
+			exec.Spec.Action = "plan"
+		})
+		exec := reconcileExecution()
+		job := currentJob(exec)
+		Expect(job.Spec.Template.Spec.Containers[0].Command[2]).To(ContainSubstring("tofu plan -out=/tmp/soyplane.tfplan -detailed-exitcode"))
+		Expect(recorder.Events).To(Receive(Equal("Normal JobCreated Created Job " + job.Name)))
+
+		job.Status.Active = 1
+		Expect(fakeClient.Status().Update(ctx, job)).To(Succeed())
+		reconcileExecution()
+		Expect(recorder.Events).To(Receive(Equal("Normal Started Job " + job.Name + " started")))
+
+		added := testutil.ToFloat64(plannedResourceChanges.WithLabelValues("add"))
+		destroyed := testutil.ToFloat64(plannedResourceChanges.WithLabelValues("destroy"))
+		terminatePod(job, 0, "lockfile 0a1b\nchanges true\nresources 2 1 0\n")
+		job.Status.Active = 0
+		job.Status.Succeeded = 1
+		Expect(fakeClient.Status().Update(ctx, job)).To(Succeed())
+		exec = reconcileExecution()
+		Expect(exec.Status.HasChanges).To(Equal(ptr.To(true)))
+		Expect(testutil.ToFloat64(plannedResourceChanges.WithLabelValues("add"))).To(Equal(added + 2))
+		Expect(testutil.ToFloat64(plannedResourceChanges.WithLabelValues("destroy"))).To(Equal(destroyed))
+		Expect(recorder.Events).To(Receive(Equal("Normal PlanSucceeded Plan succeeded")))
+		Expect(recorder.Events).To(Receive(Equal("Normal PlanHasChanges Plan has changes to apply: 2 to add, 1 to change, 0 to destroy")))
+
+		reconcileExecution()
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("comments plan results on the pull request the module tracks", func() {
+		var posted []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("Authorization")).To(Equal("Bearer ghp_secret"))
+			Expect(r.URL.Path).To(Equal("/repos/org/infra/issues/42/comments"))
+			if r.Method == http.MethodPost {
+				var comment struct{ Body string }
+				Expect(json.NewDecoder(r.Body).Decode(&comment)).To(Succeed())
+				posted = append(posted, comment.Body)
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+			_, _ = w.Write([]byte("[]"))
+		}))
+		defer server.Close()
+
+		setup(func(exec *opentofuv1alpha1.TofuExecution) {
+			exec.Spec.Action = "plan"
+		})
+		module := &opentofuv1alpha1.TofuModule{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "network", Namespace: "default"}, module)).To(Succeed())
+		module.Spec.Source = "https://github.com/org/infra.git"
+		module.Spec.Version = "refs/pull/42/head"
+		module.Spec.PullRequestComment = &opentofuv1alpha1.PullRequestCommentSpec{
+			Provider:       opentofuv1alpha1.GitProviderGitHub,
+			TokenSecretRef: opentofuv1alpha1.KeyRef{Name: "github", Key: "token"},
+			APIURL:         server.URL,
+		}
+		Expect(fakeClient.Update(ctx, module)).To(Succeed())
+		Expect(fakeClient.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "github", Namespace: "default"},
+			Data:       map[string][]byte{"token": []byte("ghp_secret\n")},
+		})).To(Succeed())
+
+		exec := reconcileExecution()
+		job := currentJob(exec)
+		Expect(job.Spec.Template.Spec.Containers[0].Command[2]).To(ContainSubstring(checkoutScript()))
+		Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: moduleRefEnvVar, Value: "refs/pull/42/head"}))
+
+		terminatePod(job, 0, "changes true\nresources 2 1 0\n")
+		job.Status.Succeeded = 1
+		Expect(fakeClient.Status().Update(ctx, job)).To(Succeed())
+		reconcileExecution()
+
+		Expect(posted).To(HaveLen(1))
+		Expect(posted[0]).To(HavePrefix("<!-- soyplane:default/network -->\n#### Soyplane plan for `default/network`"))
+		Expect(posted[0]).To(ContainSubstring("**Plan: 2 to add, 1 to change, 0 to destroy.**"))
+		Expect(posted[0]).To(ContainSubstring("Ref `refs/pull/42/head`, execution `run` (tofu)."))
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		Expect(events).To(ContainElement("Normal PullRequestCommented Commented the plan on pull request #42"))
+	})
+
+	It("records failed applies as warnings", func() {
+		setup(func(*opentofuv1alpha1.TofuExecution) {})
+		exec := reconcileExecution()
+		job := currentJob(exec)
+		Expect(recorder.Events).To(Receive())
+
+		terminatePod(job, exitCodeApplyError, "")
+		job.Status.Failed = 1
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+		Expect(fakeClient.Status().Update(ctx, job)).To(Succeed())
+		reconcileExecution()
+		Expect(recorder.Events).To(Receive(Equal("Warning ApplyFailed Apply failed: ApplyError")))
+	})
+
+	It("waits for the plan and fails applies resolving a different lock file", func() {
+		setup(func(exec *opentofuv1alpha1.TofuExecution) {
+			exec.Spec.PlanRef = "run-plan"
+		})
+		plan := &opentofuv1alpha1.TofuExecution{
+			ObjectMeta: metav1.ObjectMeta{Name: "run-plan", Namespace: "default", UID: "plan-uid"},
+			Spec: opentofuv1alpha1.TofuExecutionSpec{
+				Action:    "plan",
+				ModuleRef: opentofuv1alpha1.ObjectRef{Name: "network", Namespace: "default"},
+			},
+		}
+		Expect(fakeClient.Create(ctx, plan)).To(Succeed())
+		plan.Status.Phase = "Running"
+		Expect(fakeClient.Status().Update(ctx, plan)).To(Succeed())
+
+		exec := reconcileExecution()
+		Expect(exec.Status.Phase).To(Equal("Queued"))
+		Expect(exec.Status.Summary).To(Equal("Waiting for plan run-plan to finish"))
+
+		plan.Status.Phase = "Succeeded"
+		plan.Status.Lockfile = &opentofuv1alpha1.LockfileStatus{Digest: "0a1b"}
+		Expect(fakeClient.Status().Update(ctx, plan)).To(Succeed())
+
+		exec = reconcileExecution()
+		job := currentJob(exec)
+		Expect(job.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: planLockfileEnvVar, Value: "0a1b"}))
+
+		terminatePod(job, exitCodeLockfileMismatch, "lockfile 2c3d\n")
+		job.Status.Failed = 1
+		Expect(fakeClient.Status().Update(ctx, job)).To(Succeed())
+
+		exec = reconcileExecution()
+		Expect(exec.Status.Phase).To(Equal("Failed"))
+		Expect(exec.Status.FailureReason).To(Equal(opentofuv1alpha1.FailureLockfileMismatch))
+		condition := meta.FindStatusCondition(exec.Status.Conditions, opentofuv1alpha1.ConditionLockfileMatched)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("2c3d differs from 0a1b used by plan run-plan"))
+	})
+
+	It("rejects applies referencing a missing plan", func() {
+		setup(func(exec *opentofuv1alpha1.TofuExecution) {
+			exec.Spec.PlanRef = "missing"
+		})
+		exec := reconcileExecution()
+		Expect(exec.Status.Phase).To(Equal("Failed"))
+		Expect(exec.Status.FailureReason).To(Equal(opentofuv1alpha1.FailureRejected))
+		Expect(exec.Status.Summary).To(ContainSubstring("plan missing not found"))
+	})
+
+	It("checks plans against the policies selecting the module and blocks denied applies", func() {
+		setup(func(*opentofuv1alpha1.TofuExecution) {})
+		Expect(fakeClient.Create(ctx, &opentofuv1alpha1.TofuPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "guardrails", Namespace: "default"},
+			Spec: opentofuv1alpha1.TofuPolicySpec{
+				Rules: []opentofuv1alpha1.PolicyRule{
+					{Name: "no-deletes", Expression: "true", Message: "Deletes need review"},
+					{Name: "tagged", Expression: "true"},
+				},
+			},
+		})).To(Succeed())
+		Expect(fakeClient.Create(ctx, &opentofuv1alpha1.TofuPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "databases", Namespace: "default"},
+			Spec: opentofuv1alpha1.TofuPolicySpec{
+				ModuleSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "data"}},
+				Rules:          []opentofuv1alpha1.PolicyRule{{Name: "backups", Expression: "true"}},
+			},
+		})).To(Succeed())
+
+		exec := reconcileExecution()
+		job := currentJob(exec)
+		podSpec := job.Spec.Template.Spec
+		Expect(podSpec.InitContainers).To(HaveLen(1))
+		Expect(podSpec.InitContainers[0].Image).To(Equal("soyplane/agent:test"))
+		Expect(podSpec.InitContainers[0].SecurityContext).NotTo(BeNil())
+		Expect(podSpec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: agentVolumeName, MountPath: agentBinDir}))
+		script := podSpec.Containers[0].Command[2]
+		Expect(script).To(ContainSubstring("plan -input=false -out=/tmp/soyplane.tfplan"))
+		Expect(script).To(ContainSubstring("/soyplane/bin/agent policy-check /tmp/soyplane-plan.json || exit 15"))
+		Expect(script).To(ContainSubstring("apply -input=false /tmp/soyplane.tfplan"))
+
+		policies := jobPolicies(job)
+		Expect(policies).To(HaveLen(1))
+		Expect(policies[0].Name).To(Equal("guardrails"))
+		Expect(policies[0].Enforcement).To(Equal(opentofuv1alpha1.PolicyDeny))
+
+		terminatePod(job, exitCodePolicyDenied, "lockfile 0a1b\npolicies evaluated\npolicy guardrails no-deletes Fail\n")
+		job.Status.Failed = 1
+		Expect(fakeClient.Status().Update(ctx, job)).To(Succeed())
+
+		exec = reconcileExecution()
+		Expect(exec.Status.Phase).To(Equal("Failed"))
+		Expect(exec.Status.FailureReason).To(Equal(opentofuv1alpha1.FailurePolicyDenied))
+		Expect(exec.Status.Policies).To(Equal([]opentofuv1alpha1.PolicyResult{
+			{Policy: "guardrails", Rule: "no-deletes", Enforcement: opentofuv1alpha1.PolicyDeny, Outcome: opentofuv1alpha1.PolicyFail, Message: "Deletes need review"},
+			{Policy: "guardrails", Rule: "tagged", Enforcement: opentofuv1alpha1.PolicyDeny, Outcome: opentofuv1alpha1.PolicyPass},
+		}))
+	})
+
+	It("only reports violations of plans", func() {
+		setup(func(exec *opentofuv1alpha1.TofuExecution) {
+			exec.Spec.Action = "plan"
+		})
+		Expect(fakeClient.Create(ctx, &opentofuv1alpha1.TofuPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "guardrails", Namespace: "default"},
+			Spec: opentofuv1alpha1.TofuPolicySpec{
+				Rules: []opentofuv1alpha1.PolicyRule{{Name: "no-deletes", Expression: "true"}},
+			},
+		})).To(Succeed())
+
+		script := currentJob(reconcileExecution()).Spec.Template.Spec.Containers[0].Command[2]
+		Expect(script).To(ContainSubstring("policy-check /tmp/soyplane-plan.json || true"))
+		Expect(script).NotTo(ContainSubstring(" apply "))
+	})
+
+	DescribeTable("rejects job templates bypassing policies",
+		func(template opentofuv1alpha1.JobTemplateSpec, reason string) {
+			setup(func(exec *opentofuv1alpha1.TofuExecution) {
+				exec.Spec.JobTemplate = template
+			})
+			Expect(fakeClient.Create(ctx, &opentofuv1alpha1.TofuPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "guardrails", Namespace: "default"},
+				Spec: opentofuv1alpha1.TofuPolicySpec{
+					Rules: []opentofuv1alpha1.PolicyRule{{Name: "no-deletes", Expression: "true"}},
+				},
+			})).To(Succeed())
+
+			exec := reconcileExecution()
+			Expect(exec.Status.Phase).To(Equal("Failed"))
+			Expect(exec.Status.FailureReason).To(Equal(opentofuv1alpha1.FailureRejected))
+			Expect(exec.Status.Summary).To(ContainSubstring(reason))
+		},
+		Entry("overriding the policies",
+			opentofuv1alpha1.JobTemplateSpec{Env: []corev1.EnvVar{{Name: policiesEnvVar, Value: "[]"}}},
+			"jobTemplate.env must not set SOYPLANE_POLICIES"),
+		Entry("patching the policies",
+			opentofuv1alpha1.JobTemplateSpec{PodSpecPatch: &apiextv1.JSON{Raw: []byte(
+				`{"containers": [{"name": "engine", "env": [{"name": "SOYPLANE_POLICIES", "value": "[]"}]}]}`)}},
+			"podSpecPatch must not change the SOYPLANE_* variables"),
+		Entry("replacing the engine command",
+			opentofuv1alpha1.JobTemplateSpec{PodSpecPatch: &apiextv1.JSON{Raw: []byte(
+				`{"containers": [{"name": "engine", "command": ["sh", "-c", "tofu apply -auto-approve"]}]}`)}},
+			"podSpecPatch must not change the engine command"),
+		Entry("dropping the agent",
+			opentofuv1alpha1.JobTemplateSpec{PodSpecPatch: &apiextv1.JSON{Raw: []byte(
+				`{"initContainers": [{"name": "install-agent", "$patch": "delete"}]}`)}},
+			"podSpecPatch must not change the install-agent init container"),
+	)
+
+	It("rejects executions of modules in other namespaces without a grant", func() {
+		setup(func(exec *opentofuv1alpha1.TofuExecution) {
+			exec.Spec.ModuleRef.Namespace = "infra"
+		})
+		Expect(fakeClient.Create(ctx, &opentofuv1alpha1.TofuModule{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "infra"},
+			Spec:       opentofuv1alpha1.TofuModuleSpec{Source: "https://example.com/repo.git"},
+		})).To(Succeed())
+		// A grant for stacks does not cover executions created directly.
+		Expect(fakeClient.Create(ctx, &opentofuv1alpha1.TofuModuleGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: "default-stacks", Namespace: "infra"},
+			Spec: opentofuv1alpha1.TofuModuleGrantSpec{
+				From: []opentofuv1alpha1.TofuModuleGrantFrom{{Kind: "TofuStack", Namespace: "default"}},
+			},
+		})).To(Succeed())
+
+		exec := reconcileExecution()
+		Expect(exec.Status.Phase).To(Equal("Failed"))
+		Expect(exec.Status.FailureReason).To(Equal(opentofuv1alpha1.FailureRejected))
+		Expect(exec.Status.Summary).To(ContainSubstring("no TofuModuleGrant in namespace infra permits references to module network"))
+		var leases coordinationv1.LeaseList
+		Expect(fakeClient.List(ctx, &leases)).To(Succeed())
+		Expect(leases.Items).To(BeEmpty())
+	})
+
+	It("runs executions of modules in other namespaces once granted", func() {
+		setup(func(exec *opentofuv1alpha1.TofuExecution) {
+			exec.Spec.ModuleRef.Namespace = "infra"
+		})
+		Expect(fakeClient.Create(ctx, &opentofuv1alpha1.TofuModule{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "infra"},
+			Spec:       opentofuv1alpha1.TofuModuleSpec{Source: "https://example.com/repo.git"},
+		})).To(Succeed())
+		Expect(fakeClient.Create(ctx, &opentofuv1alpha1.TofuModuleGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: "default-executions", Namespace: "infra"},
+			Spec: opentofuv1alpha1.TofuModuleGrantSpec{
+				From: []opentofuv1alpha1.TofuModuleGrantFrom{{Kind: "TofuExecution", Namespace: "default"}},
+				To:   []opentofuv1alpha1.TofuModuleGrantTo{{Name: "network"}},
+			},
+		})).To(Succeed())
+
+		exec := reconcileExecution()
+		Expect(exec.Status.FailureReason).To(BeEmpty())
+		Expect(exec.Status.JobName).NotTo(BeEmpty())
+	})
+
+	It("cancels without creating a Job when cancelled before starting", func() {
+		setup(func(exec *opentofuv1alpha1.TofuExecution) {
+			exec.Spec.Cancel = true
+		})
+		exec := reconcileExecution()
+		Expect(exec.Status.Phase).To(Equal("Cancelled"))
+		Expect(exec.Status.JobName).To(BeEmpty())
+
+		var jobs batchv1.JobList
+		Expect(fakeClient.List(ctx, &jobs)).To(Succeed())
+		Expect(jobs.Items).To(BeEmpty())
+	})
+})
+
+var _ = Describe("TofuExecution queue", func() {
+	newExecution := func(name, namespace, action string, created time.Time) *opentofuv1alpha1.TofuExecution {
+		return &opentofuv1alpha1.TofuExecution{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, CreationTimestamp: metav1.NewTime(created)},
+			Spec:       opentofuv1alpha1.TofuExecutionSpec{Action: action},
+		}
+	}
+	running := func(exec *opentofuv1alpha1.TofuExecution) *opentofuv1alpha1.TofuExecution {
+		exec.Status.JobName = exec.Name + "-job"
+		exec.Status.Phase = "Running"
+		return exec
+	}
+	now := time.Now()
+
+	It("orders queued executions by priority, then age", func() {
+		older := newExecution("a", "default", "plan", now.Add(-time.Minute))
+		newer := newExecution("b", "default", "plan", now)
+		apply := newExecution("c", "default", "apply", now)
+		urgent := newExecution("d", "default", "plan", now)
+		urgent.Spec.Priority = ptr.To(int32(500))
+		queue := []*opentofuv1alpha1.TofuExecution{newer, older, apply, urgent}
+		slices.SortStableFunc(queue, compareQueuedExecutions)
+		Expect(queue).To(Equal([]*opentofuv1alpha1.TofuExecution{urgent, apply, older, newer}))
+	})
+
+	It("admits the highest priority waiter within the global limit", func() {
+		cfg := settings.ExecutionSettings{MaxConcurrent: 2}
+		plan := newExecution("plan", "default", "plan", now.Add(-time.Minute))
+		apply := newExecution("apply", "default", "apply", now)
+		busy := running(newExecution("busy", "other", "plan", now))
+
+		admitted, reason := admitFromQueue(cfg, plan, []*opentofuv1alpha1.TofuExecution{apply, busy})
+		Expect(admitted).To(BeFalse())
+		Expect(reason).To(ContainSubstring("1 running, limit 2"))
+
+		admitted, _ = admitFromQueue(cfg, apply, []*opentofuv1alpha1.TofuExecution{plan, busy})
+		Expect(admitted).To(BeTrue())
+
+		admitted, _ = admitFromQueue(cfg, plan, []*opentofuv1alpha1.TofuExecution{busy})
+		Expect(admitted).To(BeTrue())
+	})
+
+	It("applies per-namespace limits independently", func() {
+		cfg := settings.ExecutionSettings{
+			MaxConcurrentPerNamespace: 1,
+			NamespaceMaxConcurrent:    map[string]int{"batch": 3},
+		}
+		busy := running(newExecution("busy", "default", "plan", now))
+
+		admitted, reason := admitFromQueue(cfg, newExecution("next", "default", "plan", now), []*opentofuv1alpha1.TofuExecution{busy})
+		Expect(admitted).To(BeFalse())
+		Expect(reason).To(ContainSubstring("namespace execution capacity"))
+
+		admitted, _ = admitFromQueue(cfg, newExecution("other", "team-a", "plan", now), []*opentofuv1alpha1.TofuExecution{busy})
+		Expect(admitted).To(BeTrue())
+
+		batch := []*opentofuv1alpha1.TofuExecution{
+			running(newExecution("b1", "batch", "plan", now)),
+			running(newExecution("b2", "batch", "plan", now)),
+		}
+		admitted, _ = admitFromQueue(cfg, newExecution("b3", "batch", "plan", now), batch)
+		Expect(admitted).To(BeTrue())
+	})
+})
+
+var _ = Describe("Engine image resolution", func() {
+	cfg := settings.ExecutionSettings{
+		DefaultImage: "tofuutils/tenv:latest",
+		EngineImages: []settings.EngineImageRule{
+			{Engine: "tofu", Versions: ">= 1.8", Image: "mirror.internal/opentofu:{{ .Version }}"},
+			{Engine: "terraform", Versions: "= 1.5.7", Image: "mirror.internal/terraform@sha256:abc"},
+		},
+	}
+
+	DescribeTable("resolves images",
+		func(engine, requested, expected string, expectedInstall bool) {
+			image, install, err := resolveEngineImage(cfg, engine, requested)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(image).To(Equal(expected))
+			Expect(install).To(Equal(expectedInstall))
+		},
+		Entry("configured mirror", "tofu", "v1.8.2", "mirror.internal/opentofu:1.8.2", false),
+		Entry("default image outside the constraint", "tofu", "1.6.2", "tofuutils/tenv:latest", true),
+		Entry("pinned digest", "terraform", "1.5.7", "mirror.internal/terraform@sha256:abc", false),
+		Entry("latest runs on the default image", "tofu", "latest", "tofuutils/tenv:latest", true),
+		Entry("constraints run on the default image", "tofu", "~> 1.6", "tofuutils/tenv:latest", true),
+		Entry("empty version", "terraform", "", "tofuutils/tenv:latest", true),
+	)
+
+	It("does not install the engine when a rule renders the default image", func() {
+		rules := settings.ExecutionSettings{
+			DefaultImage: "tofuutils/tenv:latest",
+			EngineImages: []settings.EngineImageRule{{Engine: "tofu", Image: "tofuutils/tenv:latest"}},
+		}
+		image, install, err := resolveEngineImage(rules, "tofu", "1.8.2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(image).To(Equal("tofuutils/tenv:latest"))
+		Expect(install).To(BeFalse())
+	})
+
+	It("configures tenv to install the requested version", func() {
+		install := settings.EngineInstallSettings{CacheDir: "/cache/tenv", TerraformMirror: "https://mirror.internal/terraform"}
+		Expect(engineInstallEnv(install, "terraform", "~> 1.5")).To(ConsistOf(
+			corev1.EnvVar{Name: "TFENV_TERRAFORM_VERSION", Value: "~> 1.5"},
+			corev1.EnvVar{Name: "TENV_AUTO_INSTALL", Value: "true"},
+			corev1.EnvVar{Name: "TENV_ROOT", Value: "/cache/tenv"},
+			corev1.EnvVar{Name: "TFENV_REMOTE", Value: "https://mirror.internal/terraform"},
+		))
+		Expect(engineInstallEnv(install, "tofu", "latest")).To(ConsistOf(
+			corev1.EnvVar{Name: "TOFUENV_TOFU_VERSION", Value: "latest-allowed"},
+			corev1.EnvVar{Name: "TENV_AUTO_INSTALL", Value: "true"},
+			corev1.EnvVar{Name: "TENV_ROOT", Value: "/cache/tenv"},
+		))
+	})
+
+	It("rejects invalid versions", func() {
+		_, _, err := resolveEngineImage(cfg, "tofu", "not-a-version")
+		var rejected *rejectionError
+		Expect(goerrors.As(err, &rejected)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Plan changes", func() {
+	It("reads the report of the plan step", func() {
+		Expect(parsePlanChanges("lockfile 0a1b\nchanges false\npolicies evaluated\n")).To(Equal(ptr.To(false)))
+		Expect(parsePlanChanges("changes true\n")).To(Equal(ptr.To(true)))
+		Expect(parsePlanChanges("lockfile 0a1b\n")).To(BeNil())
+	})
+
+	It("reads the resource counts of plans with changes", func() {
+		Expect(parsePlannedResources("changes true\nresources 3 1 2\n")).To(Equal(&plannedResources{add: 3, change: 1, destroy: 2}))
+		Expect(parsePlannedResources("changes false\n")).To(BeNil())
+	})
+
+	It("treats the detailed exit code of plans with changes as success", func() {
+		script := engineScript("tofu", "plan")
+		Expect(script).To(ContainSubstring("tofu plan -out=/tmp/soyplane.tfplan -detailed-exitcode"))
+		Expect(script).To(ContainSubstring(`if [ $rc -eq 2 ]; then rc=0; echo "changes true" >> /dev/termination-log;`))
+		Expect(script).To(ContainSubstring(`tofu show -no-color /tmp/soyplane.tfplan | sed -n`))
+		Expect(engineScript("tofu", "apply")).NotTo(ContainSubstring("-detailed-exitcode"))
+		Expect(policyEngineScript("tofu", "apply")).NotTo(ContainSubstring("-detailed-exitcode"))
+	})
+})
+
+var _ = Describe("Metrics", func() {
+	It("counts every object once under its current state", func() {
+		gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_objects"}, []string{"state"})
+		states := newStateGauge(gauge)
+		first := types.NamespacedName{Namespace: "default", Name: "first"}
+		second := types.NamespacedName{Namespace: "default", Name: "second"}
+
+		states.set(first, "Queued")
+		states.set(first, "Queued")
+		states.set(second, "Queued")
+		Expect(testutil.ToFloat64(gauge.WithLabelValues("Queued"))).To(Equal(2.0))
+
+		states.set(first, "Running")
+		states.set(second, "")
+		Expect(testutil.ToFloat64(gauge.WithLabelValues("Queued"))).To(Equal(0.0))
+		Expect(testutil.ToFloat64(gauge.WithLabelValues("Running"))).To(Equal(1.0))
+	})
+
+	It("observes the duration of finished executions", func() {
+		started := metav1.NewTime(time.Now().Add(-90 * time.Second))
+		finished := metav1.NewTime(started.Add(90 * time.Second))
+		exec := &opentofuv1alpha1.TofuExecution{
+			Spec: opentofuv1alpha1.TofuExecutionSpec{Action: "apply"},
+			Status: opentofuv1alpha1.TofuExecutionStatus{
+				Phase:            "Failed",
+				ExecutionSummary: opentofuv1alpha1.ExecutionSummary{StartedAt: &started, FinishedAt: &finished},
+			},
+		}
+		observeExecutionOutcome(exec, executionEngine(exec), nil)
+
+		var metric dto.Metric
+		Expect(executionDuration.WithLabelValues("apply", "tofu", "Failed").(prometheus.Metric).Write(&metric)).To(Succeed())
+		Expect(metric.GetHistogram().GetSampleSum()).To(BeNumerically(">=", 90))
+	})
+})
+
+var _ = Describe("Execution tracing", func() {
+	It("reports when each phase of the engine script started", func() {
+		script := policyEngineScript("tofu", "apply")
+		Expect(script).To(ContainSubstring(`echo "phase policy-check $(date +%s)" >> /tmp/soyplane.phases;`))
+		Expect(phaseReportScript()).To(Equal(`cat /tmp/soyplane.phases >> /dev/termination-log 2>/dev/null;`))
+		Expect(exitReportTrap()).To(ContainSubstring(phaseReportScript() + "\n\t" + lockfileReportScript()))
+		Expect(exitReportTrap()).To(HaveSuffix("trap soyplane_report EXIT;"))
+
+		Expect(parseExecutionPhases("lockfile 0a1b\nphase clone 1700000000\nphase init 1700000004\nchanges true\nphase plan 1700000060\n")).To(Equal([]executionPhase{
+			{name: "clone", start: time.Unix(1700000000, 0)},
+			{name: "init", start: time.Unix(1700000004, 0)},
+			{name: "plan", start: time.Unix(1700000060, 0)},
+		}))
+	})
+
+	It("records a span per phase under the execution span", func() {
+		recorder := tracetest.NewSpanRecorder()
+		previous := otel.GetTracerProvider()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder), sdktrace.WithSampler(sdktrace.AlwaysSample())))
+		DeferCleanup(otel.SetTracerProvider, previous)
+
+		created := metav1.NewTime(time.Unix(1700000000, 0))
+		finished := metav1.NewTime(time.Unix(1700000300, 0))
+		exec := &opentofuv1alpha1.TofuExecution{
+			ObjectMeta: metav1.ObjectMeta{Name: "run", Namespace: "default", UID: "7c9e6679-7425-40de-944b-e07fc1f90ae7", CreationTimestamp: created},
+			Spec:       opentofuv1alpha1.TofuExecutionSpec{Action: "plan", ModuleRef: opentofuv1alpha1.ObjectRef{Name: "network"}},
+			Status: opentofuv1alpha1.TofuExecutionStatus{
+				Phase:            "Failed",
+				ExecutionSummary: opentofuv1alpha1.ExecutionSummary{FinishedAt: &finished, Summary: "Plan failed: PlanError"},
+			},
+		}
+		traceExecution(context.Background(), exec, []executionPhase{
+			{name: "clone", start: time.Unix(1700000010, 0)},
+			{name: "plan", start: time.Unix(1700000100, 0)},
+		})
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(3))
+		Expect(spans[0].Name()).To(Equal("clone"))
+		Expect(spans[0].EndTime()).To(Equal(time.Unix(1700000100, 0)))
+		Expect(spans[1].Name()).To(Equal("plan"))
+		Expect(spans[1].EndTime()).To(Equal(finished.Time))
+		Expect(spans[2].Name()).To(Equal("TofuExecution plan"))
+		Expect(spans[2].StartTime()).To(Equal(created.Time))
+		Expect(spans[2].Status().Description).To(Equal("Plan failed: PlanError"))
+		Expect(spans[0].SpanContext().TraceID().String()).To(Equal("7c9e6679742540de944be07fc1f90ae7"))
+	})
+
+	It("continues sampled traces in execution pods", func() {
+		recorder := tracetest.NewSpanRecorder()
+		ctx, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "CreateJob")
+		defer span.End()
+		cfg := settings.TracingSettings{Enabled: true, Endpoint: "otel-collector:4317", Insecure: true}
+
+		env := tracingEnv(ctx, cfg)
+		Expect(env).To(ContainElements(
+			corev1.EnvVar{Name: "TRACEPARENT", Value: "00-" + span.SpanContext().TraceID().String() + "-" + span.SpanContext().SpanID().String() + "-01"},
+			corev1.EnvVar{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "http://otel-collector:4317"},
+		))
+		Expect(tracingEnv(ctx, settings.TracingSettings{})).To(BeEmpty())
+		Expect(tracingEnv(context.Background(), cfg)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Provider installation", func() {
+	It("leaves the engine defaults alone without settings", func() {
+		cfg := settings.ProviderInstallationSettings{}
+		Expect(providerInstallationConfig(cfg)).To(BeEmpty())
+		Expect(providerInstallationEnv(cfg)).To(BeEmpty())
+		Expect(providerInstallationScript(cfg)).To(BeEmpty())
+	})
+
+	It("points the engine at the network mirror and plugin cache", func() {
+		cfg := settings.ProviderInstallationSettings{
+			NetworkMirrorURL: "https://soyplane-provider-mirror.soyplane-system.svc:8444",
+			Direct:           true,
+			PluginCacheDir:   "/cache/plugins",
+		}
+		Expect(providerInstallationConfig(cfg)).To(Equal(`provider_installation {
+  network_mirror {
+    url = "https://soyplane-provider-mirror.soyplane-system.svc:8444/"
+  }
+  direct {}
+}
+`))
+		Expect(providerInstallationEnv(cfg)).To(ContainElements(
+			corev1.EnvVar{Name: "TF_CLI_CONFIG_FILE", Value: cliConfigPath},
+			corev1.EnvVar{Name: "TF_PLUGIN_CACHE_DIR", Value: "/cache/plugins"},
+		))
+		Expect(providerInstallationScript(cfg)).To(ContainSubstring(`> "$TF_CLI_CONFIG_FILE"`))
+	})
+})
+
+var _ = Describe("Execution identity", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *TofuExecutionReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(rbacv1.AddToScheme(scheme)).To(Succeed())
+		Expect(opentofuv1alpha1.AddToScheme(scheme)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).Build()
+		reconciler = &TofuExecutionReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(100)}
+	})
+
+	It("creates the mandatory service account with its workload identity annotations", func() {
+		cfg := settings.IdentitySettings{
+			CreateServiceAccount: true,
+			ServiceAccountAnnotations: []settings.AnnotationTemplate{{
+				Name:  "eks.amazonaws.com/role-arn",
+				Value: "arn:aws:iam::123456789012:role/{{ .Namespace }}-{{ .ServiceAccount }}",
+			}},
+		}
+		key := types.NamespacedName{Name: "tofu", Namespace: "team-a"}
+		Expect(reconciler.ensureServiceAccount(ctx, key, cfg)).To(Succeed())
+
+		var account corev1.ServiceAccount
+		Expect(fakeClient.Get(ctx, key, &account)).To(Succeed())
+		Expect(account.Labels).To(HaveKeyWithValue(managedByLabel, managedBy))
+		Expect(account.Annotations).To(HaveKeyWithValue("eks.amazonaws.com/role-arn", "arn:aws:iam::123456789012:role/team-a-tofu"))
+	})
+
+	It("leaves service accounts it did not create alone", func() {
+		key := types.NamespacedName{Name: "tofu", Namespace: "team-a"}
+		Expect(fakeClient.Create(ctx, &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}})).To(Succeed())
+		cfg := settings.IdentitySettings{
+			CreateServiceAccount:      true,
+			ServiceAccountAnnotations: []settings.AnnotationTemplate{{Name: "iam.gke.io/gcp-service-account", Value: "tofu@project.iam.gserviceaccount.com"}},
+		}
+		Expect(reconciler.ensureServiceAccount(ctx, key, cfg)).To(Succeed())
+
+		var account corev1.ServiceAccount
+		Expect(fakeClient.Get(ctx, key, &account)).To(Succeed())
+		Expect(account.Annotations).To(BeEmpty())
+	})
+
+	It("grants agents access to their execution, module and output targets only", func() {
+		execution := &opentofuv1alpha1.TofuExecution{ObjectMeta: metav1.ObjectMeta{Name: "run", Namespace: "team-a", UID: "run-uid"}}
+		module := &opentofuv1alpha1.TofuModule{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "team-a"},
+			Spec: opentofuv1alpha1.TofuModuleSpec{Outputs: []opentofuv1alpha1.OutputSpec{{
+				From: "vpc_id",
+				To: []opentofuv1alpha1.OutputTarget{
+					{Kind: "ConfigMap", Name: "network", Key: "vpc_id"},
+					{Kind: "Secret", Name: "network", Key: "vpc_id"},
+				},
+			}}},
+		}
+
+		roles := executionAccessRoles(execution, module)
+		Expect(roles).To(HaveLen(1))
+		Expect(roles[0].Namespace).To(Equal("team-a"))
+		Expect(roles[0].Rules).To(ConsistOf(
+			rbacv1.PolicyRule{APIGroups: []string{"opentofu.soyplane.io"}, Resources: []string{"tofuexecutions"}, ResourceNames: []string{"run"}, Verbs: []string{"get"}},
+			rbacv1.PolicyRule{APIGroups: []string{"opentofu.soyplane.io"}, Resources: []string{"tofumodules"}, ResourceNames: []string{"network"}, Verbs: []string{"get"}},
+			rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{"network"}, Verbs: []string{"get", "update", "patch"}},
+			rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"network"}, Verbs: []string{"get", "update", "patch"}},
+		))
+
+		module.Namespace = "infra"
+		roles = executionAccessRoles(execution, module)
+		Expect(roles).To(HaveLen(2))
+		Expect(roles[1].Namespace).To(Equal("infra"))
+		Expect(roles[1].Rules).To(ConsistOf(
+			rbacv1.PolicyRule{APIGroups: []string{"opentofu.soyplane.io"}, Resources: []string{"tofumodules"}, ResourceNames: []string{"network"}, Verbs: []string{"get"}},
+		))
+	})
+
+	It("names grants after the execution UID", func() {
+		execution := &opentofuv1alpha1.TofuExecution{ObjectMeta: metav1.ObjectMeta{
+			Name:      strings.Repeat("long-execution-name-", 12),
+			Namespace: "team-a",
+			UID:       "6f1d2c3b-4a59-4e8f-9b7a-0c1d2e3f4a5b",
+		}}
+		module := &opentofuv1alpha1.TofuModule{ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "infra"}}
+		for _, role := range executionAccessRoles(execution, module) {
+			Expect(role.Name).To(Equal("soyplane-agent-6f1d2c3b-4a59-4e8f-9b7a-0c1d2e3f4a5b"))
+		}
+	})
+
+	It("updates grants left with outdated rules", func() {
+		execution := &opentofuv1alpha1.TofuExecution{ObjectMeta: metav1.ObjectMeta{Name: "run", Namespace: "team-a", UID: "run-uid"}}
+		Expect(fakeClient.Create(ctx, execution)).To(Succeed())
+		Expect(fakeClient.Create(ctx, &rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: "soyplane-agent-run-uid", Namespace: "team-a"},
+			Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"old"}, Verbs: []string{"get"}}},
+		})).To(Succeed())
+		module := &opentofuv1alpha1.TofuModule{ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "team-a"}}
+		roles := executionAccessRoles(execution, module)
+		Expect(reconciler.ensureExecutionRoles(ctx, execution, roles, "tofu")).To(Succeed())
+
+		var role rbacv1.Role
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "soyplane-agent-run-uid", Namespace: "team-a"}, &role)).To(Succeed())
+		Expect(role.Rules).To(Equal(roles[0].Rules))
+		var binding rbacv1.RoleBinding
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "soyplane-agent-run-uid", Namespace: "team-a"}, &binding)).To(Succeed())
+		Expect(binding.Subjects).To(ConsistOf(rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "tofu", Namespace: "team-a"}))
+	})
+
+	It("revokes grants in other namespaces when the execution is deleted mid-run", func() {
+		execution := &opentofuv1alpha1.TofuExecution{
+			ObjectMeta: metav1.ObjectMeta{Name: "run", Namespace: "team-a", UID: "run-uid"},
+			Spec:       opentofuv1alpha1.TofuExecutionSpec{ModuleRef: opentofuv1alpha1.ObjectRef{Name: "network", Namespace: "infra"}},
+		}
+		Expect(fakeClient.Create(ctx, execution)).To(Succeed())
+		module := &opentofuv1alpha1.TofuModule{ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "infra"}}
+		Expect(reconciler.ensureExecutionRoles(ctx, execution, executionAccessRoles(execution, module), "tofu")).To(Succeed())
+		Expect(execution.Finalizers).To(ConsistOf(executionAccessFinalizer))
+		key := types.NamespacedName{Name: "soyplane-agent-" + string(execution.UID), Namespace: "infra"}
+		Expect(fakeClient.Get(ctx, key, &rbacv1.Role{})).To(Succeed())
+
+		Expect(fakeClient.Delete(ctx, execution)).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(execution)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errors.IsNotFound(fakeClient.Get(ctx, key, &rbacv1.Role{}))).To(BeTrue())
+		Expect(errors.IsNotFound(fakeClient.Get(ctx, key, &rbacv1.RoleBinding{}))).To(BeTrue())
+		Expect(errors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(execution), execution))).To(BeTrue())
+	})
+
+	It("revokes grants once, when the execution finishes", func() {
+		execution := &opentofuv1alpha1.TofuExecution{ObjectMeta: metav1.ObjectMeta{Name: "run", Namespace: "team-a", UID: "run-uid"}}
+		Expect(fakeClient.Create(ctx, execution)).To(Succeed())
+		module := &opentofuv1alpha1.TofuModule{ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "team-a"}}
+		Expect(reconciler.ensureExecutionRoles(ctx, execution, executionAccessRoles(execution, module), "tofu")).To(Succeed())
+		Expect(execution.Finalizers).To(ConsistOf(executionAccessFinalizer))
+		key := types.NamespacedName{Name: "soyplane-agent-run-uid", Namespace: "team-a"}
+
+		Expect(reconciler.revokeExecutionAccess(ctx, execution)).To(Succeed())
+		Expect(errors.IsNotFound(fakeClient.Get(ctx, key, &rbacv1.Role{}))).To(BeTrue())
+		Expect(execution.Finalizers).To(BeEmpty())
+
+		// Without the finalizer there is nothing left to revoke, so nothing is deleted.
+		Expect(fakeClient.Create(ctx, &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}})).To(Succeed())
+		Expect(reconciler.revokeExecutionAccess(ctx, execution)).To(Succeed())
+		Expect(fakeClient.Get(ctx, key, &rbacv1.Role{})).To(Succeed())
+	})
+
+	It("creates missing output targets without touching existing ones", func() {
+		Expect(fakeClient.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "team-a"},
+			StringData: map[string]string{"vpc_id": "vpc-123"},
+		})).To(Succeed())
+		module := &opentofuv1alpha1.TofuModule{Spec: opentofuv1alpha1.TofuModuleSpec{Outputs: []opentofuv1alpha1.OutputSpec{{
+			From: "vpc_id",
+			To: []opentofuv1alpha1.OutputTarget{
+				{Kind: "ConfigMap", Name: "network", Key: "vpc_id"},
+				{Kind: "Secret", Name: "network", Key: "vpc_id"},
+			},
+		}}}}
+		Expect(reconciler.ensureOutputTargets(ctx, "team-a", module)).To(Succeed())
+
+		var configMap corev1.ConfigMap
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "network", Namespace: "team-a"}, &configMap)).To(Succeed())
+		Expect(configMap.Labels).To(HaveKeyWithValue(managedByLabel, managedBy))
+		var secret corev1.Secret
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "network", Namespace: "team-a"}, &secret)).To(Succeed())
+		Expect(secret.Labels).NotTo(HaveKey(managedByLabel))
+	})
+})
+
+var _ = Describe("Module policies", func() {
+	It("adds the policies of the shared policy namespace to those of the module's namespace", func() {
+		scheme := runtime.NewScheme()
+		Expect(opentofuv1alpha1.AddToScheme(scheme)).To(Succeed())
+		newPolicy := func(name, namespace string, selector map[string]string) *opentofuv1alpha1.TofuPolicy {
+			p := &opentofuv1alpha1.TofuPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec:       opentofuv1alpha1.TofuPolicySpec{Rules: []opentofuv1alpha1.PolicyRule{{Name: "no-deletes", Expression: "true"}}},
+			}
+			if selector != nil {
+				p.Spec.ModuleSelector = &metav1.LabelSelector{MatchLabels: selector}
+			}
+			return p
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newPolicy("tenant", "team-a", nil),
+			newPolicy("guardrails", "soyplane-policies", nil),
+			newPolicy("databases", "soyplane-policies", map[string]string{"tier": "data"}),
+			newPolicy("other-tenant", "team-b", nil),
+		).Build()
+		reconciler := &TofuExecutionReconciler{Client: fakeClient, Scheme: scheme}
+		module := &opentofuv1alpha1.TofuModule{ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "team-a"}}
+
+		names := func(policies []policy.Policy) []string {
+			var out []string
+			for _, p := range policies {
+				out = append(out, p.Name)
+			}
+			return out
+		}
+		policies, err := reconciler.modulePolicies(context.Background(), module, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(policies)).To(Equal([]string{"tenant"}))
+
+		policies, err = reconciler.modulePolicies(context.Background(), module, "soyplane-policies")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(policies)).To(Equal([]string{"soyplane-policies/guardrails", "tenant"}))
+
+		module.Labels = map[string]string{"tier": "data"}
+		policies, err = reconciler.modulePolicies(context.Background(), module, "soyplane-policies")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(policies)).To(Equal([]string{"soyplane-policies/databases", "soyplane-policies/guardrails", "tenant"}))
+	})
+})
+
+var _ = Describe("Pull request comments", func() {
+	module := &opentofuv1alpha1.TofuModule{ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "apps"}}
+
+	It("reports failures and policy results", func() {
+		exec := &opentofuv1alpha1.TofuExecution{
+			ObjectMeta: metav1.ObjectMeta{Name: "network-plan"},
+			Spec:       opentofuv1alpha1.TofuExecutionSpec{Engine: opentofuv1alpha1.EngineSpec{Name: "terraform"}},
+			Status: opentofuv1alpha1.TofuExecutionStatus{
+				ExecutionSummary: opentofuv1alpha1.ExecutionSummary{Summary: "Plan failed: Init"},
+				Phase:            "Failed",
+				Policies: []opentofuv1alpha1.PolicyResult{{
+					Policy: "guardrails", Rule: "no-deletes", Enforcement: opentofuv1alpha1.PolicyDeny,
+					Outcome: opentofuv1alpha1.PolicyFail, Message: "deletes a | b",
+				}},
+			},
+		}
+		Expect(pullRequestCommentBody(module, exec, "feature", nil)).To(Equal("#### Soyplane plan for `apps/network`\n\n" +
+			"**Plan failed: Init**\n\n" +
+			"Ref `feature`, execution `network-plan` (terraform).\n\n" +
+			"| Policy | Rule | Enforcement | Outcome | Message |\n|---|---|---|---|---|\n" +
+			"| guardrails | no-deletes | Deny | Fail | deletes a \\| b |\n"))
+	})
+
+	It("tells plans without changes apart", func() {
+		exec := &opentofuv1alpha1.TofuExecution{ObjectMeta: metav1.ObjectMeta{Name: "network-plan"}}
+		exec.Status.Phase = "Succeeded"
+		exec.Status.HasChanges = ptr.To(false)
+		Expect(pullRequestCommentBody(module, exec, "feature", nil)).To(ContainSubstring("**No changes.**"))
+		exec.Status.HasChanges = ptr.To(true)
+		Expect(pullRequestCommentBody(module, exec, "feature", nil)).To(ContainSubstring("**The plan has changes.**"))
+	})
+})
+
+var _ = Describe("Module checkout", func() {
+	var origin string
+	git := func(dir string, args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1",
+			"GIT_AUTHOR_NAME=soyplane", "GIT_AUTHOR_EMAIL=soyplane@example.com",
+			"GIT_COMMITTER_NAME=soyplane", "GIT_COMMITTER_EMAIL=soyplane@example.com")
+		out, err := cmd.CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(out))
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(message string) string {
+		git(origin, "commit", "-q", "--allow-empty", "-m", message)
+		return git(origin, "rev-parse", "HEAD")
+	}
+	checkout := func(version string) (string, error) {
+		clone := GinkgoT().TempDir()
+		git(clone, "clone", "-q", origin, ".")
+		cmd := exec.Command("sh", "-ec", checkoutScript())
+		cmd.Dir = clone
+		cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1", moduleRefEnvVar+"="+version)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			GinkgoWriter.Println(string(out))
+			return "", err
+		}
+		return git(clone, "rev-parse", "HEAD"), nil
+	}
+
+	BeforeEach(func() {
+		if _, err := exec.LookPath("git"); err != nil {
+			Skip("git is not installed")
+		}
+		origin = GinkgoT().TempDir()
+		git(origin, "init", "-q", "-b", "main")
+		commit("initial")
+	})
+
+	It("checks out branches", func() {
+		git(origin, "checkout", "-q", "-b", "feature")
+		feature := commit("feature")
+		git(origin, "checkout", "-q", "main")
+		commit("main")
+
+		Expect(checkout("feature")).To(Equal(feature))
+		Expect(checkout("refs/heads/feature")).To(Equal(feature))
+	})
+
+	It("checks out tags", func() {
+		tagged := commit("release")
+		git(origin, "tag", "v1.0.0")
+		commit("main")
+
+		Expect(checkout("v1.0.0")).To(Equal(tagged))
+	})
+
+	It("checks out commits", func() {
+		first := commit("first")
+		commit("main")
+
+		Expect(checkout(first)).To(Equal(first))
+		Expect(checkout(first[:7])).To(Equal(first))
+	})
+
+	It("fetches pull request refs, which clones do not carry", func() {
+		git(origin, "checkout", "-q", "--detach")
+		head := commit("pull request")
+		git(origin, "update-ref", "refs/pull/42/head", head)
+		git(origin, "checkout", "-q", "main")
+
+		Expect(checkout("refs/pull/42/head")).To(Equal(head))
+	})
+
+	It("fails with the init exit code for unknown versions", func() {
+		_, err := checkout("missing")
+		var exitErr *exec.ExitError
+		Expect(goerrors.As(err, &exitErr)).To(BeTrue())
+		Expect(exitErr.ExitCode()).To(Equal(exitCodeInitError))
+	})
+})
+
+var _ = Describe("Termination message", func() {
+	// run runs the engine script steps in a temporary directory holding lockfile, and returns the
+	// termination message they leave.
+	run := func(lockfile string, steps ...string) string {
+		dir := GinkgoT().TempDir()
+		terminationLog := filepath.Join(dir, "termination-log")
+		Expect(os.WriteFile(filepath.Join(dir, ".terraform.lock.hcl"), []byte(lockfile), 0o644)).To(Succeed())
+		script := strings.Join(append([]string{exitReportTrap()}, steps...), "\n")
+		script = strings.NewReplacer("/dev/termination-log", terminationLog, "/tmp/", dir+"/").Replace(script)
+		cmd := exec.Command("sh", "-ec", script)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(out))
+		message, err := os.ReadFile(terminationLog)
+		Expect(err).NotTo(HaveOccurred())
+		return string(message)
+	}
+	lockfile := func(providers, hashes int) string {
+		var b strings.Builder
+		for i := range providers {
+			fmt.Fprintf(&b, "provider \"registry.opentofu.org/hashicorp/p%02d\" {\n  version     = \"1.2.3\"\n  constraints = \"~> 1.2\"\n  hashes = [\n", i)
+			for h := range hashes {
+				fmt.Fprintf(&b, "    \"h1:%043d=\",\n", h)
+			}
+			b.WriteString("  ]\n}\n\n")
+		}
+		return b.String()
+	}
+	planned := []string{
+		phaseMark("plan"),
+		fmt.Sprintf(`echo "%s true" >> /dev/termination-log;`, planChangesReport),
+		fmt.Sprintf(`echo "%s 1 2 3" >> /dev/termination-log;`, plannedResourcesReport),
+		phaseMark("apply"),
+	}
+
+	BeforeEach(func() {
+		for _, tool := range []string{"awk", "sha256sum"} {
+			if _, err := exec.LookPath(tool); err != nil {
+				Skip(tool + " is not installed")
+			}
+		}
+	})
+
+	It("reports the lock file with its hashes when it fits", func() {
+		content := lockfile(2, 3)
+		message := run(content, append([]string{phaseMark("init"), lockfileScript()}, planned...)...)
+
+		report := parseLockfileSummary(message)
+		Expect(report).NotTo(BeNil())
+		Expect(report.Digest).To(Equal(fmt.Sprintf("%x", sha256.Sum256([]byte(content)))))
+		Expect(report.Providers).To(HaveLen(2))
+		Expect(report.Providers[1].Hashes).To(HaveLen(3))
+		Expect(parseExecutionPhases(message)).To(HaveLen(3))
+	})
+
+	It("keeps the plan and phase lines when the lock file report fills the message", func() {
+		message := run(lockfile(80, 10), append([]string{phaseMark("init"), lockfileScript()}, planned...)...)
+		Expect(len(message)).To(BeNumerically("<=", maxTerminationMessage))
+
+		Expect(parsePlanChanges(message)).To(Equal(ptr.To(true)))
+		Expect(parsePlannedResources(message)).To(Equal(&plannedResources{add: 1, change: 2, destroy: 3}))
+		Expect(parseExecutionPhases(message)).To(HaveLen(3))
+
+		report := parseLockfileSummary(message)
+		Expect(report).NotTo(BeNil())
+		Expect(report.Digest).To(HaveLen(64))
+		Expect(len(report.Providers)).To(BeNumerically(">", 0))
+		Expect(len(report.Providers)).To(BeNumerically("<", 80))
+		for _, provider := range report.Providers {
+			Expect(provider.Version).To(Equal("1.2.3"))
+			Expect(provider.Hashes).To(BeEmpty())
+		}
+	})
+})
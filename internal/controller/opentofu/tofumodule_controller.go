@@ -22,11 +22,15 @@ import (
 	"maps"
 	"sort"
 	"strconv"
+	"time"
 
 	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -35,12 +39,14 @@ import (
 // TofuModuleReconciler reconciles a TofuModule object
 type TofuModuleReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=opentofu.soyplane.io,resources=tofumodules,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=opentofu.soyplane.io,resources=tofumodules/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=opentofu.soyplane.io,resources=tofumodules/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -56,28 +62,38 @@ func (r *TofuModuleReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	var module opentofuv1alpha1.TofuModule
 
 	if err := r.Get(ctx, req.NamespacedName, &module); err != nil {
+		if errors.IsNotFound(err) {
+			observeModule(req.NamespacedName, nil, false)
+		}
 		log.Error(err, "Unable to fetch TofuModule")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	lastExecution, err := r.lastExecution(ctx, &module)
+	executions, err := r.ownedExecutions(ctx, &module)
 	if err != nil {
 		log.Error(err, "Unable to fetch last TofuExecution")
 		return ctrl.Result{}, err
 	}
 
+	if err := pruneExecutionHistory(ctx, r.Client, executions, module.Spec.HistoryLimits, module.Status.LastApply); err != nil {
+		log.Error(err, "Unable to prune TofuExecution history")
+		return ctrl.Result{}, err
+	}
+
 	desiredGeneration := strconv.FormatInt(module.GetGeneration(), 10)
 
-	if lastExecution == nil {
+	if len(executions) == 0 {
 		newExecution, err := r.createExecution(ctx, &module)
 		if err != nil {
 			log.Error(err, "Unable to create new TofuExecution")
 			return ctrl.Result{}, err
 		}
+		recordExecutionCreated(r.Recorder, &module, newExecution, "for generation "+desiredGeneration)
 
 		log.Info("TofuModule reconciled, Created TofuExecution", "execution", newExecution.Name)
 		return ctrl.Result{}, nil
 	}
+	lastExecution := executions[0]
 	currentGeneration := ""
 	if lastExecution.Annotations != nil {
 		currentGeneration = lastExecution.Annotations[moduleGenerationAnnotation]
@@ -88,11 +104,33 @@ func (r *TofuModuleReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			log.Error(err, "Unable to create new TofuExecution for updated module spec")
 			return ctrl.Result{}, err
 		}
+		recordExecutionCreated(r.Recorder, &module, newExecution, "for generation "+desiredGeneration)
 		log.Info("Module spec changed — triggered new TofuExecution", "execution", newExecution.Name, "generation", desiredGeneration)
 		return ctrl.Result{}, nil
 	}
 
-	if updated, err := r.updateModuleStatus(ctx, &module, lastExecution); err != nil {
+	if retry, delay := retryDelay(lastExecution, time.Now()); retry {
+		if delay == 0 {
+			attempt := newRetryExecution(lastExecution)
+			if err := r.Create(ctx, attempt); err != nil {
+				log.Error(err, "Unable to create retry TofuExecution")
+				return ctrl.Result{}, err
+			}
+			log.Info("Retrying failed TofuExecution", "failed", lastExecution.Name, "execution", attempt.Name,
+				"attempt", attempt.Annotations[attemptAnnotation], "reason", lastExecution.Status.FailureReason)
+			r.Recorder.Eventf(&module, corev1.EventTypeNormal, eventRetrying, "Retrying failed execution %s as %s (attempt %s)",
+				lastExecution.Name, attempt.Name, attempt.Annotations[attemptAnnotation])
+			return ctrl.Result{}, nil
+		}
+		if _, err := r.updateModuleStatus(ctx, &module, executions); err != nil {
+			log.Error(err, "Could not update TofuModule status")
+			return ctrl.Result{}, err
+		}
+		log.Info("Waiting before retrying failed TofuExecution", "execution", lastExecution.Name, "after", delay)
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
+	if updated, err := r.updateModuleStatus(ctx, &module, executions); err != nil {
 		log.Error(err, "Could not update TofuModule status")
 		return ctrl.Result{}, err
 	} else if updated {
@@ -103,9 +141,24 @@ func (r *TofuModuleReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	return ctrl.Result{}, nil
 }
 
-func (r *TofuModuleReconciler) updateModuleStatus(ctx context.Context, module *opentofuv1alpha1.TofuModule, execution *opentofuv1alpha1.TofuExecution) (bool, error) {
+// updateModuleStatus mirrors the newest execution into the module status, along with the most
+// recent finished plan and apply. executions must be sorted newest first.
+func (r *TofuModuleReconciler) updateModuleStatus(ctx context.Context, module *opentofuv1alpha1.TofuModule, executions []*opentofuv1alpha1.TofuExecution) (bool, error) {
 	log := logf.FromContext(ctx)
 	moduleChanged := false
+	execution := executions[0]
+
+	lastPlan := lastFinishedSummary(executions, "plan", module.Status.LastPlan)
+	planChanged := !equality.Semantic.DeepEqual(module.Status.LastPlan, lastPlan)
+	if planChanged {
+		module.Status.LastPlan = lastPlan
+		moduleChanged = true
+	}
+	lastApply := lastFinishedSummary(executions, "apply", module.Status.LastApply)
+	if !equality.Semantic.DeepEqual(module.Status.LastApply, lastApply) {
+		module.Status.LastApply = lastApply
+		moduleChanged = true
+	}
 
 	if module.Status.LastExecutionName != execution.Name {
 		module.Status.LastExecutionName = execution.Name
@@ -117,6 +170,7 @@ func (r *TofuModuleReconciler) updateModuleStatus(ctx context.Context, module *o
 		moduleChanged = true
 	}
 
+	drifted := detectedDrift(executions)
 	if moduleChanged {
 		module.Status.ObservedGeneration = module.GetGeneration()
 		if err := r.Status().Update(ctx, module); err != nil {
@@ -126,12 +180,26 @@ func (r *TofuModuleReconciler) updateModuleStatus(ctx context.Context, module *o
 			}
 			return false, err
 		}
+		if drifted != nil && planChanged {
+			r.Recorder.Eventf(module, corev1.EventTypeWarning, eventDriftDetected, "Drift check %s found changes to the infrastructure", drifted.Name)
+		}
+		if planChanged {
+			var namespaced opentofuv1alpha1.TofuExecutionList
+			if err := r.List(ctx, &namespaced, client.InNamespace(module.Namespace)); err != nil {
+				return false, err
+			}
+			if plan := awaitingApproval(executions, namespaced.Items); plan != nil {
+				r.Recorder.Eventf(module, corev1.EventTypeNormal, eventApprovalRequired, "Plan %s has changes waiting for an apply referencing it", plan.Name)
+			}
+		}
 	}
+	observeModule(client.ObjectKeyFromObject(module), module, drifted != nil)
 
 	return moduleChanged, nil
 }
 
-func (r *TofuModuleReconciler) lastExecution(ctx context.Context, module *opentofuv1alpha1.TofuModule) (*opentofuv1alpha1.TofuExecution, error) {
+// ownedExecutions lists the executions controlled by the module, newest first.
+func (r *TofuModuleReconciler) ownedExecutions(ctx context.Context, module *opentofuv1alpha1.TofuModule) ([]*opentofuv1alpha1.TofuExecution, error) {
 	log := logf.FromContext(ctx)
 	var childExecutions opentofuv1alpha1.TofuExecutionList
 	if err := r.List(ctx, &childExecutions, client.InNamespace(module.Namespace)); err != nil {
@@ -145,18 +213,16 @@ func (r *TofuModuleReconciler) lastExecution(ctx context.Context, module *opento
 			ownedExecutions = append(ownedExecutions, exec)
 		}
 	}
-	if len(ownedExecutions) == 0 {
-		return nil, nil
-	}
 
 	// Sort newest first
 	sort.SliceStable(ownedExecutions, func(i, j int) bool {
 		return ownedExecutions[i].CreationTimestamp.Time.After(ownedExecutions[j].CreationTimestamp.Time)
 	})
 
-	return ownedExecutions[0], nil // Latest execution
+	return ownedExecutions, nil
 }
 
+// createExecution creates an execution from the module's template.
 func (r *TofuModuleReconciler) createExecution(ctx context.Context, module *opentofuv1alpha1.TofuModule) (*opentofuv1alpha1.TofuExecution, error) {
 	exec := opentofuv1alpha1.TofuExecution{
 		Spec: module.Spec.ExecutionTemplate.Spec,
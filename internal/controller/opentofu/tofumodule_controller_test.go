@@ -17,7 +17,22 @@ limitations under the License.
 package opentofu
 
 import (
+	"context"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
 )
 
 var _ = Describe("TofuModule Controller", func() {
@@ -30,3 +45,314 @@ var _ = Describe("TofuModule Controller", func() {
 		})
 	})
 })
+
+var _ = Describe("TofuModule retries", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *TofuModuleReconciler
+		module     *opentofuv1alpha1.TofuModule
+	)
+
+	failedExecution := func(reason opentofuv1alpha1.FailureReason, finishedAgo time.Duration) *opentofuv1alpha1.TofuExecution {
+		exec := &opentofuv1alpha1.TofuExecution{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "network-abcde",
+				GenerateName:      "network-",
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+				Annotations:       map[string]string{moduleGenerationAnnotation: "1"},
+			},
+			Spec: opentofuv1alpha1.TofuExecutionSpec{
+				Action:      "apply",
+				ModuleRef:   opentofuv1alpha1.ObjectRef{Name: "network", Namespace: "default"},
+				RetryPolicy: &opentofuv1alpha1.RetryPolicy{MaxRetries: 2, Backoff: &metav1.Duration{Duration: time.Minute}},
+			},
+		}
+		exec.Status.Phase = "Failed"
+		exec.Status.FailureReason = reason
+		exec.Status.FinishedAt = ptr.To(metav1.NewTime(time.Now().Add(-finishedAgo)))
+		return exec
+	}
+	setup := func(execution *opentofuv1alpha1.TofuExecution) {
+		scheme := runtime.NewScheme()
+		Expect(opentofuv1alpha1.AddToScheme(scheme)).To(Succeed())
+		module = &opentofuv1alpha1.TofuModule{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "default", UID: "network-uid", Generation: 1},
+			Spec:       opentofuv1alpha1.TofuModuleSpec{Source: "https://example.com/repo.git"},
+		}
+		Expect(controllerutil.SetControllerReference(module, execution, scheme)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(module, execution).
+			WithStatusSubresource(&opentofuv1alpha1.TofuModule{}, &opentofuv1alpha1.TofuExecution{}).
+			Build()
+		reconciler = &TofuModuleReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(100)}
+	}
+	reconcileModule := func() reconcile.Result {
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "network", Namespace: "default"}})
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+	executions := func() []opentofuv1alpha1.TofuExecution {
+		var list opentofuv1alpha1.TofuExecutionList
+		Expect(fakeClient.List(ctx, &list)).To(Succeed())
+		return list.Items
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("creates a linked attempt for retryable failures once the backoff elapsed", func() {
+		setup(failedExecution(opentofuv1alpha1.FailureApplyError, 2*time.Minute))
+		reconcileModule()
+
+		items := executions()
+		Expect(items).To(HaveLen(2))
+		var retry *opentofuv1alpha1.TofuExecution
+		for i := range items {
+			if items[i].Name != "network-abcde" {
+				retry = &items[i]
+			}
+		}
+		Expect(retry).NotTo(BeNil())
+		Expect(retry.Annotations).To(HaveKeyWithValue(attemptAnnotation, "2"))
+		Expect(retry.Annotations).To(HaveKeyWithValue(retryOfAnnotation, "network-abcde"))
+		Expect(metav1.IsControlledBy(retry, module)).To(BeTrue())
+		Expect(retry.Spec.Action).To(Equal("apply"))
+	})
+
+	It("waits for the backoff before retrying", func() {
+		setup(failedExecution(opentofuv1alpha1.FailureLockContention, 10*time.Second))
+		result := reconcileModule()
+		Expect(result.RequeueAfter).To(BeNumerically(">", 40*time.Second))
+		Expect(executions()).To(HaveLen(1))
+	})
+
+	It("does not retry non-retryable failures", func() {
+		setup(failedExecution(opentofuv1alpha1.FailureInitError, time.Hour))
+		reconcileModule()
+		Expect(executions()).To(HaveLen(1))
+	})
+
+	It("stops after maxRetries and doubles the backoff per attempt", func() {
+		exec := failedExecution(opentofuv1alpha1.FailurePlanError, 0)
+		now := exec.Status.FinishedAt.Time
+
+		exec.Annotations[attemptAnnotation] = "2"
+		retry, delay := retryDelay(exec, now)
+		Expect(retry).To(BeTrue())
+		Expect(delay).To(Equal(2 * time.Minute))
+
+		exec.Annotations[attemptAnnotation] = "3"
+		retry, _ = retryDelay(exec, now)
+		Expect(retry).To(BeFalse())
+	})
+})
+
+var _ = Describe("TofuModule history", func() {
+	newExecution := func(name, action, phase string, age time.Duration) *opentofuv1alpha1.TofuExecution {
+		exec := &opentofuv1alpha1.TofuExecution{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+				Annotations:       map[string]string{moduleGenerationAnnotation: "1"},
+			},
+			Spec: opentofuv1alpha1.TofuExecutionSpec{
+				Action:    action,
+				ModuleRef: opentofuv1alpha1.ObjectRef{Name: "network", Namespace: "default"},
+			},
+		}
+		exec.Status.Phase = phase
+		exec.Status.JobName = name + "-job"
+		return exec
+	}
+
+	It("keeps the newest executions per limit plus the last apply", func() {
+		executions := []*opentofuv1alpha1.TofuExecution{
+			newExecution("e6", "plan", "Running", 1*time.Minute),
+			newExecution("e5", "plan", "Succeeded", 2*time.Minute),
+			newExecution("e4", "plan", "Failed", 3*time.Minute),
+			newExecution("e3", "plan", "Succeeded", 4*time.Minute),
+			newExecution("e2", "apply", "Succeeded", 5*time.Minute),
+			newExecution("e1", "plan", "TimedOut", 6*time.Minute),
+		}
+		lastApply := &opentofuv1alpha1.ExecutionSummary{JobName: "e2-job"}
+
+		names := func(list []*opentofuv1alpha1.TofuExecution) []string {
+			out := []string{}
+			for _, exec := range list {
+				out = append(out, exec.Name)
+			}
+			return out
+		}
+		Expect(names(executionsToPrune(executions, 1, 1, lastApply, nil))).To(Equal([]string{"e3", "e1"}))
+		Expect(names(executionsToPrune(executions, 0, 0, nil, nil))).To(Equal([]string{"e5", "e4", "e3", "e2", "e1"}))
+		Expect(executionsToPrune(executions, 10, 10, nil, nil)).To(BeEmpty())
+	})
+
+	It("never prunes the newest execution of each stack instance", func() {
+		dev := newExecution("dev", "plan", "Succeeded", time.Minute)
+		dev.Labels = map[string]string{stackInstanceLabel: "dev"}
+		prod := newExecution("prod", "plan", "Succeeded", 2*time.Minute)
+		prod.Labels = map[string]string{stackInstanceLabel: "prod"}
+		oldDev := newExecution("old-dev", "plan", "Succeeded", 3*time.Minute)
+		oldDev.Labels = map[string]string{stackInstanceLabel: "dev"}
+
+		pruned := executionsToPrune([]*opentofuv1alpha1.TofuExecution{dev, prod, oldDev}, 1, 1, nil, nil)
+		Expect(pruned).To(Equal([]*opentofuv1alpha1.TofuExecution{oldDev}))
+	})
+
+	It("keeps plans that unfinished applies reference", func() {
+		queued := newExecution("queued", "apply", "Queued", time.Minute)
+		queued.Spec.PlanRef = "p2"
+		applied := newExecution("applied", "apply", "Succeeded", 2*time.Minute)
+		applied.Spec.PlanRef = "p3"
+		executions := []*opentofuv1alpha1.TofuExecution{
+			newExecution("p1", "plan", "Succeeded", 3*time.Minute),
+			newExecution("p2", "plan", "Succeeded", 4*time.Minute),
+			newExecution("p3", "plan", "Succeeded", 5*time.Minute),
+		}
+
+		planRefs := pendingPlanRefs([]opentofuv1alpha1.TofuExecution{*queued, *applied})
+		Expect(planRefs).To(Equal(map[string]bool{"p2": true}))
+		pruned := executionsToPrune(executions, 0, 0, nil, planRefs)
+		Expect(pruned).To(Equal([]*opentofuv1alpha1.TofuExecution{executions[2]}))
+	})
+
+	It("deletes executions beyond the module's history limits on reconcile", func() {
+		ctx := context.Background()
+		scheme := runtime.NewScheme()
+		Expect(opentofuv1alpha1.AddToScheme(scheme)).To(Succeed())
+		module := &opentofuv1alpha1.TofuModule{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "default", UID: "network-uid", Generation: 1},
+			Spec: opentofuv1alpha1.TofuModuleSpec{
+				Source:        "https://example.com/repo.git",
+				HistoryLimits: &opentofuv1alpha1.HistoryLimits{Successful: ptr.To(int32(1))},
+			},
+		}
+		objects := []client.Object{module}
+		for i, name := range []string{"newest", "older", "oldest"} {
+			exec := newExecution(name, "apply", "Succeeded", time.Duration(i+1)*time.Minute)
+			Expect(controllerutil.SetControllerReference(module, exec, scheme)).To(Succeed())
+			objects = append(objects, exec)
+		}
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objects...).
+			WithStatusSubresource(&opentofuv1alpha1.TofuModule{}, &opentofuv1alpha1.TofuExecution{}).
+			Build()
+		reconciler := &TofuModuleReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(100)}
+
+		key := types.NamespacedName{Name: "network", Namespace: "default"}
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		var list opentofuv1alpha1.TofuExecutionList
+		Expect(fakeClient.List(ctx, &list)).To(Succeed())
+		Expect(list.Items).To(HaveLen(1))
+		Expect(list.Items[0].Name).To(Equal("newest"))
+
+		Expect(fakeClient.Get(ctx, key, module)).To(Succeed())
+		Expect(module.Status.LastApply).NotTo(BeNil())
+		Expect(module.Status.LastApply.JobName).To(Equal("newest-job"))
+	})
+})
+
+var _ = Describe("TofuModule drift detection", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *TofuModuleReconciler
+		recorder   *record.FakeRecorder
+	)
+
+	key := types.NamespacedName{Name: "network", Namespace: "default"}
+	finishedExecution := func(name, action string, finishedAgo time.Duration) *opentofuv1alpha1.TofuExecution {
+		exec := &opentofuv1alpha1.TofuExecution{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-finishedAgo - time.Minute)),
+				Annotations:       map[string]string{moduleGenerationAnnotation: "1"},
+			},
+			Spec: opentofuv1alpha1.TofuExecutionSpec{
+				Action:    action,
+				ModuleRef: opentofuv1alpha1.ObjectRef{Name: "network", Namespace: "default"},
+			},
+		}
+		exec.Status.Phase = "Succeeded"
+		exec.Status.JobName = name + "-job"
+		exec.Status.FinishedAt = ptr.To(metav1.NewTime(time.Now().Add(-finishedAgo)))
+		return exec
+	}
+	setup := func(executions ...*opentofuv1alpha1.TofuExecution) {
+		scheme := runtime.NewScheme()
+		Expect(opentofuv1alpha1.AddToScheme(scheme)).To(Succeed())
+		module := &opentofuv1alpha1.TofuModule{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, UID: "network-uid", Generation: 1},
+			Spec: opentofuv1alpha1.TofuModuleSpec{
+				Source:         "https://example.com/repo.git",
+				DriftDetection: &opentofuv1alpha1.DriftDetectionSpec{Enabled: true, Interval: metav1.Duration{Duration: time.Hour}},
+				ExecutionTemplate: opentofuv1alpha1.ExecutionTemplateSpec{
+					Spec: opentofuv1alpha1.TofuExecutionSpec{Action: "apply"},
+				},
+			},
+		}
+		objects := []client.Object{module}
+		for _, exec := range executions {
+			Expect(controllerutil.SetControllerReference(module, exec, scheme)).To(Succeed())
+			objects = append(objects, exec)
+		}
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objects...).
+			WithStatusSubresource(&opentofuv1alpha1.TofuModule{}, &opentofuv1alpha1.TofuExecution{}).
+			Build()
+		recorder = record.NewFakeRecorder(100)
+		reconciler = &TofuModuleReconciler{Client: fakeClient, Scheme: scheme, Recorder: recorder}
+	}
+	reconcileModule := func() reconcile.Result {
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("reports drift when a drift check finds changes", func() {
+		check := finishedExecution("drift-check", "plan", time.Minute)
+		check.Labels = map[string]string{driftCheckLabel: "true"}
+		check.Status.HasChanges = ptr.To(true)
+		setup(check, finishedExecution("applied", "apply", 2*time.Hour))
+		reconcileModule()
+		Expect(recorder.Events).To(Receive(Equal("Warning DriftDetected Drift check drift-check found changes to the infrastructure")))
+		Expect(recorder.Events).To(Receive(Equal("Normal ApprovalRequired Plan drift-check has changes waiting for an apply referencing it")))
+
+		reconcileModule()
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("does not ask for approval once an apply references the plan", func() {
+		plan := finishedExecution("plan", "plan", time.Minute)
+		plan.Status.HasChanges = ptr.To(true)
+		executions := []*opentofuv1alpha1.TofuExecution{plan, finishedExecution("applied", "apply", 2*time.Hour)}
+		Expect(awaitingApproval(executions, nil)).To(Equal(plan))
+
+		apply := finishedExecution("apply-plan", "apply", 0)
+		apply.Spec.PlanRef = plan.Name
+		Expect(awaitingApproval(executions, []opentofuv1alpha1.TofuExecution{*apply})).To(BeNil())
+	})
+
+	It("does not ask for approval when the newest plan failed", func() {
+		failed := finishedExecution("failed", "plan", time.Minute)
+		failed.Status.Phase = "Failed"
+		plan := finishedExecution("plan", "plan", time.Hour)
+		plan.Status.HasChanges = ptr.To(true)
+		Expect(awaitingApproval([]*opentofuv1alpha1.TofuExecution{failed, plan}, nil)).To(BeNil())
+	})
+})
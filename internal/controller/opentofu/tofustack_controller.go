@@ -18,29 +18,44 @@ package opentofu
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"maps"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
 
 	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	"github.com/soyplane-io/soyplane/internal/modulegrant"
+	corev1 "k8s.io/api/core/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // TofuStackReconciler reconciles a TofuStack object
 type TofuStackReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=opentofu.soyplane.io,resources=tofustacks,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=opentofu.soyplane.io,resources=tofustacks/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=opentofu.soyplane.io,resources=tofustacks/finalizers,verbs=update
+// +kubebuilder:rbac:groups=opentofu.soyplane.io,resources=tofumodulegrants,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -60,39 +75,65 @@ func (r *TofuStackReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	lastExecution, err := r.lastExecution(ctx, &stack)
+	executions, err := r.ownedExecutions(ctx, &stack)
 	if err != nil {
 		log.Error(err, "Unable to fetch last TofuExecution")
 		return ctrl.Result{}, err
 	}
+	if err := pruneExecutionHistory(ctx, r.Client, executions, stack.Spec.HistoryLimits, stack.Status.LastApply); err != nil {
+		log.Error(err, "Unable to prune TofuExecution history")
+		return ctrl.Result{}, err
+	}
+
+	module := modulegrant.ModuleKey(stack.Spec.ModuleRef, stack.Namespace)
+	granted, err := modulegrant.Permitted(ctx, r.Client, modulegrant.KindStack, stack.Namespace, module)
+	if err != nil {
+		log.Error(err, "Unable to check TofuModuleGrants")
+		return ctrl.Result{}, err
+	}
+	if !granted {
+		// Grants are watched, so the stack is reconciled again once one permits the reference.
+		return ctrl.Result{}, r.refuseModuleReference(ctx, &stack, module)
+	}
+
+	if instances := stackInstances(&stack); len(instances) > 0 {
+		return r.reconcileInstances(ctx, &stack, instances)
+	}
 
 	desiredGeneration := strconv.FormatInt(stack.GetGeneration(), 10)
 
-	if lastExecution == nil {
-		newExecution, err := r.createExecution(ctx, &stack)
+	if len(executions) == 0 {
+		newExecution, err := r.createExecution(ctx, &stack, nil)
 		if err != nil {
 			log.Error(err, "Unable to create new TofuExecution")
 			return ctrl.Result{}, err
 		}
+		recordExecutionCreated(r.Recorder, &stack, newExecution, "for generation "+desiredGeneration)
 
 		log.Info("TofuStack reconciled, Created TofuExecution", "execution", newExecution.Name)
 		return ctrl.Result{}, nil
 	}
+	lastExecution := executions[0]
 	currentGeneration := ""
 	if lastExecution.Annotations != nil {
 		currentGeneration = lastExecution.Annotations[stackGenerationAnnotation]
 	}
 	if currentGeneration != desiredGeneration && isExecutionTerminal(lastExecution.Status.Phase) {
-		newExecution, err := r.createExecution(ctx, &stack)
+		newExecution, err := r.createExecution(ctx, &stack, nil)
 		if err != nil {
 			log.Error(err, "Unable to create new TofuExecution for updated stack spec")
 			return ctrl.Result{}, err
 		}
+		recordExecutionCreated(r.Recorder, &stack, newExecution, "for generation "+desiredGeneration)
 		log.Info("Stack spec changed — triggered new TofuExecution", "execution", newExecution.Name, "generation", desiredGeneration)
 		return ctrl.Result{}, nil
 	}
 
-	if updated, err := r.updateStackStatus(ctx, &stack, lastExecution); err != nil {
+	status := stack.Status.DeepCopy()
+	status.LastExecutionName = lastExecution.Name
+	status.Phase = lastExecution.Status.Phase
+	summarizeStack(status, stack.GetGeneration(), []string{lastExecution.Status.Phase}, executions, false)
+	if updated, err := r.updateStackStatus(ctx, &stack, status); err != nil {
 		log.Error(err, "Could not update TofuStack status")
 		return ctrl.Result{}, err
 	} else if updated {
@@ -103,35 +144,292 @@ func (r *TofuStackReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	return ctrl.Result{}, nil
 }
 
-func (r *TofuStackReconciler) updateStackStatus(ctx context.Context, stack *opentofuv1alpha1.TofuStack, execution *opentofuv1alpha1.TofuExecution) (bool, error) {
+// updateStackStatus writes status if it differs from the stack's current status.
+func (r *TofuStackReconciler) updateStackStatus(ctx context.Context, stack *opentofuv1alpha1.TofuStack, status *opentofuv1alpha1.TofuStackStatus) (bool, error) {
 	log := logf.FromContext(ctx)
-	stackChanged := false
 
-	if stack.Status.LastExecutionName != execution.Name {
-		stack.Status.LastExecutionName = execution.Name
-		stackChanged = true
+	status.ObservedGeneration = stack.GetGeneration()
+	if equality.Semantic.DeepEqual(&stack.Status, status) {
+		return false, nil
 	}
 
-	if stack.Status.Phase != execution.Status.Phase {
-		stack.Status.Phase = execution.Status.Phase
-		stackChanged = true
+	stack.Status = *status
+	if err := r.Status().Update(ctx, stack); err != nil {
+		if errors.IsConflict(err) {
+			log.Info("Conflict on status update — will retry naturally")
+			return false, nil
+		}
+		return false, err
 	}
 
-	if stackChanged {
-		stack.Status.ObservedGeneration = stack.GetGeneration()
-		if err := r.Status().Update(ctx, stack); err != nil {
-			if errors.IsConflict(err) {
-				log.Info("Conflict on status update — will retry naturally")
-				return false, nil
+	return true, nil
+}
+
+// refuseModuleReference reports that no TofuModuleGrant permits the stack's cross-namespace
+// module reference. No executions are created until one does.
+func (r *TofuStackReconciler) refuseModuleReference(ctx context.Context, stack *opentofuv1alpha1.TofuStack, module types.NamespacedName) error {
+	status := stack.Status.DeepCopy()
+	status.Phase = "Failed"
+	message := fmt.Sprintf("No TofuModuleGrant in namespace %s permits references to module %s", module.Namespace, module.Name)
+	generation := stack.GetGeneration()
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{Type: conditionReady, Status: metav1.ConditionFalse, Reason: reasonReferenceNotPermitted, Message: message, ObservedGeneration: generation})
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{Type: conditionProgressing, Status: metav1.ConditionFalse, Reason: reasonReferenceNotPermitted, Message: message, ObservedGeneration: generation})
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{Type: conditionDegraded, Status: metav1.ConditionTrue, Reason: reasonReferenceNotPermitted, Message: message, ObservedGeneration: generation})
+	if updated, err := r.updateStackStatus(ctx, stack, status); err != nil {
+		return err
+	} else if updated {
+		logf.FromContext(ctx).Info("TofuStack module reference not permitted", "module", module.String())
+		r.Recorder.Event(stack, corev1.EventTypeWarning, reasonReferenceNotPermitted, message)
+	}
+	return nil
+}
+
+// stacksForGrant maps a TofuModuleGrant to the stacks of other namespaces referencing modules of
+// the grant's namespace.
+func (r *TofuStackReconciler) stacksForGrant(ctx context.Context, grant client.Object) []reconcile.Request {
+	var stacks opentofuv1alpha1.TofuStackList
+	if err := r.List(ctx, &stacks); err != nil {
+		logf.FromContext(ctx).Error(err, "Unable to list TofuStacks for TofuModuleGrant", "grant", grant.GetName())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, stack := range stacks.Items {
+		module := modulegrant.ModuleKey(stack.Spec.ModuleRef, stack.Namespace)
+		if module.Namespace == grant.GetNamespace() && stack.Namespace != module.Namespace {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&stack)})
+		}
+	}
+	return requests
+}
+
+// summarizeStack fills the aggregated status fields: execution counts derived from the
+// instance phases, the most recent finished plan and apply, and the standard conditions.
+// executions must be sorted newest first. awaitingApply reports a rollout stopped on a wave
+// whose executions only planned.
+func summarizeStack(status *opentofuv1alpha1.TofuStackStatus, generation int64, phases []string, executions []*opentofuv1alpha1.TofuExecution, awaitingApply bool) {
+	counts := opentofuv1alpha1.TofuStackExecutionCounts{}
+	inFlight := 0
+	for _, phase := range phases {
+		switch phase {
+		case "Succeeded":
+			counts.Succeeded++
+		case "Failed", "Cancelled", "TimedOut":
+			counts.Failed++
+		case "Waiting":
+			counts.Pending++
+		default:
+			counts.Pending++
+			inFlight++
+		}
+	}
+	status.Executions = counts
+
+	status.LastPlan = lastFinishedSummary(executions, "plan", status.LastPlan)
+	status.LastApply = lastFinishedSummary(executions, "apply", status.LastApply)
+
+	total := len(phases)
+	ready := metav1.Condition{Type: conditionReady, ObservedGeneration: generation}
+	progressing := metav1.Condition{Type: conditionProgressing, ObservedGeneration: generation}
+	degraded := metav1.Condition{Type: conditionDegraded, ObservedGeneration: generation}
+
+	switch {
+	case counts.Failed > 0:
+		ready.Status, ready.Reason = metav1.ConditionFalse, "ExecutionFailed"
+		degraded.Status, degraded.Reason = metav1.ConditionTrue, "ExecutionFailed"
+	case counts.Pending > 0:
+		ready.Status, ready.Reason = metav1.ConditionFalse, "ExecutionsInProgress"
+		degraded.Status, degraded.Reason = metav1.ConditionFalse, "NoFailures"
+	default:
+		ready.Status, ready.Reason = metav1.ConditionTrue, "ExecutionsSucceeded"
+		degraded.Status, degraded.Reason = metav1.ConditionFalse, "NoFailures"
+	}
+	ready.Message = fmt.Sprintf("%d of %d executions succeeded", counts.Succeeded, total)
+	degraded.Message = fmt.Sprintf("%d of %d executions failed", counts.Failed, total)
+
+	switch {
+	case inFlight > 0:
+		progressing.Status, progressing.Reason = metav1.ConditionTrue, "ExecutionsInProgress"
+		progressing.Message = fmt.Sprintf("%d executions in progress", inFlight)
+	case counts.Pending > 0 && counts.Failed > 0:
+		progressing.Status, progressing.Reason = metav1.ConditionFalse, "RolloutHalted"
+		progressing.Message = fmt.Sprintf("%d instances are waiting on a failed wave", counts.Pending)
+	case counts.Pending > 0 && awaitingApply:
+		progressing.Status, progressing.Reason = metav1.ConditionFalse, "AwaitingApply"
+		progressing.Message = fmt.Sprintf("%d instances are waiting on a wave that was only planned", counts.Pending)
+	case counts.Pending > 0:
+		// Nothing of the current generation runs, but the instances still start once the
+		// executions of a previous generation finish.
+		progressing.Status, progressing.Reason = metav1.ConditionTrue, "InstancesWaiting"
+		progressing.Message = fmt.Sprintf("%d instances are waiting to start", counts.Pending)
+	default:
+		progressing.Status, progressing.Reason = metav1.ConditionFalse, "RolloutComplete"
+		progressing.Message = "All executions finished"
+	}
+
+	meta.SetStatusCondition(&status.Conditions, ready)
+	meta.SetStatusCondition(&status.Conditions, progressing)
+	meta.SetStatusCondition(&status.Conditions, degraded)
+}
+
+// lastFinishedSummary returns the summary of the newest finished execution running action,
+// falling back to previous when none of the executions qualify.
+func lastFinishedSummary(executions []*opentofuv1alpha1.TofuExecution, action string, previous *opentofuv1alpha1.ExecutionSummary) *opentofuv1alpha1.ExecutionSummary {
+	for _, exec := range executions {
+		if exec.Spec.Action == action && isExecutionTerminal(exec.Status.Phase) {
+			return exec.Status.ExecutionSummary.DeepCopy()
+		}
+	}
+	return previous
+}
+
+// reconcileInstances drives one execution per stack instance, promoting a new stack generation
+// wave by wave, and aggregates the instance phases into the stack status.
+func (r *TofuStackReconciler) reconcileInstances(ctx context.Context, stack *opentofuv1alpha1.TofuStack, instances []opentofuv1alpha1.TofuStackInstance) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	executions, err := r.ownedExecutions(ctx, stack)
+	if err != nil {
+		log.Error(err, "Unable to fetch owned TofuExecutions")
+		return ctrl.Result{}, err
+	}
+
+	rollout := opentofuv1alpha1.TofuStackRolloutSpec{}
+	if stack.Spec.Rollout != nil {
+		rollout = *stack.Spec.Rollout
+	}
+
+	desiredGeneration := strconv.FormatInt(stack.GetGeneration(), 10)
+	isCurrent := func(exec *opentofuv1alpha1.TofuExecution) bool {
+		return exec != nil && exec.Annotations[stackGenerationAnnotation] == desiredGeneration
+	}
+
+	statuses := make([]opentofuv1alpha1.TofuStackInstanceStatus, len(instances))
+	var latest *opentofuv1alpha1.TofuExecution
+	var currentWave int32
+	blocked, awaitingApply := false, false
+	for _, wave := range rolloutWaves(rollout.Strategy, instances) {
+		inFlight := 0
+		failed := false
+		for _, idx := range wave.instances {
+			exec := latestInstanceExecution(executions, instances[idx].Name)
+			if isCurrent(exec) {
+				failed = failed || isExecutionFailed(exec.Status.Phase)
+				if !isExecutionTerminal(exec.Status.Phase) {
+					inFlight++
+				}
 			}
-			return false, err
 		}
+
+		promotable, planned := true, false
+		for _, idx := range wave.instances {
+			instance := &instances[idx]
+			exec := latestInstanceExecution(executions, instance.Name)
+			canStart := !blocked &&
+				!(failed && rollout.StopOnFailure) &&
+				(rollout.MaxParallel == 0 || int32(inFlight) < rollout.MaxParallel) &&
+				(exec == nil || isExecutionTerminal(exec.Status.Phase))
+			if !isCurrent(exec) && canStart {
+				exec, err = r.createExecution(ctx, stack, instance)
+				if err != nil {
+					log.Error(err, "Unable to create TofuExecution for stack instance", "instance", instance.Name)
+					return ctrl.Result{}, err
+				}
+				log.Info("Triggered TofuExecution for stack instance", "instance", instance.Name, "wave", wave.number, "execution", exec.Name, "generation", desiredGeneration)
+				recordExecutionCreated(r.Recorder, stack, exec, fmt.Sprintf("for instance %s of generation %s", instance.Name, desiredGeneration))
+				inFlight++
+			}
+
+			status := opentofuv1alpha1.TofuStackInstanceStatus{Name: instance.Name, Wave: wave.number, Phase: "Waiting"}
+			if exec != nil {
+				status.LastExecutionName = exec.Name
+				if latest == nil || exec.CreationTimestamp.After(latest.CreationTimestamp.Time) {
+					latest = exec
+				}
+			}
+			if isCurrent(exec) {
+				status.Phase = exec.Status.Phase
+			}
+			// Only applied changes promote: a plan says nothing about how the next wave fares.
+			if status.Phase != "Succeeded" {
+				promotable = false
+			} else if exec.Spec.Action != "apply" {
+				promotable, planned = false, true
+			}
+			statuses[idx] = status
+		}
+
+		if !blocked {
+			currentWave = wave.number
+		}
+		if !promotable && !blocked {
+			blocked, awaitingApply = true, planned
+		}
+	}
+
+	phase := aggregateInstancePhase(statuses)
+	lastExecutionName := ""
+	if latest != nil {
+		lastExecutionName = latest.Name
+	}
+	phases := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		phases = append(phases, status.Phase)
+	}
+
+	status := stack.Status.DeepCopy()
+	status.Phase = phase
+	status.LastExecutionName = lastExecutionName
+	status.Instances = statuses
+	status.CurrentWave = currentWave
+	summarizeStack(status, stack.GetGeneration(), phases, executions, awaitingApply)
+	if updated, err := r.updateStackStatus(ctx, stack, status); err != nil {
+		log.Error(err, "Could not update TofuStack status")
+		return ctrl.Result{}, err
+	} else if updated {
+		log.Info("TofuStack reconciled, Status updated", "phase", phase, "wave", currentWave)
+		return ctrl.Result{}, nil
 	}
+	log.Info("TofuStack reconciled, nothing to do")
+	return ctrl.Result{}, nil
+}
 
-	return stackChanged, nil
+// rolloutWave groups the indices of the instances rolled out together.
+type rolloutWave struct {
+	number    int32
+	instances []int
 }
 
-func (r *TofuStackReconciler) lastExecution(ctx context.Context, stack *opentofuv1alpha1.TofuStack) (*opentofuv1alpha1.TofuExecution, error) {
+// rolloutWaves groups instances into waves according to the rollout strategy, in the order
+// they must be rolled out.
+func rolloutWaves(strategy string, instances []opentofuv1alpha1.TofuStackInstance) []rolloutWave {
+	switch strategy {
+	case "Sequential":
+		waves := make([]rolloutWave, 0, len(instances))
+		for i := range instances {
+			waves = append(waves, rolloutWave{number: int32(i), instances: []int{i}})
+		}
+		return waves
+	case "Waves":
+		byNumber := make(map[int32][]int)
+		for i := range instances {
+			byNumber[instances[i].Wave] = append(byNumber[instances[i].Wave], i)
+		}
+		waves := make([]rolloutWave, 0, len(byNumber))
+		for _, number := range slices.Sorted(maps.Keys(byNumber)) {
+			waves = append(waves, rolloutWave{number: number, instances: byNumber[number]})
+		}
+		return waves
+	default:
+		wave := rolloutWave{instances: make([]int, 0, len(instances))}
+		for i := range instances {
+			wave.instances = append(wave.instances, i)
+		}
+		return []rolloutWave{wave}
+	}
+}
+
+// ownedExecutions lists the executions controlled by the stack, newest first.
+func (r *TofuStackReconciler) ownedExecutions(ctx context.Context, stack *opentofuv1alpha1.TofuStack) ([]*opentofuv1alpha1.TofuExecution, error) {
 	log := logf.FromContext(ctx)
 	var childExecutions opentofuv1alpha1.TofuExecutionList
 	if err := r.List(ctx, &childExecutions, client.InNamespace(stack.Namespace)); err != nil {
@@ -145,30 +443,115 @@ func (r *TofuStackReconciler) lastExecution(ctx context.Context, stack *opentofu
 			ownedExecutions = append(ownedExecutions, exec)
 		}
 	}
-	if len(ownedExecutions) == 0 {
-		return nil, nil
-	}
 
 	// Sort newest first
 	sort.SliceStable(ownedExecutions, func(i, j int) bool {
 		return ownedExecutions[i].CreationTimestamp.Time.After(ownedExecutions[j].CreationTimestamp.Time)
 	})
 
-	return ownedExecutions[0], nil // Latest execution
+	return ownedExecutions, nil
+}
+
+// latestInstanceExecution returns the newest execution generated for the named instance.
+// executions must be sorted newest first.
+func latestInstanceExecution(executions []*opentofuv1alpha1.TofuExecution, instance string) *opentofuv1alpha1.TofuExecution {
+	for _, exec := range executions {
+		if exec.Labels[stackInstanceLabel] == instance {
+			return exec
+		}
+	}
+	return nil
+}
+
+// stackInstances returns the explicit instances followed by the matrix expansion.
+func stackInstances(stack *opentofuv1alpha1.TofuStack) []opentofuv1alpha1.TofuStackInstance {
+	instances := make([]opentofuv1alpha1.TofuStackInstance, 0, len(stack.Spec.Instances))
+	for i := range stack.Spec.Instances {
+		instances = append(instances, *stack.Spec.Instances[i].DeepCopy())
+	}
+	if len(stack.Spec.Matrix) == 0 {
+		return instances
+	}
+
+	combinations := [][]string{{}}
+	for _, axis := range stack.Spec.Matrix {
+		next := make([][]string, 0, len(combinations)*len(axis.Values))
+		for _, combination := range combinations {
+			for _, value := range axis.Values {
+				next = append(next, append(slices.Clone(combination), value))
+			}
+		}
+		combinations = next
+	}
+
+	for _, combination := range combinations {
+		instance := opentofuv1alpha1.TofuStackInstance{
+			Name:      strings.Join(combination, "-"),
+			Variables: make(map[string]apiextv1.JSON, len(combination)),
+		}
+		for i, value := range combination {
+			raw, _ := json.Marshal(value)
+			instance.Variables[stack.Spec.Matrix[i].Name] = apiextv1.JSON{Raw: raw}
+		}
+		instances = append(instances, instance)
+	}
+	return instances
 }
 
-func (r *TofuStackReconciler) createExecution(ctx context.Context, stack *opentofuv1alpha1.TofuStack) (*opentofuv1alpha1.TofuExecution, error) {
+// aggregateInstancePhase folds instance phases into a single stack phase: any failure wins,
+// then in-flight work, and the stack only succeeds once every instance has.
+func aggregateInstancePhase(statuses []opentofuv1alpha1.TofuStackInstanceStatus) string {
+	phase := "Succeeded"
+	for _, status := range statuses {
+		switch status.Phase {
+		case "Failed", "Cancelled", "TimedOut":
+			return "Failed"
+		case "Running":
+			phase = "Running"
+		case "Succeeded":
+		default:
+			if phase != "Running" {
+				phase = "Pending"
+			}
+		}
+	}
+	return phase
+}
+
+func (r *TofuStackReconciler) createExecution(ctx context.Context, stack *opentofuv1alpha1.TofuStack, instance *opentofuv1alpha1.TofuStackInstance) (*opentofuv1alpha1.TofuExecution, error) {
 	exec := opentofuv1alpha1.TofuExecution{
-		Spec: stack.Spec.ExecutionTemplate.Spec,
+		Spec: *stack.Spec.ExecutionTemplate.Spec.DeepCopy(),
 	}
 	generateName := stack.Spec.ExecutionTemplate.Metadata.GenerateName
 	if generateName == "" {
 		generateName = fmt.Sprintf("%s-", stack.Name)
 	}
+	if instance != nil {
+		generateName = fmt.Sprintf("%s%s-", generateName, instance.Name)
+	}
 	exec.GenerateName = generateName
 	exec.Namespace = stack.Namespace
 
 	exec.Labels = maps.Clone(stack.Spec.ExecutionTemplate.Metadata.Labels)
+	if instance != nil {
+		if exec.Labels == nil {
+			exec.Labels = make(map[string]string)
+		}
+		exec.Labels[stackInstanceLabel] = instance.Name
+
+		exec.Spec.Workspace = instance.Workspace
+		if exec.Spec.Workspace == "" {
+			exec.Spec.Workspace = instance.Name
+		}
+		if len(instance.Variables) > 0 && exec.Spec.Variables == nil {
+			exec.Spec.Variables = make(map[string]apiextv1.JSON, len(instance.Variables))
+		}
+		maps.Copy(exec.Spec.Variables, instance.Variables)
+		if len(instance.ValueSources) > 0 && exec.Spec.ValueSources == nil {
+			exec.Spec.ValueSources = make(map[string]opentofuv1alpha1.ValueFrom, len(instance.ValueSources))
+		}
+		maps.Copy(exec.Spec.ValueSources, instance.ValueSources)
+	}
 	exec.Annotations = maps.Clone(stack.Spec.ExecutionTemplate.Metadata.Annotations)
 	if exec.Annotations == nil {
 		exec.Annotations = make(map[string]string)
@@ -198,6 +581,7 @@ func (r *TofuStackReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&opentofuv1alpha1.TofuStack{}).
 		Owns(&opentofuv1alpha1.TofuExecution{}).
+		Watches(&opentofuv1alpha1.TofuModuleGrant{}, handler.EnqueueRequestsFromMapFunc(r.stacksForGrant)).
 		Named("opentofu_tofustack").
 		Complete(r)
 }
@@ -21,9 +21,14 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -133,8 +138,9 @@ var _ = Describe("TofuStack Controller", func() {
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
 			controllerReconciler := &TofuStackReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(100),
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -146,3 +152,316 @@ var _ = Describe("TofuStack Controller", func() {
 		})
 	})
 })
+
+var _ = Describe("TofuStack instances", func() {
+	newStack := func() *opentofuv1alpha1.TofuStack {
+		return &opentofuv1alpha1.TofuStack{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "envs",
+				Namespace:  "default",
+				UID:        types.UID("stack-uid"),
+				Generation: 1,
+			},
+			Spec: opentofuv1alpha1.TofuStackSpec{
+				ModuleRef: opentofuv1alpha1.ObjectRef{Name: "network"},
+				ExecutionTemplate: opentofuv1alpha1.ExecutionTemplateSpec{
+					Spec: opentofuv1alpha1.TofuExecutionSpec{
+						Action: "plan",
+						Variables: map[string]apiextv1.JSON{
+							"size": {Raw: []byte(`"small"`)},
+						},
+					},
+				},
+				Instances: []opentofuv1alpha1.TofuStackInstance{
+					{Name: "dev"},
+					{
+						Name:      "prod",
+						Workspace: "production",
+						Variables: map[string]apiextv1.JSON{
+							"size": {Raw: []byte(`"large"`)},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	It("expands the matrix after the explicit instances", func() {
+		stack := newStack()
+		stack.Spec.Matrix = []opentofuv1alpha1.TofuStackMatrixAxis{
+			{Name: "region", Values: []string{"eu", "us"}},
+			{Name: "tier", Values: []string{"a", "b"}},
+		}
+
+		instances := stackInstances(stack)
+		names := make([]string, 0, len(instances))
+		for _, instance := range instances {
+			names = append(names, instance.Name)
+		}
+		Expect(names).To(Equal([]string{"dev", "prod", "eu-a", "eu-b", "us-a", "us-b"}))
+		Expect(instances[5].Variables).To(HaveKeyWithValue("region", apiextv1.JSON{Raw: []byte(`"us"`)}))
+		Expect(instances[5].Variables).To(HaveKeyWithValue("tier", apiextv1.JSON{Raw: []byte(`"b"`)}))
+	})
+
+	It("creates one execution per instance and aggregates their phases", func() {
+		scheme := runtime.NewScheme()
+		Expect(opentofuv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		stack := newStack()
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(stack).
+			WithStatusSubresource(stack, &opentofuv1alpha1.TofuExecution{}).
+			Build()
+		reconciler := &TofuStackReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(100)}
+
+		ctx := context.Background()
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: stack.Name, Namespace: stack.Namespace}}
+		_, err := reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+
+		var executions opentofuv1alpha1.TofuExecutionList
+		Expect(fakeClient.List(ctx, &executions)).To(Succeed())
+		Expect(executions.Items).To(HaveLen(2))
+		byInstance := map[string]*opentofuv1alpha1.TofuExecution{}
+		for i := range executions.Items {
+			exec := &executions.Items[i]
+			byInstance[exec.Labels[stackInstanceLabel]] = exec
+		}
+		Expect(byInstance).To(HaveKey("dev"))
+		Expect(byInstance).To(HaveKey("prod"))
+		Expect(byInstance["dev"].Spec.Workspace).To(Equal("dev"))
+		Expect(byInstance["dev"].Spec.Variables).To(HaveKeyWithValue("size", apiextv1.JSON{Raw: []byte(`"small"`)}))
+		Expect(byInstance["prod"].Spec.Workspace).To(Equal("production"))
+		Expect(byInstance["prod"].Spec.Variables).To(HaveKeyWithValue("size", apiextv1.JSON{Raw: []byte(`"large"`)}))
+		Expect(byInstance["prod"].Spec.ModuleRef).To(Equal(opentofuv1alpha1.ObjectRef{Name: "network", Namespace: "default"}))
+
+		byInstance["dev"].Status.Phase = "Succeeded"
+		byInstance["dev"].Status.JobName = "dev-job"
+		Expect(fakeClient.Status().Update(ctx, byInstance["dev"])).To(Succeed())
+		byInstance["prod"].Status.Phase = "Running"
+		Expect(fakeClient.Status().Update(ctx, byInstance["prod"])).To(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, req.NamespacedName, stack)).To(Succeed())
+		Expect(stack.Status.Phase).To(Equal("Running"))
+		Expect(stack.Status.Instances).To(ConsistOf(
+			opentofuv1alpha1.TofuStackInstanceStatus{Name: "dev", Phase: "Succeeded", LastExecutionName: byInstance["dev"].Name},
+			opentofuv1alpha1.TofuStackInstanceStatus{Name: "prod", Phase: "Running", LastExecutionName: byInstance["prod"].Name},
+		))
+		Expect(stack.Status.Executions).To(Equal(opentofuv1alpha1.TofuStackExecutionCounts{Succeeded: 1, Pending: 1}))
+		Expect(stack.Status.LastPlan).NotTo(BeNil())
+		Expect(stack.Status.LastPlan.JobName).To(Equal("dev-job"))
+		Expect(stack.Status.LastApply).To(BeNil())
+		Expect(meta.IsStatusConditionFalse(stack.Status.Conditions, conditionReady)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(stack.Status.Conditions, conditionProgressing)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(stack.Status.Conditions, conditionDegraded)).To(BeTrue())
+
+		byInstance["prod"].Status.Phase = "Failed"
+		Expect(fakeClient.Status().Update(ctx, byInstance["prod"])).To(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, req.NamespacedName, stack)).To(Succeed())
+		Expect(stack.Status.Executions).To(Equal(opentofuv1alpha1.TofuStackExecutionCounts{Succeeded: 1, Failed: 1}))
+		degraded := meta.FindStatusCondition(stack.Status.Conditions, conditionDegraded)
+		Expect(degraded).NotTo(BeNil())
+		Expect(degraded.Status).To(Equal(metav1.ConditionTrue))
+		Expect(degraded.Reason).To(Equal("ExecutionFailed"))
+		Expect(meta.FindStatusCondition(stack.Status.Conditions, conditionProgressing).Reason).To(Equal("RolloutComplete"))
+
+		Expect(fakeClient.List(ctx, &executions)).To(Succeed())
+		Expect(executions.Items).To(HaveLen(2))
+	})
+
+	It("only creates executions of modules in other namespaces once a grant permits it", func() {
+		scheme := runtime.NewScheme()
+		Expect(opentofuv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		stack := newStack()
+		stack.Spec.ModuleRef.Namespace = "infra"
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(stack).
+			WithStatusSubresource(stack, &opentofuv1alpha1.TofuExecution{}).
+			Build()
+		reconciler := &TofuStackReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(100)}
+
+		ctx := context.Background()
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: stack.Name, Namespace: stack.Namespace}}
+		_, err := reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+
+		var executions opentofuv1alpha1.TofuExecutionList
+		Expect(fakeClient.List(ctx, &executions)).To(Succeed())
+		Expect(executions.Items).To(BeEmpty())
+		Expect(fakeClient.Get(ctx, req.NamespacedName, stack)).To(Succeed())
+		Expect(stack.Status.Phase).To(Equal("Failed"))
+		ready := meta.FindStatusCondition(stack.Status.Conditions, conditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Reason).To(Equal(reasonReferenceNotPermitted))
+
+		grant := &opentofuv1alpha1.TofuModuleGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: "default-stacks", Namespace: "infra"},
+			Spec: opentofuv1alpha1.TofuModuleGrantSpec{
+				From: []opentofuv1alpha1.TofuModuleGrantFrom{{Kind: "TofuStack", Namespace: "default"}},
+				To:   []opentofuv1alpha1.TofuModuleGrantTo{{Name: "network"}},
+			},
+		}
+		Expect(fakeClient.Create(ctx, grant)).To(Succeed())
+		Expect(reconciler.stacksForGrant(ctx, grant)).To(ConsistOf(req))
+
+		_, err = reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeClient.List(ctx, &executions)).To(Succeed())
+		Expect(executions.Items).To(HaveLen(2))
+		Expect(executions.Items[0].Spec.ModuleRef).To(Equal(opentofuv1alpha1.ObjectRef{Name: "network", Namespace: "infra"}))
+	})
+
+	It("promotes sequential instances only after the previous wave succeeded", func() {
+		scheme := runtime.NewScheme()
+		Expect(opentofuv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		stack := newStack()
+		stack.Spec.Instances = append(stack.Spec.Instances, opentofuv1alpha1.TofuStackInstance{Name: "dr"})
+		stack.Spec.ExecutionTemplate.Spec.Action = "apply"
+		stack.Spec.Rollout = &opentofuv1alpha1.TofuStackRolloutSpec{Strategy: "Sequential"}
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(stack).
+			WithStatusSubresource(stack, &opentofuv1alpha1.TofuExecution{}).
+			Build()
+		reconciler := &TofuStackReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(100)}
+
+		ctx := context.Background()
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: stack.Name, Namespace: stack.Namespace}}
+		executionFor := func(instance string) *opentofuv1alpha1.TofuExecution {
+			var executions opentofuv1alpha1.TofuExecutionList
+			Expect(fakeClient.List(ctx, &executions)).To(Succeed())
+			for i := range executions.Items {
+				if executions.Items[i].Labels[stackInstanceLabel] == instance {
+					return &executions.Items[i]
+				}
+			}
+			return nil
+		}
+
+		_, err := reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(executionFor("dev")).NotTo(BeNil())
+		Expect(executionFor("prod")).To(BeNil())
+		Expect(fakeClient.Get(ctx, req.NamespacedName, stack)).To(Succeed())
+		Expect(stack.Status.CurrentWave).To(Equal(int32(0)))
+		Expect(stack.Status.Instances[1].Phase).To(Equal("Waiting"))
+
+		dev := executionFor("dev")
+		dev.Status.Phase = "Succeeded"
+		Expect(fakeClient.Status().Update(ctx, dev)).To(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(executionFor("prod")).NotTo(BeNil())
+		Expect(executionFor("dr")).To(BeNil())
+		Expect(fakeClient.Get(ctx, req.NamespacedName, stack)).To(Succeed())
+		Expect(stack.Status.CurrentWave).To(Equal(int32(1)))
+
+		prod := executionFor("prod")
+		prod.Status.Phase = "Failed"
+		Expect(fakeClient.Status().Update(ctx, prod)).To(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(executionFor("dr")).To(BeNil())
+		Expect(fakeClient.Get(ctx, req.NamespacedName, stack)).To(Succeed())
+		Expect(stack.Status.Phase).To(Equal("Failed"))
+		Expect(stack.Status.CurrentWave).To(Equal(int32(1)))
+		Expect(stack.Status.Instances[2]).To(Equal(opentofuv1alpha1.TofuStackInstanceStatus{Name: "dr", Wave: 2, Phase: "Waiting"}))
+	})
+
+	It("does not promote a wave that was only planned", func() {
+		scheme := runtime.NewScheme()
+		Expect(opentofuv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		stack := newStack()
+		stack.Spec.Rollout = &opentofuv1alpha1.TofuStackRolloutSpec{Strategy: "Sequential"}
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(stack).
+			WithStatusSubresource(stack, &opentofuv1alpha1.TofuExecution{}).
+			Build()
+		reconciler := &TofuStackReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(100)}
+
+		ctx := context.Background()
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: stack.Name, Namespace: stack.Namespace}}
+		_, err := reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+
+		var executions opentofuv1alpha1.TofuExecutionList
+		Expect(fakeClient.List(ctx, &executions)).To(Succeed())
+		Expect(executions.Items).To(HaveLen(1))
+		dev := &executions.Items[0]
+		dev.Status.Phase = "Succeeded"
+		Expect(fakeClient.Status().Update(ctx, dev)).To(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeClient.List(ctx, &executions)).To(Succeed())
+		Expect(executions.Items).To(HaveLen(1))
+		Expect(fakeClient.Get(ctx, req.NamespacedName, stack)).To(Succeed())
+		Expect(stack.Status.CurrentWave).To(Equal(int32(0)))
+		progressing := meta.FindStatusCondition(stack.Status.Conditions, conditionProgressing)
+		Expect(progressing).NotTo(BeNil())
+		Expect(progressing.Reason).To(Equal("AwaitingApply"))
+	})
+
+	It("caps in-flight executions within a wave", func() {
+		instances := []opentofuv1alpha1.TofuStackInstance{
+			{Name: "eu", Wave: 1},
+			{Name: "dev", Wave: 0},
+			{Name: "us", Wave: 1},
+		}
+		waves := rolloutWaves("Waves", instances)
+		Expect(waves).To(Equal([]rolloutWave{
+			{number: 0, instances: []int{1}},
+			{number: 1, instances: []int{0, 2}},
+		}))
+
+		scheme := runtime.NewScheme()
+		Expect(opentofuv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		stack := newStack()
+		stack.Spec.Rollout = &opentofuv1alpha1.TofuStackRolloutSpec{MaxParallel: 1}
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(stack).
+			WithStatusSubresource(stack, &opentofuv1alpha1.TofuExecution{}).
+			Build()
+		reconciler := &TofuStackReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(100)}
+
+		ctx := context.Background()
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: stack.Name, Namespace: stack.Namespace}}
+		_, err := reconciler.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+
+		var executions opentofuv1alpha1.TofuExecutionList
+		Expect(fakeClient.List(ctx, &executions)).To(Succeed())
+		Expect(executions.Items).To(HaveLen(1))
+	})
+
+	It("reports a halted rollout only when a wave failed", func() {
+		status := &opentofuv1alpha1.TofuStackStatus{}
+		summarizeStack(status, 1, []string{"Succeeded", "Failed", "Waiting"}, nil, false)
+		progressing := meta.FindStatusCondition(status.Conditions, conditionProgressing)
+		Expect(progressing.Status).To(Equal(metav1.ConditionFalse))
+		Expect(progressing.Reason).To(Equal("RolloutHalted"))
+
+		status = &opentofuv1alpha1.TofuStackStatus{}
+		summarizeStack(status, 1, []string{"Succeeded", "Waiting"}, nil, false)
+		progressing = meta.FindStatusCondition(status.Conditions, conditionProgressing)
+		Expect(progressing.Status).To(Equal(metav1.ConditionTrue))
+		Expect(progressing.Reason).To(Equal("InstancesWaiting"))
+	})
+})
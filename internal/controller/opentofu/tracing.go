@@ -0,0 +1,128 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package opentofu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	settings "github.com/soyplane-io/soyplane/internal/settings"
+	"github.com/soyplane-io/soyplane/internal/tracing"
+)
+
+const (
+	// phaseReport prefixes the termination message lines recording when each phase of the
+	// engine script started.
+	phaseReport = "phase"
+
+	// phasesFile collects the phase lines until the engine script exits. They are appended to
+	// the termination message then, ahead of the lock file report.
+	phasesFile = "/tmp/soyplane.phases"
+)
+
+// phaseMark records the start of the named phase of the engine script.
+func phaseMark(name string) string {
+	return fmt.Sprintf(`echo "%s %s $(date +%%s)" >> %s;`, phaseReport, name, phasesFile)
+}
+
+// phaseReportScript appends the phase lines to the termination message.
+func phaseReportScript() string {
+	return fmt.Sprintf(`cat %s >> /dev/termination-log 2>/dev/null;`, phasesFile)
+}
+
+// executionPhase is a phase of the engine script and when it started.
+type executionPhase struct {
+	name  string
+	start time.Time
+}
+
+// parseExecutionPhases reads the phase lines written by phaseMark, in the order the phases ran.
+func parseExecutionPhases(message string) []executionPhase {
+	var phases []executionPhase
+	for _, line := range strings.Split(message, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[0] != phaseReport {
+			continue
+		}
+		seconds, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		phases = append(phases, executionPhase{name: fields[1], start: time.Unix(seconds, 0)})
+	}
+	return phases
+}
+
+// traceExecution records the finished execution's root span, from its creation until it
+// finished, and a span for every phase of its engine script. A phase lasts until the next one
+// starts; the last one until the Job finished.
+func traceExecution(ctx context.Context, execution *opentofuv1alpha1.TofuExecution, phases []executionPhase) {
+	end := time.Now()
+	if execution.Status.FinishedAt != nil {
+		end = execution.Status.FinishedAt.Time
+	}
+	phaseCtx := tracing.ExecutionContext(ctx, execution.UID)
+	for i, phase := range phases {
+		phaseEnd := end
+		if i+1 < len(phases) {
+			phaseEnd = phases[i+1].start
+		}
+		tracing.RecordSpan(phaseCtx, phase.name, phase.start, phaseEnd)
+	}
+
+	var err error
+	if isExecutionFailed(execution.Status.Phase) {
+		err = errors.New(execution.Status.Summary)
+	}
+	tracing.RecordExecution(ctx, execution.UID, "TofuExecution "+execution.Spec.Action, execution.CreationTimestamp.Time, end, err,
+		trace.WithAttributes(
+			attribute.String("k8s.namespace.name", execution.Namespace),
+			attribute.String("soyplane.execution", execution.Name),
+			attribute.String("soyplane.module", moduleKey(execution).String()),
+			attribute.String("soyplane.action", execution.Spec.Action),
+			attribute.String("soyplane.engine", executionEngine(execution)),
+			attribute.String("soyplane.phase", execution.Status.Phase),
+		))
+}
+
+// tracingEnv continues the execution's trace in its pod: the agent exports its spans under the
+// span in ctx when the execution is traced.
+func tracingEnv(ctx context.Context, cfg settings.TracingSettings) []corev1.EnvVar {
+	traceParent := tracing.TraceParent(ctx)
+	if !cfg.Enabled || traceParent == "" {
+		return nil
+	}
+	env := []corev1.EnvVar{
+		{Name: tracing.TraceParentEnvVar, Value: traceParent},
+		{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Value: "https://" + cfg.Endpoint},
+		{Name: "OTEL_SERVICE_NAME", Value: "soyplane-agent"},
+	}
+	if cfg.Insecure {
+		env[1].Value = "http://" + cfg.Endpoint
+		env = append(env, corev1.EnvVar{Name: "OTEL_EXPORTER_OTLP_INSECURE", Value: "true"})
+	}
+	return env
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package modulegrant decides whether cross-namespace TofuModule references are permitted by the
+// TofuModuleGrants of the module's namespace. The admission webhooks and the controllers share it
+// so that both enforce the same rules.
+package modulegrant
+
+import (
+	"context"
+	"fmt"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+const (
+	// KindExecution is the grant kind of TofuExecutions referencing a module directly.
+	KindExecution = "TofuExecution"
+	// KindStack is the grant kind of TofuStacks and of the executions they create.
+	KindStack = "TofuStack"
+)
+
+// ModuleKey returns the module referenced by ref from namespace, defaulting the module namespace.
+func ModuleKey(ref opentofuv1alpha1.ObjectRef, namespace string) types.NamespacedName {
+	key := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+	if key.Namespace == "" {
+		key.Namespace = namespace
+	}
+	return key
+}
+
+// Permitted reports whether an object of kind in namespace may reference module. References
+// within a namespace need no grant.
+func Permitted(ctx context.Context, c client.Reader, kind, namespace string, module types.NamespacedName) (bool, error) {
+	if module.Namespace == namespace {
+		return true, nil
+	}
+	var grants opentofuv1alpha1.TofuModuleGrantList
+	if err := c.List(ctx, &grants, client.InNamespace(module.Namespace)); err != nil {
+		return false, err
+	}
+	for i := range grants.Items {
+		if grants.Items[i].Permits(kind, namespace, module.Name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ExecutionKind returns the grant kind covering execution. Executions controlled by a TofuStack
+// that references the same module are covered by the stack's grants; any other execution needs a
+// grant of its own.
+func ExecutionKind(ctx context.Context, c client.Reader, execution *opentofuv1alpha1.TofuExecution) (string, error) {
+	owner := metav1.GetControllerOf(execution)
+	if owner == nil || owner.Kind != KindStack {
+		return KindExecution, nil
+	}
+	if gv, err := schema.ParseGroupVersion(owner.APIVersion); err != nil || gv.Group != opentofuv1alpha1.GroupVersion.Group {
+		return KindExecution, nil
+	}
+	var stack opentofuv1alpha1.TofuStack
+	if err := c.Get(ctx, types.NamespacedName{Namespace: execution.Namespace, Name: owner.Name}, &stack); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return KindExecution, nil
+		}
+		return "", fmt.Errorf("getting owning TofuStack %s: %w", owner.Name, err)
+	}
+	if stack.UID != owner.UID || ModuleKey(stack.Spec.ModuleRef, stack.Namespace) != ModuleKey(execution.Spec.ModuleRef, execution.Namespace) {
+		return KindExecution, nil
+	}
+	return KindStack, nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package modulegrant
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+func newReader(t *testing.T, objects ...runtime.Object) *fake.ClientBuilder {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := opentofuv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...)
+}
+
+func TestPermitted(t *testing.T) {
+	grant := &opentofuv1alpha1.TofuModuleGrant{
+		ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: "infra"},
+		Spec: opentofuv1alpha1.TofuModuleGrantSpec{
+			From: []opentofuv1alpha1.TofuModuleGrantFrom{{Kind: KindStack, Namespace: "apps"}},
+			To:   []opentofuv1alpha1.TofuModuleGrantTo{{Name: "network"}},
+		},
+	}
+	reader := newReader(t, grant).Build()
+
+	tests := []struct {
+		name      string
+		kind      string
+		namespace string
+		module    types.NamespacedName
+		want      bool
+	}{
+		{"same namespace", KindExecution, "infra", types.NamespacedName{Namespace: "infra", Name: "database"}, true},
+		{"granted", KindStack, "apps", types.NamespacedName{Namespace: "infra", Name: "network"}, true},
+		{"other module", KindStack, "apps", types.NamespacedName{Namespace: "infra", Name: "database"}, false},
+		{"other kind", KindExecution, "apps", types.NamespacedName{Namespace: "infra", Name: "network"}, false},
+		{"other namespace", KindStack, "team", types.NamespacedName{Namespace: "infra", Name: "network"}, false},
+		{"no grants", KindStack, "apps", types.NamespacedName{Namespace: "shared", Name: "network"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Permitted(context.Background(), reader, tt.kind, tt.namespace, tt.module)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Permitted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutionKind(t *testing.T) {
+	stack := &opentofuv1alpha1.TofuStack{
+		ObjectMeta: metav1.ObjectMeta{Name: "envs", Namespace: "apps", UID: "stack-uid"},
+		Spec:       opentofuv1alpha1.TofuStackSpec{ModuleRef: opentofuv1alpha1.ObjectRef{Name: "network", Namespace: "infra"}},
+	}
+	reader := newReader(t, stack).Build()
+	owned := func(name string, uid types.UID, module string) *opentofuv1alpha1.TofuExecution {
+		return &opentofuv1alpha1.TofuExecution{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "run",
+				Namespace: "apps",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: opentofuv1alpha1.GroupVersion.String(),
+					Kind:       KindStack,
+					Name:       name,
+					UID:        uid,
+					Controller: ptr.To(true),
+				}},
+			},
+			Spec: opentofuv1alpha1.TofuExecutionSpec{ModuleRef: opentofuv1alpha1.ObjectRef{Name: module, Namespace: "infra"}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		execution *opentofuv1alpha1.TofuExecution
+		want      string
+	}{
+		{"stack execution", owned("envs", "stack-uid", "network"), KindStack},
+		{"other module", owned("envs", "stack-uid", "database"), KindExecution},
+		{"forged owner", owned("envs", "other-uid", "network"), KindExecution},
+		{"missing stack", owned("gone", "gone-uid", "network"), KindExecution},
+		{"no owner", &opentofuv1alpha1.TofuExecution{ObjectMeta: metav1.ObjectMeta{Name: "run", Namespace: "apps"}}, KindExecution},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExecutionKind(context.Background(), reader, tt.execution)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ExecutionKind() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,382 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	ctrmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+var log = logf.Log.WithName("notification")
+
+var (
+	deliveries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "soyplane",
+			Subsystem: "notification",
+			Name:      "deliveries_total",
+			Help:      "Number of notifications delivered partitioned by TofuNotification.",
+		},
+		[]string{"notification"},
+	)
+
+	deadLetters = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "soyplane",
+			Subsystem: "notification",
+			Name:      "dead_letters_total",
+			Help:      "Number of notifications dropped after all delivery attempts failed partitioned by TofuNotification.",
+		},
+		[]string{"notification"},
+	)
+)
+
+func init() {
+	ctrmetrics.Registry.MustRegister(deliveries, deadLetters)
+}
+
+const (
+	// queueSize bounds the events waiting to be matched against notifications. Events arriving
+	// while the queue is full are dropped rather than blocking reconciles.
+	queueSize = 256
+	// requestTimeout bounds a single delivery attempt.
+	requestTimeout = 10 * time.Second
+)
+
+// +kubebuilder:rbac:groups=opentofu.soyplane.io,resources=tofunotifications,verbs=get;list;watch
+// +kubebuilder:rbac:groups=opentofu.soyplane.io,resources=tofunotifications/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// Dispatcher matches events against TofuNotifications and posts them to their sinks, retrying
+// failed deliveries with exponential backoff. It runs as a manager Runnable.
+type Dispatcher struct {
+	// Client lists notifications, reads namespaces and modules and updates notification statuses.
+	Client client.Client
+	// Reader reads the Secrets holding sink URLs. It should bypass the cache so that Secrets
+	// are not watched cluster-wide.
+	Reader     client.Reader
+	HTTPClient *http.Client
+	// Attempts is how many times a delivery is attempted before it is dropped.
+	Attempts int
+	// Backoff is the wait before the first retry. It doubles on every retry.
+	Backoff time.Duration
+
+	events chan Event
+}
+
+// NewDispatcher returns a Dispatcher attempting deliveries 5 times, waiting 1s, 2s, 4s and 8s
+// between attempts.
+func NewDispatcher(c client.Client, reader client.Reader) *Dispatcher {
+	return &Dispatcher{
+		Client:     c,
+		Reader:     reader,
+		HTTPClient: http.DefaultClient,
+		Attempts:   5,
+		Backoff:    time.Second,
+		events:     make(chan Event, queueSize),
+	}
+}
+
+// Notify queues e for delivery.
+func (d *Dispatcher) Notify(e Event) {
+	select {
+	case d.events <- e:
+	default:
+		log.Error(errors.New("notification queue is full"), "Dropping event",
+			"event", e.Type, "namespace", e.Object.GetNamespace(), "name", e.Object.GetName())
+	}
+}
+
+// Start delivers queued events until ctx is done.
+func (d *Dispatcher) Start(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-d.events:
+			notifications, err := d.matching(ctx, e)
+			if err != nil {
+				log.Error(err, "Unable to match event against notifications", "event", e.Type)
+				continue
+			}
+			for _, n := range notifications {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					d.deliver(ctx, n, e)
+				}()
+			}
+		}
+	}
+}
+
+// Recorder returns an EventRecorder recording through recorder that also notifies the Events
+// recorded on executions and modules that TofuNotifications can subscribe to.
+func (d *Dispatcher) Recorder(recorder record.EventRecorder) record.EventRecorder {
+	return &notifyingRecorder{EventRecorder: recorder, dispatcher: d}
+}
+
+type notifyingRecorder struct {
+	record.EventRecorder
+	dispatcher *Dispatcher
+}
+
+func (r *notifyingRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.EventRecorder.Event(object, eventtype, reason, message)
+	r.notify(object, reason, message)
+}
+
+func (r *notifyingRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	r.notify(object, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *notifyingRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	r.notify(object, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *notifyingRecorder) notify(object runtime.Object, reason, message string) {
+	eventType := opentofuv1alpha1.NotificationEvent(reason)
+	if !notifiable[eventType] {
+		return
+	}
+	switch object.(type) {
+	case *opentofuv1alpha1.TofuExecution, *opentofuv1alpha1.TofuModule:
+	default:
+		return
+	}
+	// Copy the object: reconcilers keep modifying theirs while the event waits in the queue.
+	r.dispatcher.Notify(Event{
+		Type:    eventType,
+		Message: message,
+		Time:    time.Now(),
+		Object:  object.DeepCopyObject().(client.Object),
+	})
+}
+
+// matching returns the notifications subscribed to e.
+func (d *Dispatcher) matching(ctx context.Context, e Event) ([]*opentofuv1alpha1.TofuNotification, error) {
+	var list opentofuv1alpha1.TofuNotificationList
+	if err := d.Client.List(ctx, &list); err != nil {
+		return nil, err
+	}
+
+	var namespaceLabels, moduleLabels labels.Set
+	var moduleFound bool
+	var err error
+	loadedNamespace, loadedModule := false, false
+
+	var matched []*opentofuv1alpha1.TofuNotification
+	for i := range list.Items {
+		n := &list.Items[i]
+		if len(n.Spec.Events) > 0 && !slices.Contains(n.Spec.Events, e.Type) {
+			continue
+		}
+		if n.Spec.NamespaceSelector != nil {
+			selector, serr := metav1.LabelSelectorAsSelector(n.Spec.NamespaceSelector)
+			if serr != nil {
+				log.Error(serr, "Skipping notification with an invalid namespace selector", "notification", n.Name)
+				continue
+			}
+			if !loadedNamespace {
+				if namespaceLabels, err = d.namespaceLabels(ctx, e.Object.GetNamespace()); err != nil {
+					return nil, err
+				}
+				loadedNamespace = true
+			}
+			if !selector.Matches(namespaceLabels) {
+				continue
+			}
+		}
+		if n.Spec.ModuleSelector != nil {
+			selector, serr := metav1.LabelSelectorAsSelector(n.Spec.ModuleSelector)
+			if serr != nil {
+				log.Error(serr, "Skipping notification with an invalid module selector", "notification", n.Name)
+				continue
+			}
+			if !loadedModule {
+				if moduleLabels, moduleFound, err = d.moduleLabels(ctx, e.Object); err != nil {
+					return nil, err
+				}
+				loadedModule = true
+			}
+			if !moduleFound || !selector.Matches(moduleLabels) {
+				continue
+			}
+		}
+		matched = append(matched, n)
+	}
+	return matched, nil
+}
+
+func (d *Dispatcher) namespaceLabels(ctx context.Context, name string) (labels.Set, error) {
+	var namespace corev1.Namespace
+	if err := d.Client.Get(ctx, types.NamespacedName{Name: name}, &namespace); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return namespace.Labels, nil
+}
+
+// moduleLabels returns the labels of the module the object is, or the one the execution runs.
+func (d *Dispatcher) moduleLabels(ctx context.Context, object client.Object) (labels.Set, bool, error) {
+	switch o := object.(type) {
+	case *opentofuv1alpha1.TofuModule:
+		return o.Labels, true, nil
+	case *opentofuv1alpha1.TofuExecution:
+		key := types.NamespacedName{Namespace: o.Spec.ModuleRef.Namespace, Name: o.Spec.ModuleRef.Name}
+		if key.Namespace == "" {
+			key.Namespace = o.Namespace
+		}
+		var module opentofuv1alpha1.TofuModule
+		if err := d.Client.Get(ctx, key, &module); err != nil {
+			return nil, false, client.IgnoreNotFound(err)
+		}
+		return module.Labels, true, nil
+	}
+	return nil, false, nil
+}
+
+// deliver posts e to the sink of n and records the outcome in the status of n.
+func (d *Dispatcher) deliver(ctx context.Context, n *opentofuv1alpha1.TofuNotification, e Event) {
+	log := log.WithValues("notification", n.Name, "event", e.Type, "namespace", e.Object.GetNamespace(), "name", e.Object.GetName())
+	patch := client.MergeFrom(n.DeepCopy())
+	now := metav1.Now()
+	if err := d.send(ctx, n, e); err != nil {
+		deadLetters.WithLabelValues(n.Name).Inc()
+		log.Error(err, "Dropping notification")
+		n.Status.LastFailedAt = &now
+		n.Status.LastFailure = err.Error()
+	} else {
+		deliveries.WithLabelValues(n.Name).Inc()
+		n.Status.LastDeliveredAt = &now
+	}
+	if err := d.Client.Status().Patch(ctx, n, patch); err != nil && !apierrors.IsNotFound(err) {
+		log.Error(err, "Unable to update notification status")
+	}
+}
+
+// permanentError is a delivery failure that retrying cannot fix.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+// send renders e and posts it to the sink of n until it is accepted, it fails permanently or
+// the attempts are exhausted.
+func (d *Dispatcher) send(ctx context.Context, n *opentofuv1alpha1.TofuNotification, e Event) error {
+	contentType, body, err := render(n, e)
+	if err != nil {
+		return fmt.Errorf("rendering the notification: %w", err)
+	}
+	sinkURL, err := d.sinkURL(ctx, n.Spec.Sink)
+	if err != nil {
+		return err
+	}
+	backoff := d.Backoff
+	for attempt := 1; ; attempt++ {
+		err := d.post(ctx, sinkURL, contentType, body)
+		var permanent *permanentError
+		if err == nil || errors.As(err, &permanent) {
+			return err
+		}
+		if attempt >= d.Attempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// sinkURL returns the URL of sink, reading it from its Secret when it references one.
+func (d *Dispatcher) sinkURL(ctx context.Context, sink opentofuv1alpha1.NotificationSink) (string, error) {
+	ref := sink.URLSecretRef
+	if ref == nil {
+		return sink.URL, nil
+	}
+	var secret corev1.Secret
+	if err := d.Reader.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+		return "", fmt.Errorf("reading the sink URL: %w", err)
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s has no key %q", ref.Namespace, ref.Name, ref.Key)
+	}
+	return string(bytes.TrimSpace(value)), nil
+}
+
+// post makes a single delivery attempt. Errors leave the URL out: it may carry credentials, and
+// they end up in the status of the notification.
+func (d *Dispatcher) post(ctx context.Context, sinkURL, contentType string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sinkURL, bytes.NewReader(body))
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return &permanentError{fmt.Errorf("invalid sink URL: %w", err)}
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := d.HTTPClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("posting to the sink: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("sink answered %s", resp.Status)
+	default:
+		return &permanentError{fmt.Errorf("sink answered %s", resp.Status)}
+	}
+}
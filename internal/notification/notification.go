@@ -0,0 +1,167 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notification delivers execution and module events to the sinks of TofuNotifications.
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+// notifiable are the Event reasons TofuNotifications can subscribe to.
+var notifiable = map[opentofuv1alpha1.NotificationEvent]bool{
+	"PlanSucceeded":    true,
+	"PlanHasChanges":   true,
+	"PlanFailed":       true,
+	"ApplySucceeded":   true,
+	"ApplyFailed":      true,
+	"TimedOut":         true,
+	"Cancelled":        true,
+	"Rejected":         true,
+	"DriftDetected":    true,
+	"ApprovalRequired": true,
+}
+
+// Event is something that happened to an execution or a module, named after the reason of the
+// Kubernetes Event recorded for it.
+type Event struct {
+	Type    opentofuv1alpha1.NotificationEvent
+	Message string
+	Time    time.Time
+	// Object is the TofuExecution or TofuModule the event is about.
+	Object client.Object
+}
+
+// kind names the kind of the object the event is about.
+func (e Event) kind() string {
+	switch e.Object.(type) {
+	case *opentofuv1alpha1.TofuExecution:
+		return "TofuExecution"
+	case *opentofuv1alpha1.TofuModule:
+		return "TofuModule"
+	}
+	return e.Object.GetObjectKind().GroupVersionKind().Kind
+}
+
+// document is the default Webhook body and CloudEvents data, and what templates are rendered
+// with.
+type document struct {
+	Event     opentofuv1alpha1.NotificationEvent `json:"event"`
+	Message   string                             `json:"message"`
+	Time      time.Time                          `json:"time"`
+	Kind      string                             `json:"kind"`
+	Namespace string                             `json:"namespace"`
+	Name      string                             `json:"name"`
+	Object    client.Object                      `json:"object"`
+}
+
+func newDocument(e Event) document {
+	return document{
+		Event:     e.Type,
+		Message:   e.Message,
+		Time:      e.Time.UTC(),
+		Kind:      e.kind(),
+		Namespace: e.Object.GetNamespace(),
+		Name:      e.Object.GetName(),
+		Object:    e.Object,
+	}
+}
+
+// cloudEvent is a CloudEvent in structured JSON mode.
+type cloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            any       `json:"data"`
+}
+
+// ParseTemplate parses a notification template. Besides the standard functions, templates can
+// call json to render a value as JSON.
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("notification").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(text)
+}
+
+// render formats the event for the sink of n, returning the content type and the body to post.
+func render(n *opentofuv1alpha1.TofuNotification, e Event) (string, []byte, error) {
+	doc := newDocument(e)
+	var rendered []byte
+	if n.Spec.Template != "" {
+		tmpl, err := ParseTemplate(n.Spec.Template)
+		if err != nil {
+			return "", nil, err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, doc); err != nil {
+			return "", nil, err
+		}
+		rendered = buf.Bytes()
+	}
+
+	switch n.Spec.Sink.Type {
+	case opentofuv1alpha1.SinkSlack:
+		text := string(rendered)
+		if rendered == nil {
+			text = fmt.Sprintf("*%s* %s %s/%s: %s", doc.Event, doc.Kind, doc.Namespace, doc.Name, doc.Message)
+		}
+		body, err := json.Marshal(map[string]string{"text": text})
+		return "application/json", body, err
+	case opentofuv1alpha1.SinkCloudEvents:
+		ce := cloudEvent{
+			SpecVersion:     "1.0",
+			ID:              string(uuid.NewUUID()),
+			Source:          fmt.Sprintf("/apis/%s/namespaces/%s/%s", opentofuv1alpha1.GroupVersion.Group, doc.Namespace, doc.Kind),
+			Type:            "io.soyplane.opentofu." + string(doc.Event),
+			Subject:         doc.Name,
+			Time:            doc.Time,
+			DataContentType: "application/json",
+			Data:            doc,
+		}
+		switch {
+		case rendered == nil:
+		case json.Valid(rendered):
+			ce.Data = json.RawMessage(rendered)
+		default:
+			ce.DataContentType = "text/plain"
+			ce.Data = string(rendered)
+		}
+		body, err := json.Marshal(ce)
+		return "application/cloudevents+json", body, err
+	default:
+		if rendered != nil {
+			return "application/json", rendered, nil
+		}
+		body, err := json.Marshal(doc)
+		return "application/json", body, err
+	}
+}
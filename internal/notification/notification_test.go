@@ -0,0 +1,372 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+// sink is a local stand-in for a notification endpoint. It answers with the queued statuses,
+// then with 200 OK.
+type sink struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	requests []request
+}
+
+type request struct {
+	path        string
+	contentType string
+	body        []byte
+}
+
+func newSink(t *testing.T, statuses ...int) *sink {
+	t.Helper()
+	s := &sink{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, request{path: r.URL.Path, contentType: r.Header.Get("Content-Type"), body: body})
+		if len(s.statuses) > 0 {
+			w.WriteHeader(s.statuses[0])
+			s.statuses = s.statuses[1:]
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *sink) received() []request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+func newDispatcher(t *testing.T, objects ...client.Object) *Dispatcher {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := opentofuv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+		WithStatusSubresource(&opentofuv1alpha1.TofuNotification{}).Build()
+	d := NewDispatcher(c, c)
+	d.Backoff = time.Millisecond
+	return d
+}
+
+func notification(name, url string, mutate func(*opentofuv1alpha1.TofuNotificationSpec)) *opentofuv1alpha1.TofuNotification {
+	n := &opentofuv1alpha1.TofuNotification{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: opentofuv1alpha1.TofuNotificationSpec{
+			Sink: opentofuv1alpha1.NotificationSink{Type: opentofuv1alpha1.SinkWebhook, URL: url},
+		},
+	}
+	if mutate != nil {
+		mutate(&n.Spec)
+	}
+	return n
+}
+
+func execution() *opentofuv1alpha1.TofuExecution {
+	return &opentofuv1alpha1.TofuExecution{
+		ObjectMeta: metav1.ObjectMeta{Name: "network-plan", Namespace: "apps"},
+		Spec: opentofuv1alpha1.TofuExecutionSpec{
+			Action:    "plan",
+			ModuleRef: opentofuv1alpha1.ObjectRef{Name: "network"},
+		},
+	}
+}
+
+func TestRecorderNotifiesMatchingNotifications(t *testing.T) {
+	s := newSink(t)
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "apps", Labels: map[string]string{"team": "apps"}}}
+	module := &opentofuv1alpha1.TofuModule{ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "apps", Labels: map[string]string{"layer": "network"}}}
+	d := newDispatcher(t, namespace, module,
+		notification("everything", s.URL+"/everything", nil),
+		notification("drift", s.URL+"/drift", func(spec *opentofuv1alpha1.TofuNotificationSpec) {
+			spec.Events = []opentofuv1alpha1.NotificationEvent{"DriftDetected"}
+		}),
+		notification("team-apps", s.URL+"/team-apps", func(spec *opentofuv1alpha1.TofuNotificationSpec) {
+			spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "apps"}}
+		}),
+		notification("team-platform", s.URL+"/team-platform", func(spec *opentofuv1alpha1.TofuNotificationSpec) {
+			spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "platform"}}
+		}),
+		notification("network", s.URL+"/network", func(spec *opentofuv1alpha1.TofuNotificationSpec) {
+			spec.ModuleSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"layer": "network"}}
+		}),
+		notification("database", s.URL+"/database", func(spec *opentofuv1alpha1.TofuNotificationSpec) {
+			spec.ModuleSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"layer": "database"}}
+		}),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- d.Start(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	recorder := d.Recorder(record.NewFakeRecorder(10))
+	exec := execution()
+	recorder.Eventf(exec, corev1.EventTypeNormal, "Started", "Job %s started", "network-plan-job")
+	recorder.Event(exec, corev1.EventTypeNormal, "PlanHasChanges", "Plan has changes to apply")
+	// The recorder copied the execution: later changes must not leak into the notification.
+	exec.Name = "renamed"
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(s.received()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	var paths []string
+	for _, r := range s.received() {
+		paths = append(paths, r.path)
+		var doc map[string]any
+		if err := json.Unmarshal(r.body, &doc); err != nil {
+			t.Fatal(err)
+		}
+		if doc["event"] != "PlanHasChanges" || doc["name"] != "network-plan" || doc["kind"] != "TofuExecution" || doc["message"] != "Plan has changes to apply" {
+			t.Errorf("unexpected notification %s", r.body)
+		}
+	}
+	slices.Sort(paths)
+	if want := []string{"/everything", "/network", "/team-apps"}; !slices.Equal(paths, want) {
+		t.Errorf("notified %v, want %v", paths, want)
+	}
+
+	deadline = time.Now().Add(5 * time.Second)
+	for {
+		var n opentofuv1alpha1.TofuNotification
+		if err := d.Client.Get(ctx, types.NamespacedName{Name: "everything"}, &n); err != nil {
+			t.Fatal(err)
+		}
+		if n.Status.LastDeliveredAt != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("status of the notification was not updated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDeliverRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []int
+		attempts  int
+		delivered bool
+		failure   string
+	}{
+		{"accepted", nil, 1, true, ""},
+		{"transient failures", []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}, 3, true, ""},
+		{"exhausted", []int{500, 500, 500, 500, 500}, 5, false, "giving up after 5 attempts: sink answered 500 Internal Server Error"},
+		{"permanent failure", []int{http.StatusNotFound}, 1, false, "sink answered 404 Not Found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSink(t, tt.statuses...)
+			name := strings.ReplaceAll(tt.name, " ", "-")
+			n := notification(name, s.URL, nil)
+			d := newDispatcher(t, n)
+			dead := testutil.ToFloat64(deadLetters.WithLabelValues(name))
+
+			d.deliver(context.Background(), n, Event{Type: "ApplyFailed", Message: "Apply failed", Time: time.Now(), Object: execution()})
+
+			if got := len(s.received()); got != tt.attempts {
+				t.Errorf("made %d attempts, want %d", got, tt.attempts)
+			}
+			var got opentofuv1alpha1.TofuNotification
+			if err := d.Client.Get(context.Background(), types.NamespacedName{Name: name}, &got); err != nil {
+				t.Fatal(err)
+			}
+			if (got.Status.LastDeliveredAt != nil) != tt.delivered {
+				t.Errorf("lastDeliveredAt = %v, want delivered %v", got.Status.LastDeliveredAt, tt.delivered)
+			}
+			if got.Status.LastFailure != tt.failure {
+				t.Errorf("lastFailure = %q, want %q", got.Status.LastFailure, tt.failure)
+			}
+			wantDead := dead
+			if !tt.delivered {
+				wantDead++
+			}
+			if got := testutil.ToFloat64(deadLetters.WithLabelValues(name)); got != wantDead {
+				t.Errorf("dead letters = %v, want %v", got, wantDead)
+			}
+		})
+	}
+}
+
+func TestDeliverReadsURLFromSecret(t *testing.T) {
+	s := newSink(t)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "soyplane-system"},
+		Data:       map[string][]byte{"url": []byte(s.URL + "/hooks/secret\n")},
+	}
+	n := notification("slack", "", func(spec *opentofuv1alpha1.TofuNotificationSpec) {
+		spec.Sink.Type = opentofuv1alpha1.SinkSlack
+		spec.Sink.URLSecretRef = &opentofuv1alpha1.NamespacedKeyRef{Namespace: "soyplane-system", Name: "slack", Key: "url"}
+	})
+	d := newDispatcher(t, secret, n)
+
+	d.deliver(context.Background(), n, Event{Type: "PlanFailed", Message: "Plan failed: Init", Time: time.Now(), Object: execution()})
+
+	received := s.received()
+	if len(received) != 1 || received[0].path != "/hooks/secret" {
+		t.Fatalf("received %v", received)
+	}
+	if got, want := string(received[0].body), `{"text":"*PlanFailed* TofuExecution apps/network-plan: Plan failed: Init"}`; got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+
+	n.Spec.Sink.URLSecretRef.Key = "missing"
+	d.deliver(context.Background(), n, Event{Type: "PlanFailed", Time: time.Now(), Object: execution()})
+	if n.Status.LastFailure != `secret soyplane-system/slack has no key "missing"` {
+		t.Errorf("lastFailure = %q", n.Status.LastFailure)
+	}
+}
+
+func TestRender(t *testing.T) {
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	event := Event{Type: "PlanHasChanges", Message: "Plan has changes to apply", Time: at, Object: execution()}
+
+	tests := []struct {
+		name        string
+		sink        opentofuv1alpha1.NotificationSinkType
+		template    string
+		contentType string
+		check       func(t *testing.T, body []byte)
+	}{
+		{
+			name:        "webhook template",
+			sink:        opentofuv1alpha1.SinkWebhook,
+			template:    `{"module":{{ json .Object.Spec.ModuleRef.Name }},"event":"{{ .Event }}"}`,
+			contentType: "application/json",
+			check: func(t *testing.T, body []byte) {
+				if string(body) != `{"module":"network","event":"PlanHasChanges"}` {
+					t.Errorf("body = %s", body)
+				}
+			},
+		},
+		{
+			name:        "slack template",
+			sink:        opentofuv1alpha1.SinkSlack,
+			template:    `{{ .Namespace }}/{{ .Name }} needs a review`,
+			contentType: "application/json",
+			check: func(t *testing.T, body []byte) {
+				if string(body) != `{"text":"apps/network-plan needs a review"}` {
+					t.Errorf("body = %s", body)
+				}
+			},
+		},
+		{
+			name:        "cloudevent",
+			sink:        opentofuv1alpha1.SinkCloudEvents,
+			contentType: "application/cloudevents+json",
+			check: func(t *testing.T, body []byte) {
+				var ce struct {
+					SpecVersion     string         `json:"specversion"`
+					ID              string         `json:"id"`
+					Source          string         `json:"source"`
+					Type            string         `json:"type"`
+					Subject         string         `json:"subject"`
+					Time            time.Time      `json:"time"`
+					DataContentType string         `json:"datacontenttype"`
+					Data            map[string]any `json:"data"`
+				}
+				if err := json.Unmarshal(body, &ce); err != nil {
+					t.Fatal(err)
+				}
+				if ce.SpecVersion != "1.0" || ce.ID == "" || ce.Source != "/apis/opentofu.soyplane.io/namespaces/apps/TofuExecution" ||
+					ce.Type != "io.soyplane.opentofu.PlanHasChanges" || ce.Subject != "network-plan" || !ce.Time.Equal(at) ||
+					ce.DataContentType != "application/json" || ce.Data["message"] != "Plan has changes to apply" {
+					t.Errorf("unexpected cloudevent %s", body)
+				}
+			},
+		},
+		{
+			name:        "cloudevent with text data",
+			sink:        opentofuv1alpha1.SinkCloudEvents,
+			template:    `{{ .Event }}: {{ .Message }}`,
+			contentType: "application/cloudevents+json",
+			check: func(t *testing.T, body []byte) {
+				var ce map[string]any
+				if err := json.Unmarshal(body, &ce); err != nil {
+					t.Fatal(err)
+				}
+				if ce["datacontenttype"] != "text/plain" || ce["data"] != "PlanHasChanges: Plan has changes to apply" {
+					t.Errorf("unexpected cloudevent %s", body)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := notification("render", "", func(spec *opentofuv1alpha1.TofuNotificationSpec) {
+				spec.Sink.Type = tt.sink
+				spec.Template = tt.template
+			})
+			contentType, body, err := render(n, event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if contentType != tt.contentType {
+				t.Errorf("content type = %s, want %s", contentType, tt.contentType)
+			}
+			tt.check(t, body)
+		})
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	if _, err := ParseTemplate(`{{ .Event }`); err == nil {
+		t.Error("expected an unterminated action to be refused")
+	}
+	if _, err := ParseTemplate(`{{ json .Object }}`); err != nil {
+		t.Error(err)
+	}
+}
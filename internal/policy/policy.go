@@ -0,0 +1,133 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy checks plans against TofuPolicy rules written in CEL.
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+// costLimit bounds the work a single rule may do, so that a pathological expression cannot
+// stall an execution.
+const costLimit = 10_000_000
+
+// Policy is the part of a TofuPolicy executions need to check plans. The controller hands
+// policies to executions in this form.
+type Policy struct {
+	Name        string                             `json:"name"`
+	Enforcement opentofuv1alpha1.PolicyEnforcement `json:"enforcement"`
+	Rules       []opentofuv1alpha1.PolicyRule      `json:"rules"`
+}
+
+// FromTofuPolicy converts a TofuPolicy, defaulting its enforcement to Deny.
+func FromTofuPolicy(p *opentofuv1alpha1.TofuPolicy) Policy {
+	enforcement := p.Spec.Enforcement
+	if enforcement == "" {
+		enforcement = opentofuv1alpha1.PolicyDeny
+	}
+	return Policy{Name: p.Name, Enforcement: enforcement, Rules: p.Spec.Rules}
+}
+
+func newEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("plan", cel.DynType),
+		ext.Strings(),
+		ext.Lists(),
+		ext.Sets(),
+	)
+}
+
+// Compile checks that expression is a valid rule expression returning a bool.
+func Compile(expression string) (cel.Program, error) {
+	env, err := newEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("expression must return a bool, not %s", ast.OutputType())
+	}
+	return env.Program(ast, cel.CostLimit(costLimit))
+}
+
+// Evaluate checks planJSON, the output of `tofu show -json`, against every rule of policies.
+// Rules that fail to compile or evaluate are reported with the Error outcome.
+func Evaluate(policies []Policy, planJSON []byte) ([]opentofuv1alpha1.PolicyResult, error) {
+	var plan map[string]any
+	if err := json.Unmarshal(planJSON, &plan); err != nil {
+		return nil, fmt.Errorf("invalid plan JSON: %w", err)
+	}
+
+	var results []opentofuv1alpha1.PolicyResult
+	for _, p := range policies {
+		for _, rule := range p.Rules {
+			result := opentofuv1alpha1.PolicyResult{
+				Policy:      p.Name,
+				Rule:        rule.Name,
+				Enforcement: p.Enforcement,
+				Outcome:     opentofuv1alpha1.PolicyPass,
+			}
+			passed, err := evaluateRule(rule.Expression, plan)
+			switch {
+			case err != nil:
+				result.Outcome = opentofuv1alpha1.PolicyError
+				result.Message = err.Error()
+			case !passed:
+				result.Outcome = opentofuv1alpha1.PolicyFail
+				result.Message = rule.Message
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+func evaluateRule(expression string, plan map[string]any) (bool, error) {
+	program, err := Compile(expression)
+	if err != nil {
+		return false, err
+	}
+	out, _, err := program.Eval(map[string]any{"plan": plan})
+	if err != nil {
+		return false, err
+	}
+	passed, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression returned %v instead of a bool", out.Value())
+	}
+	return passed, nil
+}
+
+// Denied reports whether results contain a violation of a Deny policy. Rules that could not be
+// evaluated count as violations.
+func Denied(results []opentofuv1alpha1.PolicyResult) bool {
+	for _, result := range results {
+		if result.Enforcement == opentofuv1alpha1.PolicyDeny && result.Outcome != opentofuv1alpha1.PolicyPass {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package policy
+
+import (
+	"testing"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+const planJSON = `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {
+      "address": "aws_db_instance.main",
+      "type": "aws_db_instance",
+      "change": {"actions": ["delete", "create"], "before": {"identifier": "main"}, "after": {"identifier": "main", "tags": {"owner": "data"}}}
+    },
+    {
+      "address": "aws_s3_bucket.logs",
+      "type": "aws_s3_bucket",
+      "change": {"actions": ["create"], "before": null, "after": {"bucket": "logs", "tags": {}}}
+    }
+  ]
+}`
+
+func TestEvaluate(t *testing.T) {
+	policies := []Policy{
+		{
+			Name:        "guardrails",
+			Enforcement: opentofuv1alpha1.PolicyDeny,
+			Rules: []opentofuv1alpha1.PolicyRule{
+				{
+					Name:       "no-database-deletes",
+					Expression: `plan.resource_changes.all(rc, rc.type != "aws_db_instance" || !("delete" in rc.change.actions))`,
+					Message:    "Database instances must not be deleted",
+				},
+				{
+					Name:       "at-most-five-changes",
+					Expression: `size(plan.resource_changes) <= 5`,
+				},
+			},
+		},
+		{
+			Name:        "tagging",
+			Enforcement: opentofuv1alpha1.PolicyWarn,
+			Rules: []opentofuv1alpha1.PolicyRule{{
+				Name:       "owner-tag",
+				Expression: `plan.resource_changes.filter(rc, "create" in rc.change.actions).all(rc, "owner" in rc.change.after.tags)`,
+				Message:    "New resources need an owner tag",
+			}},
+		},
+	}
+
+	results, err := Evaluate(policies, []byte(planJSON))
+	if err != nil {
+		t.Fatalf("Evaluate returned error: %v", err)
+	}
+	outcomes := map[string]opentofuv1alpha1.PolicyOutcome{}
+	for _, result := range results {
+		outcomes[result.Policy+"/"+result.Rule] = result.Outcome
+	}
+	expected := map[string]opentofuv1alpha1.PolicyOutcome{
+		"guardrails/no-database-deletes":  opentofuv1alpha1.PolicyFail,
+		"guardrails/at-most-five-changes": opentofuv1alpha1.PolicyPass,
+		"tagging/owner-tag":               opentofuv1alpha1.PolicyFail,
+	}
+	for key, outcome := range expected {
+		if outcomes[key] != outcome {
+			t.Fatalf("expected %s to be %s, got %s", key, outcome, outcomes[key])
+		}
+	}
+	if results[0].Message != "Database instances must not be deleted" {
+		t.Fatalf("expected the rule message on violations, got %q", results[0].Message)
+	}
+	if !Denied(results) {
+		t.Fatal("expected the Deny violation to deny the apply")
+	}
+	if Denied(results[1:]) {
+		t.Fatal("Warn violations must not deny the apply")
+	}
+}
+
+func TestEvaluateReportsBrokenRules(t *testing.T) {
+	policies := []Policy{{
+		Name:        "broken",
+		Enforcement: opentofuv1alpha1.PolicyDeny,
+		Rules: []opentofuv1alpha1.PolicyRule{
+			{Name: "syntax", Expression: `plan.resource_changes.all(rc,`},
+			{Name: "not-bool", Expression: `size(plan.resource_changes)`},
+			{Name: "missing-field", Expression: `plan.variables.region.value == "eu-west-1"`},
+		},
+	}}
+	results, err := Evaluate(policies, []byte(planJSON))
+	if err != nil {
+		t.Fatalf("Evaluate returned error: %v", err)
+	}
+	for _, result := range results {
+		if result.Outcome != opentofuv1alpha1.PolicyError || result.Message == "" {
+			t.Fatalf("expected %s to report an error, got %+v", result.Rule, result)
+		}
+	}
+	if !Denied(results) {
+		t.Fatal("broken Deny rules must deny the apply")
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package providermirror serves OpenTofu/Terraform providers from a local directory using the
+// provider network mirror protocol, so that executions do not download providers from their
+// origin registries.
+package providermirror
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// segmentPattern matches the hostname, namespace and type segments of provider addresses.
+var segmentPattern = regexp.MustCompile(`^[0-9A-Za-z][0-9A-Za-z._-]*$`)
+
+// archivePattern matches provider packages as laid out by `tofu providers mirror`:
+// terraform-provider-<type>_<version>_<os>_<arch>.zip.
+var archivePattern = regexp.MustCompile(`^terraform-provider-([0-9A-Za-z-]+)_([0-9][0-9A-Za-z.+-]*)_([0-9a-z]+)_([0-9a-z]+)\.zip$`)
+
+type archive struct {
+	version  string
+	platform string
+	name     string
+}
+
+type versionsResponse struct {
+	Versions map[string]struct{} `json:"versions"`
+}
+
+type archiveResponse struct {
+	URL    string   `json:"url"`
+	Hashes []string `json:"hashes,omitempty"`
+}
+
+type archivesResponse struct {
+	Archives map[string]archiveResponse `json:"archives"`
+}
+
+type cachedHash struct {
+	size    int64
+	modTime time.Time
+	hash    string
+}
+
+// Handler serves the providers stored under Dir. Packages live in
+// <dir>/<hostname>/<namespace>/<type>/, the packed layout written by `tofu providers mirror`,
+// so a mirror can be filled by running that command against the directory or by copying
+// release archives. Indexes are computed from the archives present, so files can be added
+// while the mirror is serving.
+type Handler struct {
+	Dir string
+
+	mu     sync.Mutex
+	hashes map[string]cachedHash
+}
+
+// NewHandler returns a Handler serving the providers stored under dir.
+func NewHandler(dir string) *Handler {
+	return &Handler{Dir: dir, hashes: make(map[string]cachedHash)}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(segments) != 4 {
+		http.NotFound(w, req)
+		return
+	}
+	for _, segment := range segments[:3] {
+		if !segmentPattern.MatchString(segment) {
+			http.NotFound(w, req)
+			return
+		}
+	}
+	providerDir := filepath.Join(h.Dir, segments[0], segments[1], segments[2])
+	file := segments[3]
+
+	switch {
+	case file == "index.json":
+		h.serveVersions(w, req, providerDir, segments[2])
+	case strings.HasSuffix(file, ".json"):
+		h.serveArchives(w, req, providerDir, segments[2], strings.TrimSuffix(file, ".json"))
+	case archivePattern.MatchString(file):
+		http.ServeFile(w, req, filepath.Join(providerDir, file))
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+// serveVersions answers the "list available versions" request of the mirror protocol.
+func (h *Handler) serveVersions(w http.ResponseWriter, req *http.Request, providerDir, providerType string) {
+	archives, err := listArchives(providerDir, providerType)
+	if err != nil {
+		writeError(w, req, err)
+		return
+	}
+	response := versionsResponse{Versions: make(map[string]struct{})}
+	for _, a := range archives {
+		response.Versions[a.version] = struct{}{}
+	}
+	writeJSON(w, response)
+}
+
+// serveArchives answers the "list available installation packages" request of the mirror protocol.
+func (h *Handler) serveArchives(w http.ResponseWriter, req *http.Request, providerDir, providerType, version string) {
+	archives, err := listArchives(providerDir, providerType)
+	if err != nil {
+		writeError(w, req, err)
+		return
+	}
+	response := archivesResponse{Archives: make(map[string]archiveResponse)}
+	for _, a := range archives {
+		if a.version != version {
+			continue
+		}
+		hash, err := h.zipHash(filepath.Join(providerDir, a.name))
+		if err != nil {
+			writeError(w, req, err)
+			return
+		}
+		// Relative URLs resolve against this document, i.e. to the archive next to it.
+		response.Archives[a.platform] = archiveResponse{URL: a.name, Hashes: []string{hash}}
+	}
+	if len(response.Archives) == 0 {
+		http.NotFound(w, req)
+		return
+	}
+	writeJSON(w, response)
+}
+
+// listArchives returns the packages of providerType found in providerDir.
+func listArchives(providerDir, providerType string) ([]archive, error) {
+	entries, err := os.ReadDir(providerDir)
+	if err != nil {
+		return nil, err
+	}
+	var archives []archive
+	for _, entry := range entries {
+		match := archivePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil || match[1] != providerType {
+			continue
+		}
+		archives = append(archives, archive{version: match[2], platform: match[3] + "_" + match[4], name: entry.Name()})
+	}
+	if len(archives) == 0 {
+		return nil, os.ErrNotExist
+	}
+	return archives, nil
+}
+
+// zipHash returns the "zh:" hash of the archive at name, the SHA-256 of the zip file, which
+// engines record in their dependency lock files. Hashes are cached until the file changes.
+func (h *Handler) zipHash(name string) (string, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return "", err
+	}
+	h.mu.Lock()
+	cached, ok := h.hashes[name]
+	h.mu.Unlock()
+	if ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached.hash, nil
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close() //nolint:errcheck
+	digest := sha256.New()
+	if _, err := io.Copy(digest, f); err != nil {
+		return "", err
+	}
+	hash := "zh:" + hex.EncodeToString(digest.Sum(nil))
+
+	h.mu.Lock()
+	h.hashes[name] = cachedHash{size: info.Size(), modTime: info.ModTime(), hash: hash}
+	h.mu.Unlock()
+	return hash, nil
+}
+
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeError(w http.ResponseWriter, req *http.Request, err error) {
+	if os.IsNotExist(err) {
+		http.NotFound(w, req)
+		return
+	}
+	http.Error(w, fmt.Sprintf("reading %s: %v", path.Clean(req.URL.Path), err), http.StatusInternalServerError)
+}
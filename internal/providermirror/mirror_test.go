@@ -0,0 +1,102 @@
+package providermirror
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHandlerServesFilesystemMirror(t *testing.T) {
+	dir := t.TempDir()
+	providerDir := filepath.Join(dir, "registry.opentofu.org", "hashicorp", "random")
+	if err := os.MkdirAll(providerDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	archive := []byte("not really a zip")
+	for _, name := range []string{
+		"terraform-provider-random_3.6.0_linux_amd64.zip",
+		"terraform-provider-random_3.6.0_linux_arm64.zip",
+		"terraform-provider-random_3.5.1_linux_amd64.zip",
+		"index.json",
+	} {
+		if err := os.WriteFile(filepath.Join(providerDir, name), archive, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	server := httptest.NewServer(NewHandler(dir))
+	t.Cleanup(server.Close)
+	base := server.URL + "/registry.opentofu.org/hashicorp/random/"
+
+	var versions versionsResponse
+	getJSON(t, base+"index.json", &versions)
+	if len(versions.Versions) != 2 {
+		t.Fatalf("expected versions 3.6.0 and 3.5.1, got %v", versions.Versions)
+	}
+	if _, ok := versions.Versions["3.5.1"]; !ok {
+		t.Fatalf("expected version 3.5.1, got %v", versions.Versions)
+	}
+
+	var archives archivesResponse
+	getJSON(t, base+"3.6.0.json", &archives)
+	if len(archives.Archives) != 2 {
+		t.Fatalf("expected two platforms, got %v", archives.Archives)
+	}
+	amd64 := archives.Archives["linux_amd64"]
+	sum := sha256.Sum256(archive)
+	if amd64.URL != "terraform-provider-random_3.6.0_linux_amd64.zip" || len(amd64.Hashes) != 1 || amd64.Hashes[0] != "zh:"+hex.EncodeToString(sum[:]) {
+		t.Fatalf("unexpected archive %+v", amd64)
+	}
+
+	resp, err := http.Get(base + amd64.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != string(archive) {
+		t.Fatalf("unexpected archive download: %d %q", resp.StatusCode, body)
+	}
+}
+
+func TestHandlerRejectsUnknownPaths(t *testing.T) {
+	server := httptest.NewServer(NewHandler(t.TempDir()))
+	t.Cleanup(server.Close)
+
+	for _, path := range []string{
+		"/registry.opentofu.org/hashicorp/random/index.json",
+		"/registry.opentofu.org/hashicorp/random/1.0.0.json",
+		"/registry.opentofu.org/hashicorp/random/secrets.txt",
+		"/registry.opentofu.org/../random/index.json",
+		"/index.json",
+	} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected 404 for %s, got %d", path, resp.StatusCode)
+		}
+	}
+}
+
+func getJSON(t *testing.T, url string, into any) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s returned %d", url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(into); err != nil {
+		t.Fatal(err)
+	}
+}
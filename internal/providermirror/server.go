@@ -0,0 +1,76 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providermirror
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Server serves a Handler over HTTPS, which engines require of network mirrors. It implements
+// controller-runtime's Runnable and runs on every manager replica, not only on the leader.
+type Server struct {
+	BindAddress string
+	Handler     http.Handler
+	// TLSOpts configure the server certificate, typically through a certificate watcher.
+	TLSOpts []func(*tls.Config)
+}
+
+// Start serves until ctx is cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("provider-mirror")
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	for _, opt := range s.TLSOpts {
+		opt(cfg)
+	}
+	listener, err := tls.Listen("tcp", s.BindAddress, cfg)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler:           s.Handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Error(err, "Failed to shut down provider mirror")
+		}
+	}()
+
+	log.Info("Serving provider mirror", "address", s.BindAddress)
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection lets every replica serve the mirror.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
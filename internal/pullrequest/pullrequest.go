@@ -0,0 +1,259 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pullrequest comments on the pull requests of repositories hosted on GitHub, GitLab and
+// Gitea.
+package pullrequest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+const (
+	// requestTimeout bounds a single API request.
+	requestTimeout = 10 * time.Second
+	// pageSize is the number of items requested per page of list endpoints.
+	pageSize = 50
+	// maxPages bounds how far lists are paged through.
+	maxPages = 20
+)
+
+var (
+	// scpLikeSource matches the scp-like syntax of ssh remotes, e.g. git@github.com:org/repo.git.
+	scpLikeSource = regexp.MustCompile(`^[A-Za-z0-9._-]+@([A-Za-z0-9.-]+):(.+)$`)
+	// pullRequestRef matches the refs GitHub and Gitea expose pull requests under.
+	pullRequestRef = regexp.MustCompile(`^refs/pull/([0-9]+)/(head|merge)$`)
+	// mergeRequestRef matches the refs GitLab exposes merge requests under.
+	mergeRequestRef = regexp.MustCompile(`^refs/merge-requests/([0-9]+)/(head|merge)$`)
+)
+
+// Client comments on the pull requests of a repository.
+type Client struct {
+	Provider opentofuv1alpha1.GitProvider
+	// APIURL is the API endpoint of the provider, without a trailing slash.
+	APIURL string
+	// Repository is owner/name on GitHub and Gitea, and the project path on GitLab.
+	Repository string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client for the repository modules are cloned from with source.
+func NewClient(spec opentofuv1alpha1.PullRequestCommentSpec, source, token string) (*Client, error) {
+	host, repository, err := parseSource(source)
+	if err != nil {
+		return nil, err
+	}
+	apiURL := spec.APIURL
+	if apiURL == "" {
+		switch {
+		case spec.Provider == opentofuv1alpha1.GitProviderGitHub && host == "github.com":
+			apiURL = "https://api.github.com"
+		case spec.Provider == opentofuv1alpha1.GitProviderGitHub:
+			apiURL = "https://" + host + "/api/v3"
+		case spec.Provider == opentofuv1alpha1.GitProviderGitLab:
+			apiURL = "https://" + host + "/api/v4"
+		case spec.Provider == opentofuv1alpha1.GitProviderGitea:
+			apiURL = "https://" + host + "/api/v1"
+		default:
+			return nil, fmt.Errorf("unsupported provider %q", spec.Provider)
+		}
+	}
+	return &Client{
+		Provider:   spec.Provider,
+		APIURL:     strings.TrimSuffix(apiURL, "/"),
+		Repository: repository,
+		Token:      token,
+		HTTPClient: http.DefaultClient,
+	}, nil
+}
+
+// parseSource returns the host and the repository path of a git remote.
+func parseSource(source string) (string, string, error) {
+	var host, path string
+	if m := scpLikeSource.FindStringSubmatch(source); m != nil {
+		host, path = m[1], m[2]
+	} else {
+		u, err := url.Parse(source)
+		if err != nil {
+			return "", "", err
+		}
+		host, path = u.Hostname(), u.Path
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || !strings.Contains(path, "/") {
+		return "", "", fmt.Errorf("source %q does not name a host and a repository", source)
+	}
+	return host, path, nil
+}
+
+// Number returns the number of the pull request ref belongs to. Pull request refs carry the
+// number; any other ref is taken as a branch, whose open pull request is looked up. It reports
+// false when the branch has no open pull request.
+func (c *Client) Number(ctx context.Context, ref string) (int, bool, error) {
+	refPattern := pullRequestRef
+	if c.Provider == opentofuv1alpha1.GitProviderGitLab {
+		refPattern = mergeRequestRef
+	}
+	if m := refPattern.FindStringSubmatch(ref); m != nil {
+		number, err := strconv.Atoi(m[1])
+		return number, err == nil, err
+	}
+	branch := strings.TrimPrefix(ref, "refs/heads/")
+
+	switch c.Provider {
+	case opentofuv1alpha1.GitProviderGitLab:
+		var requests []struct {
+			IID int `json:"iid"`
+		}
+		path := fmt.Sprintf("/projects/%s/merge_requests?state=opened&source_branch=%s", url.PathEscape(c.Repository), url.QueryEscape(branch))
+		if err := c.do(ctx, http.MethodGet, path, nil, &requests); err != nil || len(requests) == 0 {
+			return 0, false, err
+		}
+		return requests[0].IID, true, nil
+	case opentofuv1alpha1.GitProviderGitHub:
+		owner, _, _ := strings.Cut(c.Repository, "/")
+		var pulls []struct {
+			Number int `json:"number"`
+		}
+		path := fmt.Sprintf("/repos/%s/pulls?state=open&head=%s", c.Repository, url.QueryEscape(owner+":"+branch))
+		if err := c.do(ctx, http.MethodGet, path, nil, &pulls); err != nil || len(pulls) == 0 {
+			return 0, false, err
+		}
+		return pulls[0].Number, true, nil
+	default:
+		// Gitea cannot filter pull requests by branch.
+		for page := 1; page <= maxPages; page++ {
+			var pulls []struct {
+				Number int `json:"number"`
+				Head   struct {
+					Ref string `json:"ref"`
+				} `json:"head"`
+			}
+			path := fmt.Sprintf("/repos/%s/pulls?state=open&limit=%d&page=%d", c.Repository, pageSize, page)
+			if err := c.do(ctx, http.MethodGet, path, nil, &pulls); err != nil {
+				return 0, false, err
+			}
+			for _, pull := range pulls {
+				if pull.Head.Ref == branch {
+					return pull.Number, true, nil
+				}
+			}
+			if len(pulls) < pageSize {
+				break
+			}
+		}
+		return 0, false, nil
+	}
+}
+
+// comment is a pull request comment, or a merge request note on GitLab.
+type comment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// Comment posts body on pull request number, preceded by marker. A comment already carrying
+// marker is updated instead, so that every plan refreshes a single comment.
+func (c *Client) Comment(ctx context.Context, number int, marker, body string) error {
+	commentsPath := fmt.Sprintf("/repos/%s/issues/%d/comments", c.Repository, number)
+	if c.Provider == opentofuv1alpha1.GitProviderGitLab {
+		commentsPath = fmt.Sprintf("/projects/%s/merge_requests/%d/notes", url.PathEscape(c.Repository), number)
+	}
+	existing, err := c.findComment(ctx, commentsPath, marker)
+	if err != nil {
+		return err
+	}
+	payload := map[string]string{"body": marker + "\n" + body}
+	switch {
+	case existing == nil:
+		return c.do(ctx, http.MethodPost, commentsPath, payload, nil)
+	case c.Provider == opentofuv1alpha1.GitProviderGitLab:
+		return c.do(ctx, http.MethodPut, fmt.Sprintf("%s/%d", commentsPath, existing.ID), payload, nil)
+	default:
+		return c.do(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/issues/comments/%d", c.Repository, existing.ID), payload, nil)
+	}
+}
+
+// findComment pages through the comments at path for the first one carrying marker.
+func (c *Client) findComment(ctx context.Context, path, marker string) (*comment, error) {
+	for page := 1; page <= maxPages; page++ {
+		var comments []comment
+		if err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s?per_page=%d&limit=%d&page=%d", path, pageSize, pageSize, page), nil, &comments); err != nil {
+			return nil, err
+		}
+		for i := range comments {
+			if strings.Contains(comments[i].Body, marker) {
+				return &comments[i], nil
+			}
+		}
+		if len(comments) < pageSize {
+			break
+		}
+	}
+	return nil, nil
+}
+
+// do sends a request to the API, encoding in as the JSON body and decoding the answer into out.
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.APIURL+path, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch c.Provider {
+	case opentofuv1alpha1.GitProviderGitLab:
+		req.Header.Set("PRIVATE-TOKEN", c.Token)
+	case opentofuv1alpha1.GitProviderGitea:
+		req.Header.Set("Authorization", "token "+c.Token)
+	default:
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s answered %s", method, strings.SplitN(path, "?", 2)[0], resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pullrequest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+// forge is a local stand-in for the comment API of a provider. It keeps the comments of a single
+// pull request.
+type forge struct {
+	*httptest.Server
+	provider opentofuv1alpha1.GitProvider
+
+	mu       sync.Mutex
+	comments []comment
+	nextID   int64
+	requests []string
+}
+
+func newForge(t *testing.T, provider opentofuv1alpha1.GitProvider, pulls string) *forge {
+	t.Helper()
+	f := &forge{provider: provider, nextID: 1}
+	// Comments preceding ours must be skipped.
+	f.comments = append(f.comments, comment{ID: 100, Body: "LGTM"})
+
+	mux := http.NewServeMux()
+	commentsPath, commentPath := "/repos/org/infra/issues/42/comments", "PATCH /repos/org/infra/issues/comments/{id}"
+	if provider == opentofuv1alpha1.GitProviderGitLab {
+		commentsPath, commentPath = "/projects/group%2Finfra/merge_requests/42/notes", "PUT /projects/group%2Finfra/merge_requests/42/notes/{id}"
+	}
+	mux.HandleFunc("GET "+commentsPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(f.comments)
+	})
+	mux.HandleFunc("POST "+commentsPath, func(w http.ResponseWriter, r *http.Request) {
+		var c comment
+		_ = json.NewDecoder(r.Body).Decode(&c)
+		c.ID = f.nextID
+		f.nextID++
+		f.comments = append(f.comments, c)
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc(commentPath, func(w http.ResponseWriter, r *http.Request) {
+		var c comment
+		_ = json.NewDecoder(r.Body).Decode(&c)
+		for i := range f.comments {
+			if fmt.Sprint(f.comments[i].ID) == r.PathValue("id") {
+				f.comments[i].Body = c.Body
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, pulls)
+	})
+
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if !f.authorized(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		f.requests = append(f.requests, r.Method+" "+r.URL.RequestURI())
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *forge) authorized(r *http.Request) bool {
+	switch f.provider {
+	case opentofuv1alpha1.GitProviderGitLab:
+		return r.Header.Get("PRIVATE-TOKEN") == "secret"
+	case opentofuv1alpha1.GitProviderGitea:
+		return r.Header.Get("Authorization") == "token secret"
+	default:
+		return r.Header.Get("Authorization") == "Bearer secret"
+	}
+}
+
+func (f *forge) client(t *testing.T) *Client {
+	t.Helper()
+	source := "https://git.example.com/org/infra.git"
+	if f.provider == opentofuv1alpha1.GitProviderGitLab {
+		source = "git@git.example.com:group/infra.git"
+	}
+	c, err := NewClient(opentofuv1alpha1.PullRequestCommentSpec{Provider: f.provider, APIURL: f.URL + "/"}, source, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCommentIsCreatedThenUpdated(t *testing.T) {
+	for _, provider := range []opentofuv1alpha1.GitProvider{
+		opentofuv1alpha1.GitProviderGitHub, opentofuv1alpha1.GitProviderGitLab, opentofuv1alpha1.GitProviderGitea,
+	} {
+		t.Run(string(provider), func(t *testing.T) {
+			f := newForge(t, provider, "[]")
+			c := f.client(t)
+			ctx := context.Background()
+			marker := "<!-- soyplane:apps/network -->"
+
+			if err := c.Comment(ctx, 42, marker, "Plan: 1 to add"); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.Comment(ctx, 42, marker, "Plan: no changes"); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.Comment(ctx, 42, "<!-- soyplane:apps/database -->", "Plan: 2 to destroy"); err != nil {
+				t.Fatal(err)
+			}
+
+			want := []comment{
+				{ID: 100, Body: "LGTM"},
+				{ID: 1, Body: marker + "\nPlan: no changes"},
+				{ID: 2, Body: "<!-- soyplane:apps/database -->\nPlan: 2 to destroy"},
+			}
+			if fmt.Sprint(f.comments) != fmt.Sprint(want) {
+				t.Errorf("comments = %v, want %v", f.comments, want)
+			}
+		})
+	}
+}
+
+func TestCommentReportsFailures(t *testing.T) {
+	f := newForge(t, opentofuv1alpha1.GitProviderGitHub, "[]")
+	c := f.client(t)
+	c.Token = "wrong"
+	err := c.Comment(context.Background(), 42, "<!-- soyplane:apps/network -->", "Plan: 1 to add")
+	if err == nil || err.Error() != "GET /repos/org/infra/issues/42/comments answered 401 Unauthorized" {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider opentofuv1alpha1.GitProvider
+		pulls    string
+		ref      string
+		want     int
+		found    bool
+		request  string
+	}{
+		{"github pull request ref", opentofuv1alpha1.GitProviderGitHub, "", "refs/pull/7/head", 7, true, ""},
+		{"gitlab merge request ref", opentofuv1alpha1.GitProviderGitLab, "", "refs/merge-requests/8/head", 8, true, ""},
+		{"github branch", opentofuv1alpha1.GitProviderGitHub, `[{"number": 12}]`, "feature/vpc", 12, true,
+			"GET /repos/org/infra/pulls?state=open&head=org%3Afeature%2Fvpc"},
+		{"github branch without pull request", opentofuv1alpha1.GitProviderGitHub, `[]`, "refs/heads/main", 0, false,
+			"GET /repos/org/infra/pulls?state=open&head=org%3Amain"},
+		{"gitlab branch", opentofuv1alpha1.GitProviderGitLab, `[{"iid": 3}]`, "feature", 3, true,
+			"GET /projects/group%2Finfra/merge_requests?state=opened&source_branch=feature"},
+		{"gitea branch", opentofuv1alpha1.GitProviderGitea, `[{"number": 4, "head": {"ref": "main"}}, {"number": 5, "head": {"ref": "feature"}}]`, "feature", 5, true,
+			"GET /repos/org/infra/pulls?state=open&limit=50&page=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newForge(t, tt.provider, tt.pulls)
+			got, found, err := f.client(t).Number(context.Background(), tt.ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || found != tt.found {
+				t.Errorf("Number(%q) = %d, %v, want %d, %v", tt.ref, got, found, tt.want, tt.found)
+			}
+			if requests := strings.Join(f.requests, "\n"); requests != tt.request {
+				t.Errorf("requests = %q, want %q", requests, tt.request)
+			}
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		provider   opentofuv1alpha1.GitProvider
+		source     string
+		apiURL     string
+		repository string
+	}{
+		{opentofuv1alpha1.GitProviderGitHub, "https://github.com/org/infra.git", "https://api.github.com", "org/infra"},
+		{opentofuv1alpha1.GitProviderGitHub, "git@github.example.com:org/infra.git", "https://github.example.com/api/v3", "org/infra"},
+		{opentofuv1alpha1.GitProviderGitLab, "https://gitlab.com/group/sub/infra", "https://gitlab.com/api/v4", "group/sub/infra"},
+		{opentofuv1alpha1.GitProviderGitea, "ssh://git@gitea.example.com:2222/org/infra.git", "https://gitea.example.com/api/v1", "org/infra"},
+	}
+	for _, tt := range tests {
+		c, err := NewClient(opentofuv1alpha1.PullRequestCommentSpec{Provider: tt.provider}, tt.source, "secret")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.APIURL != tt.apiURL || c.Repository != tt.repository {
+			t.Errorf("NewClient(%s, %s) = %s %s, want %s %s", tt.provider, tt.source, c.APIURL, c.Repository, tt.apiURL, tt.repository)
+		}
+	}
+
+	if _, err := NewClient(opentofuv1alpha1.PullRequestCommentSpec{Provider: opentofuv1alpha1.GitProviderGitHub}, "file:///srv/infra", ""); err == nil {
+		t.Error("expected a source without a host to be refused")
+	}
+}
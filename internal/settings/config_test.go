@@ -50,6 +50,151 @@ func TestInitLoadsConfiguration(t *testing.T) {
 	}
 }
 
+func TestExecutionConcurrencyLimits(t *testing.T) {
+	t.Cleanup(reset)
+	reset()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	writeFile(t, cfgPath, "test: true\nexecution:\n  maxConcurrent: 10\n  maxConcurrentPerNamespace: 3\n  namespaceMaxConcurrent:\n    platform: 5\n")
+
+	if err := Init([]string{cfgPath}, false); err != nil {
+		t.Fatalf("Init returned error: %v", err)
+	}
+
+	cfg, err := Execution()
+	if err != nil {
+		t.Fatalf("Execution returned error: %v", err)
+	}
+
+	if cfg.MaxConcurrent != 10 {
+		t.Fatalf("expected maxConcurrent to be 10, got %d", cfg.MaxConcurrent)
+	}
+	if got := cfg.NamespaceLimit("platform"); got != 5 {
+		t.Fatalf("expected platform limit to be 5, got %d", got)
+	}
+	if got := cfg.NamespaceLimit("team-a"); got != 3 {
+		t.Fatalf("expected default namespace limit to be 3, got %d", got)
+	}
+}
+
+func TestExecutionHistoryLimitDefaults(t *testing.T) {
+	t.Cleanup(reset)
+	reset()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	writeFile(t, cfgPath, "test: true\nexecution:\n  failedHistoryLimit: 2\n")
+
+	if err := Init([]string{cfgPath}, false); err != nil {
+		t.Fatalf("Init returned error: %v", err)
+	}
+
+	cfg, err := Execution()
+	if err != nil {
+		t.Fatalf("Execution returned error: %v", err)
+	}
+
+	if cfg.SuccessfulHistoryLimit != 10 {
+		t.Fatalf("expected successfulHistoryLimit to default to 10, got %d", cfg.SuccessfulHistoryLimit)
+	}
+	if cfg.FailedHistoryLimit != 2 {
+		t.Fatalf("expected failedHistoryLimit to be 2, got %d", cfg.FailedHistoryLimit)
+	}
+}
+
+func TestPodSecurityDefaults(t *testing.T) {
+	t.Cleanup(reset)
+	reset()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	writeFile(t, cfgPath, "test: true\nexecution:\n  podSecurity:\n    runAsUser: 1000\n")
+
+	if err := Init([]string{cfgPath}, false); err != nil {
+		t.Fatalf("Init returned error: %v", err)
+	}
+
+	cfg, err := Execution()
+	if err != nil {
+		t.Fatalf("Execution returned error: %v", err)
+	}
+
+	if cfg.PodSecurity.Profile != PodSecurityRestricted {
+		t.Fatalf("expected podSecurity.profile to default to %q, got %q", PodSecurityRestricted, cfg.PodSecurity.Profile)
+	}
+	if cfg.PodSecurity.RunAsUser != 1000 {
+		t.Fatalf("expected podSecurity.runAsUser to be 1000, got %d", cfg.PodSecurity.RunAsUser)
+	}
+	if cfg.PodSecurity.RunAsGroup != 65532 {
+		t.Fatalf("expected podSecurity.runAsGroup to default to 65532, got %d", cfg.PodSecurity.RunAsGroup)
+	}
+}
+
+func TestUnknownPodSecurityProfileRejected(t *testing.T) {
+	t.Cleanup(reset)
+	reset()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	writeFile(t, cfgPath, "test: true\nexecution:\n  podSecurity:\n    profile: privileged\n")
+
+	err := Init([]string{cfgPath}, false)
+	var cfgErr ConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("expected ConfigError, got %v", err)
+	}
+	if cfgErr.Field != "SoyplaneSettings.Execution.PodSecurity.Profile" {
+		t.Fatalf("unexpected field %q", cfgErr.Field)
+	}
+}
+
+func TestEngineImageRulesValidated(t *testing.T) {
+	t.Cleanup(reset)
+	reset()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	writeFile(t, cfgPath, "test: true\nexecution:\n  engineImages:\n  - engine: tofu\n    versions: \">= 1.6, < 1.8\"\n    image: mirror.internal/opentofu:{{ .Version }}\n")
+
+	if err := Init([]string{cfgPath}, false); err != nil {
+		t.Fatalf("Init returned error: %v", err)
+	}
+	cfg, err := Execution()
+	if err != nil {
+		t.Fatalf("Execution returned error: %v", err)
+	}
+	if len(cfg.EngineImages) != 1 || cfg.EngineImages[0].Versions != ">= 1.6, < 1.8" {
+		t.Fatalf("unexpected engine images %+v", cfg.EngineImages)
+	}
+
+	reset()
+	writeFile(t, cfgPath, "test: true\nexecution:\n  engineImages:\n  - engine: tofu\n    versions: \"about 1.6\"\n    image: mirror.internal/opentofu\n")
+	err = Init([]string{cfgPath}, false)
+	var cfgErr ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Tag != "versionconstraint" {
+		t.Fatalf("expected versionconstraint ConfigError, got %v", err)
+	}
+}
+
+func TestNegativeConcurrencyLimitRejected(t *testing.T) {
+	t.Cleanup(reset)
+	reset()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	writeFile(t, cfgPath, "test: true\nexecution:\n  maxConcurrent: -1\n")
+
+	err := Init([]string{cfgPath}, false)
+	var cfgErr ConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("expected ConfigError, got %v", err)
+	}
+	if cfgErr.Field != "SoyplaneSettings.Execution.MaxConcurrent" {
+		t.Fatalf("expected validation error on MaxConcurrent, got %s", cfgErr.Field)
+	}
+}
+
 func TestLoadPreservesPreviousSnapshotOnFailure(t *testing.T) {
 	t.Cleanup(reset)
 	reset()
@@ -157,3 +302,106 @@ func (p watchErrorProvider) Read() (map[string]interface{}, error) {
 func (p watchErrorProvider) Watch(func(any, error)) error {
 	return errors.New("watch error")
 }
+
+func TestEngineInstallMirrorValidated(t *testing.T) {
+	t.Cleanup(reset)
+	reset()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	writeFile(t, cfgPath, "test: true\nexecution:\n  engineInstall:\n    tofuMirror: not a url\n")
+
+	err := Init([]string{cfgPath}, false)
+	var cfgErr ConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("expected ConfigError, got %v", err)
+	}
+	if cfgErr.Field != "SoyplaneSettings.Execution.EngineInstall.TofuMirror" {
+		t.Fatalf("expected validation error on TofuMirror, got %s", cfgErr.Field)
+	}
+}
+
+func TestProviderMirrorRequiresCertificates(t *testing.T) {
+	t.Cleanup(reset)
+	reset()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	writeFile(t, cfgPath, "test: true\nproviderMirror:\n  enabled: true\n")
+
+	err := Init([]string{cfgPath}, false)
+	var cfgErr ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "SoyplaneSettings.ProviderMirror.CertDir" {
+		t.Fatalf("expected validation error on CertDir, got %v", err)
+	}
+
+	reset()
+	writeFile(t, cfgPath, "test: true\nproviderMirror:\n  enabled: true\n  certDir: /certs\n")
+	if err := Init([]string{cfgPath}, false); err != nil {
+		t.Fatalf("Init returned error: %v", err)
+	}
+	cfg, err := ProviderMirror()
+	if err != nil {
+		t.Fatalf("ProviderMirror returned error: %v", err)
+	}
+	if cfg.Dir != "/var/lib/soyplane/providers" || cfg.BindAddress != ":8444" {
+		t.Fatalf("unexpected provider mirror defaults %+v", cfg)
+	}
+}
+
+func TestServiceAccountAnnotationsValidated(t *testing.T) {
+	t.Cleanup(reset)
+	reset()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	writeFile(t, cfgPath, "test: true\nexecution:\n  identity:\n    serviceAccountName: tofu\n    serviceAccountAnnotations:\n    - name: eks.amazonaws.com/role-arn\n      value: \"arn:aws:iam::123456789012:role/{{ .Namespace }}\"\n")
+
+	if err := Init([]string{cfgPath}, false); err != nil {
+		t.Fatalf("Init returned error: %v", err)
+	}
+	cfg, err := Execution()
+	if err != nil {
+		t.Fatalf("Execution returned error: %v", err)
+	}
+	annotations := cfg.Identity.ServiceAccountAnnotations
+	if len(annotations) != 1 || annotations[0].Name != "eks.amazonaws.com/role-arn" {
+		t.Fatalf("unexpected service account annotations %+v", annotations)
+	}
+
+	reset()
+	writeFile(t, cfgPath, "test: true\nexecution:\n  identity:\n    serviceAccountAnnotations:\n    - name: iam.gke.io/gcp-service-account\n      value: \"{{ .Namespace\"\n")
+	err = Init([]string{cfgPath}, false)
+	var cfgErr ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Tag != "gotemplate" {
+		t.Fatalf("expected gotemplate ConfigError, got %v", err)
+	}
+}
+
+func TestTracingValidated(t *testing.T) {
+	t.Cleanup(reset)
+	reset()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.yaml")
+	writeFile(t, cfgPath, "test: true\ntracing:\n  enabled: true\n")
+
+	err := Init([]string{cfgPath}, false)
+	var cfgErr ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "SoyplaneSettings.Tracing.Endpoint" {
+		t.Fatalf("expected validation error on Endpoint, got %v", err)
+	}
+
+	reset()
+	writeFile(t, cfgPath, "test: true\ntracing:\n  enabled: true\n  endpoint: otel-collector.observability:4317\n")
+	if err := Init([]string{cfgPath}, false); err != nil {
+		t.Fatalf("Init returned error: %v", err)
+	}
+	cfg, err := Tracing()
+	if err != nil {
+		t.Fatalf("Tracing returned error: %v", err)
+	}
+	if cfg.SampleRatio != 1 {
+		t.Fatalf("expected every execution to be sampled by default, got ratio %v", cfg.SampleRatio)
+	}
+}
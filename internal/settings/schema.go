@@ -3,27 +3,193 @@ package settings
 import (
 	"errors"
 	"fmt"
+	"text/template"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/hashicorp/go-version"
 	"github.com/knadh/koanf/v2"
 	defaults "github.com/mcuadros/go-defaults"
 )
 
 // SoyplaneSettings captures all configuration knobs surfaced to runtime components.
 type SoyplaneSettings struct {
-	Execution ExecutionSettings `koanf:"execution" validate:"required"`
-	Test      bool              `koanf:"test" validate:"required"`
+	Execution      ExecutionSettings      `koanf:"execution" validate:"required"`
+	ProviderMirror ProviderMirrorSettings `koanf:"providerMirror"`
+	Tracing        TracingSettings        `koanf:"tracing"`
+	Test           bool                   `koanf:"test" validate:"required"`
+}
+
+// ProviderMirrorSettings configures the provider network mirror served by the manager. They
+// are read once at startup.
+type ProviderMirrorSettings struct {
+	Enabled bool `koanf:"enabled"`
+	// Dir holds the mirrored providers, usually on a persistent volume.
+	Dir         string `koanf:"dir" default:"/var/lib/soyplane/providers" validate:"startswith=/"`
+	BindAddress string `koanf:"bindAddress" default:":8444" validate:"hostname_port"`
+	// CertDir contains the tls.crt and tls.key the mirror is served with. Engines only talk to
+	// network mirrors over HTTPS.
+	CertDir string `koanf:"certDir" validate:"required_if=Enabled true"`
+}
+
+// TracingSettings configures the OpenTelemetry traces of executions. They are read once at
+// startup.
+type TracingSettings struct {
+	Enabled bool `koanf:"enabled"`
+	// Endpoint is the OTLP gRPC collector endpoint traces are exported to, as host:port.
+	Endpoint string `koanf:"endpoint" validate:"required_if=Enabled true,omitempty,hostname_port"`
+	// Insecure exports without TLS, e.g. to a collector running next to the manager.
+	Insecure bool `koanf:"insecure"`
+	// SampleRatio is the fraction of executions traced.
+	SampleRatio float64 `koanf:"sampleRatio" default:"1" validate:"gt=0,lte=1"`
 }
 
 // ExecutionSettings groups execution-specific knobs.
 type ExecutionSettings struct {
 	DefaultImage string `koanf:"defaultImage" default:"tofuutils/tenv:latest" validate:"required"`
+	// DefaultEngineVersion is recorded on executions that do not request an engine version.
+	DefaultEngineVersion string `koanf:"defaultEngineVersion" default:"latest" validate:"required"`
+	// MaxConcurrent caps the executions running cluster-wide. Zero disables the limit.
+	MaxConcurrent int `koanf:"maxConcurrent" validate:"gte=0"`
+	// MaxConcurrentPerNamespace caps the executions running in any single namespace. Zero disables the limit.
+	MaxConcurrentPerNamespace int `koanf:"maxConcurrentPerNamespace" validate:"gte=0"`
+	// NamespaceMaxConcurrent overrides MaxConcurrentPerNamespace for individual namespaces.
+	NamespaceMaxConcurrent map[string]int `koanf:"namespaceMaxConcurrent" validate:"dive,gte=0"`
+	// SuccessfulHistoryLimit is the default number of succeeded executions kept per module or stack.
+	SuccessfulHistoryLimit int `koanf:"successfulHistoryLimit" default:"10" validate:"gte=0"`
+	// FailedHistoryLimit is the default number of failed executions kept per module or stack.
+	FailedHistoryLimit int `koanf:"failedHistoryLimit" default:"5" validate:"gte=0"`
+	// PodSecurity configures the security defaults applied to execution pods.
+	PodSecurity PodSecuritySettings `koanf:"podSecurity"`
+	// EngineImages maps engines and versions to execution images. The first matching rule wins;
+	// when none matches, executions run on DefaultImage, which installs the engine at run time.
+	EngineImages []EngineImageRule `koanf:"engineImages" validate:"dive"`
+	// EngineInstall configures how the default image installs engine versions.
+	EngineInstall EngineInstallSettings `koanf:"engineInstall"`
+	// ProviderInstallation configures where executions install providers from.
+	ProviderInstallation ProviderInstallationSettings `koanf:"providerInstallation"`
+	// AgentImage is the soyplane agent image. Executions checked by TofuPolicies copy the agent
+	// from it to evaluate their plans, and are rejected while it is unset.
+	AgentImage string `koanf:"agentImage"`
+	// PolicyNamespace holds TofuPolicies checking the modules of every namespace, on top of the
+	// policies of each module's own namespace. Tenants without access to it cannot remove them.
+	PolicyNamespace string `koanf:"policyNamespace"`
+	// Identity configures the service accounts and RBAC execution pods run with.
+	Identity IdentitySettings `koanf:"identity"`
+}
+
+// IdentitySettings configure the Kubernetes identity of execution pods.
+type IdentitySettings struct {
+	// ServiceAccountName is the service account every execution must run as. The
+	// opentofu.soyplane.io/execution-service-account annotation of a namespace overrides it for
+	// the executions of that namespace. Empty lets job templates pick the service account.
+	ServiceAccountName string `koanf:"serviceAccountName"`
+	// CreateServiceAccount creates the mandatory service account in namespaces lacking it.
+	CreateServiceAccount bool `koanf:"createServiceAccount"`
+	// ServiceAccountAnnotations are set on the service accounts created by the controller, e.g.
+	// to bind them to a cloud identity for workload identity federation.
+	ServiceAccountAnnotations []AnnotationTemplate `koanf:"serviceAccountAnnotations" validate:"dive"`
+	// ManageRBAC grants the service account of each execution access to its execution, module
+	// and output targets while it runs. It also mounts the service account token.
+	ManageRBAC bool `koanf:"manageRBAC"`
+}
+
+// AnnotationTemplate is an annotation whose value is rendered per service account.
+type AnnotationTemplate struct {
+	Name string `koanf:"name" validate:"required"`
+	// Value is a Go template rendered with .Namespace and .ServiceAccount, e.g.
+	// "arn:aws:iam::123456789012:role/soyplane-{{ .Namespace }}".
+	Value string `koanf:"value" validate:"gotemplate"`
+}
+
+// ProviderInstallationSettings configure the provider_installation block and plugin cache of
+// the engine CLI configuration used by executions.
+type ProviderInstallationSettings struct {
+	// NetworkMirrorURL is the provider network mirror to install providers from, e.g. the
+	// manager's mirror service. Empty installs providers from their origin registries.
+	NetworkMirrorURL string `koanf:"networkMirrorURL" validate:"omitempty,url,startswith=https://"`
+	// Direct falls back to the origin registries for providers missing from the mirror. Leave
+	// it off in air-gapped clusters.
+	Direct bool `koanf:"direct"`
+	// PluginCacheDir is the engine's plugin_cache_dir. Mount a volume shared between
+	// executions at this path for the cache to outlive a single run.
+	PluginCacheDir string `koanf:"pluginCacheDir" validate:"omitempty,startswith=/"`
+}
+
+// EngineInstallSettings configures the engine downloads done by tenv in the default image.
+type EngineInstallSettings struct {
+	// CacheDir is where engine versions are installed. Empty uses $HOME/.tenv; mount a
+	// persistent volume and point this at it to share downloads between executions.
+	CacheDir string `koanf:"cacheDir" validate:"omitempty,startswith=/"`
+	// TofuMirror replaces the OpenTofu release download site.
+	TofuMirror string `koanf:"tofuMirror" validate:"omitempty,url"`
+	// TerraformMirror replaces the Terraform release download site.
+	TerraformMirror string `koanf:"terraformMirror" validate:"omitempty,url"`
+}
+
+// EngineImageRule selects the execution image for an engine version.
+type EngineImageRule struct {
+	// Engine is the engine name the rule applies to: tofu or terraform.
+	Engine string `koanf:"engine" validate:"required,oneof=tofu terraform"`
+	// Versions is a version constraint such as ">= 1.6, < 1.8". Empty matches every version.
+	Versions string `koanf:"versions" validate:"omitempty,versionconstraint"`
+	// Image is a Go template rendered with .Engine and .Version, e.g.
+	// "mirror.internal/opentofu:{{ .Version }}". It may also pin a digest.
+	Image string `koanf:"image" validate:"required,imagetemplate"`
+}
+
+// Pod security profiles for execution pods.
+const (
+	// PodSecurityRestricted runs execution pods under the restricted Pod Security Standard.
+	PodSecurityRestricted = "restricted"
+	// PodSecurityNone leaves the pod security context to the image and job templates.
+	PodSecurityNone = "none"
+)
+
+// PodSecuritySettings groups the security defaults of execution pods.
+type PodSecuritySettings struct {
+	Profile    string `koanf:"profile" default:"restricted" validate:"oneof=restricted none"`
+	RunAsUser  int64  `koanf:"runAsUser" default:"65532" validate:"gte=1"`
+	RunAsGroup int64  `koanf:"runAsGroup" default:"65532" validate:"gte=0"`
+	FSGroup    int64  `koanf:"fsGroup" default:"65532" validate:"gte=0"`
+	// AutomountServiceAccountToken mounts the service account token into execution pods. Only
+	// needed when the pod talks to the Kubernetes API, e.g. for the kubernetes state backend.
+	AutomountServiceAccountToken bool `koanf:"automountServiceAccountToken"`
+}
+
+// NamespaceLimit returns the concurrency limit applying to namespace, zero meaning unlimited.
+func (s ExecutionSettings) NamespaceLimit(namespace string) int {
+	if limit, ok := s.NamespaceMaxConcurrent[namespace]; ok {
+		return limit
+	}
+	return s.MaxConcurrentPerNamespace
 }
 
 // ErrSettingsNotLoaded indicates that settings have not been loaded yet.
 var ErrSettingsNotLoaded = errors.New("settings not loaded")
 
-var validate = validator.New(validator.WithRequiredStructEnabled())
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	must := func(err error) {
+		if err != nil {
+			panic(err)
+		}
+	}
+	must(v.RegisterValidation("versionconstraint", func(fl validator.FieldLevel) bool {
+		_, err := version.NewConstraint(fl.Field().String())
+		return err == nil
+	}))
+	must(v.RegisterValidation("imagetemplate", func(fl validator.FieldLevel) bool {
+		_, err := template.New("image").Option("missingkey=error").Parse(fl.Field().String())
+		return err == nil
+	}))
+	must(v.RegisterValidation("gotemplate", func(fl validator.FieldLevel) bool {
+		_, err := template.New("value").Option("missingkey=error").Parse(fl.Field().String())
+		return err == nil
+	}))
+	return v
+}
 
 // ConfigError surfaces validation issues for configuration fields.
 type ConfigError struct {
@@ -75,6 +241,26 @@ func Snapshot() (SoyplaneSettings, error) {
 	return cached, nil
 }
 
+// ProviderMirror returns the provider mirror settings with defaults applied.
+func ProviderMirror() (ProviderMirrorSettings, error) {
+	snap, err := Snapshot()
+	if err != nil {
+		return ProviderMirrorSettings{}, err
+	}
+
+	return snap.ProviderMirror, nil
+}
+
+// Tracing returns the tracing settings with defaults applied.
+func Tracing() (TracingSettings, error) {
+	snap, err := Snapshot()
+	if err != nil {
+		return TracingSettings{}, err
+	}
+
+	return snap.Tracing, nil
+}
+
 // Execution returns execution settings with defaults applied.
 func Execution() (ExecutionSettings, error) {
 	snap, err := Snapshot()
@@ -0,0 +1,50 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	crand "crypto/rand"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// forcedIDsKey carries the identity a new root span must take instead of random IDs.
+type forcedIDsKey struct{}
+
+// idGenerator generates random IDs, except for the root spans of execution traces, whose IDs
+// are derived from the execution and passed through the context.
+type idGenerator struct{}
+
+func (g idGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	if sc, ok := ctx.Value(forcedIDsKey{}).(trace.SpanContext); ok {
+		return sc.TraceID(), sc.SpanID()
+	}
+	var traceID trace.TraceID
+	for !traceID.IsValid() {
+		_, _ = crand.Read(traceID[:])
+	}
+	return traceID, g.NewSpanID(ctx, traceID)
+}
+
+func (idGenerator) NewSpanID(context.Context, trace.TraceID) trace.SpanID {
+	var spanID trace.SpanID
+	for !spanID.IsValid() {
+		_, _ = crand.Read(spanID[:])
+	}
+	return spanID
+}
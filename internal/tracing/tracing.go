@@ -0,0 +1,163 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing exports OpenTelemetry traces of executions. Every execution gets a trace of
+// its own, identified by the execution UID, so that the controller's reconciles, the execution
+// Job and the agent all contribute to it without keeping any state.
+package tracing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// TraceParentEnvVar carries the W3C trace context into execution pods.
+	TraceParentEnvVar = "TRACEPARENT"
+	// TraceParentAnnotation records the trace context an execution Job was created under.
+	TraceParentAnnotation = "opentofu.soyplane.io/traceparent"
+
+	instrumentationName = "github.com/soyplane-io/soyplane"
+)
+
+// Options configures the exported traces.
+type Options struct {
+	ServiceName string
+	// Endpoint is the OTLP gRPC endpoint as host:port. When empty the exporter reads the
+	// standard OTEL_EXPORTER_OTLP_* environment variables.
+	Endpoint string
+	Insecure bool
+	// SampleRatio is the fraction of executions traced.
+	SampleRatio float64
+}
+
+// Setup installs a tracer provider exporting over OTLP as the global one. The returned function
+// flushes pending spans and stops the exporter.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	var exporterOpts []otlptracegrpc.Option
+	if opts.Endpoint != "" {
+		exporterOpts = append(exporterOpts, otlptracegrpc.WithEndpoint(opts.Endpoint))
+	}
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, err
+	}
+	provider := newTracerProvider(opts.SampleRatio,
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(opts.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return provider.Shutdown, nil
+}
+
+// newTracerProvider returns a provider able to record the root spans of execution traces.
+// Traces are sampled by trace ID, so every participant makes the same decision for an
+// execution; sampled parents propagated into execution pods are always followed.
+func newTracerProvider(ratio float64, opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	sampler := sdktrace.TraceIDRatioBased(ratio)
+	return sdktrace.NewTracerProvider(append([]sdktrace.TracerProviderOption{
+		sdktrace.WithIDGenerator(idGenerator{}),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler, sdktrace.WithRemoteParentNotSampled(sampler))),
+	}, opts...)...)
+}
+
+// Tracer returns the tracer soyplane components record spans with.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// executionSpanContext returns the identity of the root span of the execution's trace. It is
+// invalid when the UID is not a UUID.
+func executionSpanContext(uid types.UID) trace.SpanContext {
+	var traceID trace.TraceID
+	raw, err := hex.DecodeString(strings.ReplaceAll(string(uid), "-", ""))
+	if err != nil || len(raw) != len(traceID) {
+		return trace.SpanContext{}
+	}
+	copy(traceID[:], raw)
+	var spanID trace.SpanID
+	sum := sha256.Sum256([]byte(uid))
+	copy(spanID[:], sum[:])
+	return trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, Remote: true})
+}
+
+// ExecutionContext returns ctx with the root span of the execution's trace as parent, so that
+// spans started from it join that trace. ctx is returned unchanged when the UID is not a UUID.
+func ExecutionContext(ctx context.Context, uid types.UID) context.Context {
+	sc := executionSpanContext(uid)
+	if !sc.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// RecordExecution records the root span of the execution's trace from start to end, failed
+// with err when it is not nil. It is recorded once the execution finished, as it covers the
+// execution's whole life.
+func RecordExecution(ctx context.Context, uid types.UID, name string, start, end time.Time, err error, opts ...trace.SpanStartOption) {
+	sc := executionSpanContext(uid)
+	if !sc.IsValid() {
+		return
+	}
+	ctx = context.WithValue(ctx, forcedIDsKey{}, sc)
+	_, span := Tracer().Start(ctx, name, append(opts, trace.WithNewRoot(), trace.WithTimestamp(start))...)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(trace.WithTimestamp(end))
+}
+
+// RecordSpan records a finished span under the span in ctx.
+func RecordSpan(ctx context.Context, name string, start, end time.Time, opts ...trace.SpanStartOption) {
+	_, span := Tracer().Start(ctx, name, append(opts, trace.WithTimestamp(start))...)
+	span.End(trace.WithTimestamp(end))
+}
+
+// TraceParent returns the W3C traceparent of the span in ctx, or an empty string when the span
+// is not sampled and there is nothing to continue.
+func TraceParent(ctx context.Context) string {
+	if !trace.SpanContextFromContext(ctx).IsSampled() {
+		return ""
+	}
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// ContextWithTraceParent returns ctx with the span described by a W3C traceparent as parent.
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	return propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{"traceparent": traceParent})
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const uid = types.UID("7c9e6679-7425-40de-944b-e07fc1f90ae7")
+
+func useRecorder(t *testing.T, ratio float64) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(newTracerProvider(ratio, sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestExecutionTrace(t *testing.T) {
+	recorder := useRecorder(t, 1)
+	ctx := ExecutionContext(context.Background(), uid)
+	ctx, reconcile := Tracer().Start(ctx, "Reconcile")
+	reconcile.End()
+	finished := time.Now()
+	RecordSpan(ExecutionContext(context.Background(), uid), "init", finished.Add(-time.Minute), finished)
+	RecordExecution(context.Background(), uid, "TofuExecution", finished.Add(-2*time.Minute), finished, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("recorded %d spans, want 3", len(spans))
+	}
+	root := spans[2].SpanContext()
+	if got := root.TraceID().String(); got != "7c9e6679742540de944be07fc1f90ae7" {
+		t.Errorf("trace ID = %s, want the execution UID", got)
+	}
+	for _, span := range spans[:2] {
+		if span.SpanContext().TraceID() != root.TraceID() || span.Parent().SpanID() != root.SpanID() {
+			t.Errorf("span %s is not a child of the execution span", span.Name())
+		}
+	}
+	if got := spans[1].EndTime().Sub(spans[1].StartTime()); got != time.Minute {
+		t.Errorf("init lasted %s, want 1m", got)
+	}
+	if !spans[2].Parent().Equal(trace.SpanContext{}) {
+		t.Errorf("execution span has parent %v", spans[2].Parent())
+	}
+
+	traceParent := TraceParent(ctx)
+	if traceParent == "" {
+		t.Fatal("no traceparent for a sampled span")
+	}
+	if got := trace.SpanContextFromContext(ContextWithTraceParent(context.Background(), traceParent)); got.SpanID() != reconcile.SpanContext().SpanID() {
+		t.Errorf("traceparent %s continues span %s, want %s", traceParent, got.SpanID(), reconcile.SpanContext().SpanID())
+	}
+}
+
+func TestUnsampledExecution(t *testing.T) {
+	recorder := useRecorder(t, 0)
+	ctx, span := Tracer().Start(ExecutionContext(context.Background(), uid), "Reconcile")
+	span.End()
+	if len(recorder.Ended()) != 0 {
+		t.Error("recorded spans of an unsampled execution")
+	}
+	if got := TraceParent(ctx); got != "" {
+		t.Errorf("traceparent = %q, want none", got)
+	}
+}
+
+func TestInvalidUID(t *testing.T) {
+	ctx := context.Background()
+	if ExecutionContext(ctx, "not-a-uuid") != ctx {
+		t.Error("ExecutionContext changed the context for an invalid UID")
+	}
+}
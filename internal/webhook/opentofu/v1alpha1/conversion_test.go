@@ -0,0 +1,174 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	fuzz "github.com/google/gofuzz"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	webhookconversion "sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	opentofuv1beta1 "github.com/soyplane-io/soyplane/api/opentofu/v1beta1"
+)
+
+// roundTrips is the number of fuzzed objects converted per kind and direction.
+const roundTrips = 50
+
+var _ = Describe("Conversion Webhook", func() {
+	var fuzzer *fuzz.Fuzzer
+
+	BeforeEach(func() {
+		fuzzer = fuzz.New().NilChance(0.2).NumElements(1, 3).Funcs(
+			// Conversions copy object metadata and embedded JSON as is.
+			func(m *metav1.ObjectMeta, c fuzz.Continue) { m.Name = c.RandString() },
+			func(j *apiextensionsv1.JSON, c fuzz.Continue) { j.Raw = []byte(`"` + c.RandString() + `"`) },
+		)
+	})
+
+	// expectRoundTrip fuzzes hub and spoke objects and checks that converting them to the other
+	// version and back is lossless.
+	expectRoundTrip := func(newHub func() conversion.Hub, newSpoke func() conversion.Convertible) {
+		for range roundTrips {
+			hub := newHub()
+			fuzzer.Fuzz(hub)
+			spoke := newSpoke()
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+			back := newHub()
+			Expect(spoke.ConvertTo(back)).To(Succeed())
+			Expect(back).To(Equal(withoutTypeMeta(hub)))
+
+			spoke = newSpoke()
+			fuzzer.Fuzz(spoke)
+			hub = newHub()
+			Expect(spoke.ConvertTo(hub)).To(Succeed())
+			spokeBack := newSpoke()
+			Expect(spokeBack.ConvertFrom(hub)).To(Succeed())
+			Expect(spokeBack).To(Equal(withoutTypeMeta(spoke)))
+		}
+	}
+
+	Context("When converting between v1alpha1 and v1beta1", func() {
+		It("Should round-trip TofuModules", func() {
+			expectRoundTrip(
+				func() conversion.Hub { return &opentofuv1alpha1.TofuModule{} },
+				func() conversion.Convertible { return &opentofuv1beta1.TofuModule{} },
+			)
+		})
+
+		It("Should round-trip TofuExecutions", func() {
+			expectRoundTrip(
+				func() conversion.Hub { return &opentofuv1alpha1.TofuExecution{} },
+				func() conversion.Convertible { return &opentofuv1beta1.TofuExecution{} },
+			)
+		})
+
+		It("Should round-trip TofuStacks", func() {
+			expectRoundTrip(
+				func() conversion.Hub { return &opentofuv1alpha1.TofuStack{} },
+				func() conversion.Convertible { return &opentofuv1beta1.TofuStack{} },
+			)
+		})
+
+		It("Should round-trip TofuProviders", func() {
+			expectRoundTrip(
+				func() conversion.Hub { return &opentofuv1alpha1.TofuProvider{} },
+				func() conversion.Convertible { return &opentofuv1beta1.TofuProvider{} },
+			)
+		})
+
+		It("Should round-trip TofuPolicies", func() {
+			expectRoundTrip(
+				func() conversion.Hub { return &opentofuv1alpha1.TofuPolicy{} },
+				func() conversion.Convertible { return &opentofuv1beta1.TofuPolicy{} },
+			)
+		})
+
+		It("Should round-trip TofuModuleGrants", func() {
+			expectRoundTrip(
+				func() conversion.Hub { return &opentofuv1alpha1.TofuModuleGrant{} },
+				func() conversion.Convertible { return &opentofuv1beta1.TofuModuleGrant{} },
+			)
+		})
+
+		It("Should round-trip TofuNotifications", func() {
+			expectRoundTrip(
+				func() conversion.Hub { return &opentofuv1alpha1.TofuNotification{} },
+				func() conversion.Convertible { return &opentofuv1beta1.TofuNotification{} },
+			)
+		})
+	})
+
+	Context("When serving conversion reviews", func() {
+		It("Should serialize the stack module reference as moduleRef in v1beta1", func() {
+			scheme := runtime.NewScheme()
+			Expect(opentofuv1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(opentofuv1beta1.AddToScheme(scheme)).To(Succeed())
+
+			stack := []byte(`{"apiVersion":"opentofu.soyplane.io/v1alpha1","kind":"TofuStack",` +
+				`"metadata":{"name":"network","namespace":"default"},` +
+				`"spec":{"moduleTemplate":{"name":"network"},"executionTemplate":{"spec":{"action":"plan","moduleRef":{"name":"network"}}}}}`)
+			review := apiextensionsv1.ConversionReview{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+				Request: &apiextensionsv1.ConversionRequest{
+					UID:               types.UID("1"),
+					DesiredAPIVersion: opentofuv1beta1.GroupVersion.String(),
+					Objects:           []runtime.RawExtension{{Raw: stack}},
+				},
+			}
+			body, err := json.Marshal(review)
+			Expect(err).NotTo(HaveOccurred())
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/convert", bytes.NewReader(body))
+			request.Header.Set("Content-Type", "application/json")
+			webhookconversion.NewWebhookHandler(scheme).ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+
+			var response apiextensionsv1.ConversionReview
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.Response.Result.Status).To(Equal(metav1.StatusSuccess))
+			Expect(response.Response.ConvertedObjects).To(HaveLen(1))
+
+			var converted map[string]any
+			Expect(json.Unmarshal(response.Response.ConvertedObjects[0].Raw, &converted)).To(Succeed())
+			Expect(converted).To(HaveKeyWithValue("apiVersion", "opentofu.soyplane.io/v1beta1"))
+			Expect(converted["spec"]).To(HaveKeyWithValue("moduleRef", map[string]any{"name": "network"}))
+			Expect(converted["spec"]).NotTo(HaveKey("moduleTemplate"))
+		})
+	})
+})
+
+// withoutTypeMeta returns obj without its type information, which conversions leave to the
+// caller.
+func withoutTypeMeta(obj runtime.Object) runtime.Object {
+	obj = obj.DeepCopyObject()
+	obj.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+	return obj
+}
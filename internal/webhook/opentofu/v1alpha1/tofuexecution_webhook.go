@@ -0,0 +1,192 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	"github.com/soyplane-io/soyplane/internal/modulegrant"
+	settings "github.com/soyplane-io/soyplane/internal/settings"
+)
+
+// log is for logging in this package.
+var tofuexecutionlog = logf.Log.WithName("tofuexecution-resource")
+
+// SetupTofuExecutionWebhookWithManager registers the webhook for TofuExecution in the manager.
+func SetupTofuExecutionWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&opentofuv1alpha1.TofuExecution{}).
+		WithValidator(&TofuExecutionCustomValidator{Reader: mgr.GetAPIReader()}).
+		WithDefaulter(&TofuExecutionCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-opentofu-soyplane-io-v1alpha1-tofuexecution,mutating=true,failurePolicy=fail,sideEffects=None,groups=opentofu.soyplane.io,resources=tofuexecutions,verbs=create;update,versions=v1alpha1,name=mtofuexecution-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// TofuExecutionCustomDefaulter struct is responsible for setting default values on the custom resource of the
+// Kind TofuExecution when those are created or updated.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as it is used only for temporary operations and does not need to be deeply copied.
+// +kubebuilder:object:generate=false
+type TofuExecutionCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &TofuExecutionCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind TofuExecution.
+// It records the engine, engine version, module namespace and queue priority the execution runs with.
+func (d *TofuExecutionCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	tofuexecution, ok := obj.(*opentofuv1alpha1.TofuExecution)
+	if !ok {
+		return fmt.Errorf("expected a TofuExecution object but got %T", obj)
+	}
+	tofuexecutionlog.Info("Defaulting for TofuExecution", "name", tofuexecution.GetName())
+
+	spec := &tofuexecution.Spec
+	if err := defaultEngine(&spec.Engine); err != nil {
+		return err
+	}
+	if spec.ModuleRef.Namespace == "" {
+		spec.ModuleRef.Namespace = tofuexecution.Namespace
+	}
+	if spec.Priority == nil {
+		priority := opentofuv1alpha1.DefaultPlanPriority
+		if spec.Action == "apply" {
+			priority = opentofuv1alpha1.DefaultApplyPriority
+		}
+		spec.Priority = &priority
+	}
+	return nil
+}
+
+// defaultEngine records the engine and engine version an execution runs with when unset.
+func defaultEngine(engine *opentofuv1alpha1.EngineSpec) error {
+	execCfg, err := settings.Execution()
+	if err != nil {
+		return fmt.Errorf("invalid settings: %w", err)
+	}
+	if engine.Name == "" {
+		engine.Name = opentofuv1alpha1.DefaultEngine
+	}
+	if engine.Version == "" {
+		engine.Version = execCfg.DefaultEngineVersion
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-opentofu-soyplane-io-v1alpha1-tofuexecution,mutating=false,failurePolicy=fail,sideEffects=None,groups=opentofu.soyplane.io,resources=tofuexecutions,verbs=create;update,versions=v1alpha1,name=vtofuexecution-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// TofuExecutionCustomValidator struct is responsible for validating the TofuExecution resource
+// when it is created, updated, or deleted.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+// +kubebuilder:object:generate=false
+type TofuExecutionCustomValidator struct {
+	// Reader looks up the referenced module and the grants permitting the reference. It bypasses
+	// the cache, so that a module created right before its execution is found.
+	Reader client.Reader
+}
+
+var _ webhook.CustomValidator = &TofuExecutionCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type TofuExecution.
+func (v *TofuExecutionCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	tofuexecution, ok := obj.(*opentofuv1alpha1.TofuExecution)
+	if !ok {
+		return nil, fmt.Errorf("expected a TofuExecution object but got %T", obj)
+	}
+	tofuexecutionlog.Info("Validation for TofuExecution upon creation", "name", tofuexecution.GetName())
+
+	return nil, v.validateTofuExecution(ctx, nil, tofuexecution)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type TofuExecution.
+func (v *TofuExecutionCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	tofuexecution, ok := newObj.(*opentofuv1alpha1.TofuExecution)
+	if !ok {
+		return nil, fmt.Errorf("expected a TofuExecution object for the newObj but got %T", newObj)
+	}
+	old, ok := oldObj.(*opentofuv1alpha1.TofuExecution)
+	if !ok {
+		return nil, fmt.Errorf("expected a TofuExecution object for the oldObj but got %T", oldObj)
+	}
+	tofuexecutionlog.Info("Validation for TofuExecution upon update", "name", tofuexecution.GetName())
+
+	return nil, v.validateTofuExecution(ctx, old, tofuexecution)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type TofuExecution.
+func (v *TofuExecutionCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateTofuExecution checks the execution, and that its module exists unless an update keeps
+// the module reference: cancelling the execution of a deleted module must still work.
+func (v *TofuExecutionCustomValidator) validateTofuExecution(ctx context.Context, old, execution *opentofuv1alpha1.TofuExecution) error {
+	spec := field.NewPath("spec")
+	errs := validateExecutionSpec(spec, &execution.Spec)
+	if old == nil || old.Spec.ModuleRef != execution.Spec.ModuleRef {
+		fieldErr, err := v.validateModuleRef(ctx, spec.Child("moduleRef"), execution)
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+		if fieldErr != nil {
+			errs = append(errs, fieldErr)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(opentofuv1alpha1.GroupVersion.WithKind("TofuExecution").GroupKind(), execution.Name, errs)
+}
+
+// validateModuleRef reports a field error when the referenced module does not exist or no
+// TofuModuleGrant permits referencing it from the execution's namespace, and a lookup error when
+// that could not be determined.
+func (v *TofuExecutionCustomValidator) validateModuleRef(ctx context.Context, path *field.Path, execution *opentofuv1alpha1.TofuExecution) (*field.Error, error) {
+	ref := execution.Spec.ModuleRef
+	if ref.Name == "" {
+		return field.Required(path.Child("name"), ""), nil
+	}
+	key := modulegrant.ModuleKey(ref, execution.Namespace)
+	var module opentofuv1alpha1.TofuModule
+	if err := v.Reader.Get(ctx, key, &module); err != nil {
+		if apierrors.IsNotFound(err) {
+			return field.NotFound(path, key.String()), nil
+		}
+		return nil, err
+	}
+	if key.Namespace == execution.Namespace {
+		return nil, nil
+	}
+	kind, err := modulegrant.ExecutionKind(ctx, v.Reader, execution)
+	if err != nil {
+		return nil, err
+	}
+	return validateModuleGrant(ctx, v.Reader, path, kind, execution.Namespace, key)
+}
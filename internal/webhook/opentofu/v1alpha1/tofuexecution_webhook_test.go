@@ -0,0 +1,140 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+var _ = Describe("TofuExecution Webhook", func() {
+	var (
+		obj       *opentofuv1alpha1.TofuExecution
+		oldObj    *opentofuv1alpha1.TofuExecution
+		validator TofuExecutionCustomValidator
+		defaulter TofuExecutionCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(opentofuv1alpha1.AddToScheme(scheme)).To(Succeed())
+		module := &opentofuv1alpha1.TofuModule{ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "infra"}}
+		grant := &opentofuv1alpha1.TofuModuleGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "infra"},
+			Spec: opentofuv1alpha1.TofuModuleGrantSpec{
+				From: []opentofuv1alpha1.TofuModuleGrantFrom{{Kind: "TofuExecution", Namespace: "default"}},
+			},
+		}
+		stackModule := &opentofuv1alpha1.TofuModule{ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "platform"}}
+		stackGrant := &opentofuv1alpha1.TofuModuleGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: "stacks", Namespace: "platform"},
+			Spec: opentofuv1alpha1.TofuModuleGrantSpec{
+				From: []opentofuv1alpha1.TofuModuleGrantFrom{{Kind: "TofuStack", Namespace: "default"}},
+			},
+		}
+		stack := &opentofuv1alpha1.TofuStack{
+			ObjectMeta: metav1.ObjectMeta{Name: "envs", Namespace: "default", UID: "stack-uid"},
+			Spec:       opentofuv1alpha1.TofuStackSpec{ModuleRef: opentofuv1alpha1.ObjectRef{Name: "network", Namespace: "platform"}},
+		}
+
+		obj = &opentofuv1alpha1.TofuExecution{
+			ObjectMeta: metav1.ObjectMeta{Name: "run", Namespace: "default"},
+			Spec: opentofuv1alpha1.TofuExecutionSpec{
+				Action:    "plan",
+				ModuleRef: opentofuv1alpha1.ObjectRef{Name: "network", Namespace: "infra"},
+			},
+		}
+		oldObj = obj.DeepCopy()
+		validator = TofuExecutionCustomValidator{Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(module, grant, stackModule, stackGrant, stack).Build()}
+		defaulter = TofuExecutionCustomDefaulter{}
+	})
+
+	Context("When creating TofuExecution under Defaulting Webhook", func() {
+		It("Should record the engine, module namespace and priority", func() {
+			obj.Spec.ModuleRef.Namespace = ""
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Engine).To(Equal(opentofuv1alpha1.EngineSpec{Name: "tofu", Version: "1.9.0"}))
+			Expect(obj.Spec.ModuleRef.Namespace).To(Equal("default"))
+			Expect(obj.Spec.Priority).To(HaveValue(Equal(opentofuv1alpha1.DefaultPlanPriority)))
+		})
+
+		It("Should keep explicit values and prioritize applies", func() {
+			obj.Spec.Action = "apply"
+			obj.Spec.Engine = opentofuv1alpha1.EngineSpec{Name: "terraform", Version: "1.5.7"}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Engine).To(Equal(opentofuv1alpha1.EngineSpec{Name: "terraform", Version: "1.5.7"}))
+			Expect(obj.Spec.ModuleRef.Namespace).To(Equal("infra"))
+			Expect(obj.Spec.Priority).To(HaveValue(Equal(opentofuv1alpha1.DefaultApplyPriority)))
+		})
+	})
+
+	Context("When creating or updating TofuExecution under Validating Webhook", func() {
+		It("Should admit executions of existing modules", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny executions of missing modules", func() {
+			obj.Spec.ModuleRef.Namespace = ""
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(`spec.moduleRef: Not found: "default/network"`)))
+		})
+
+		It("Should deny cross-namespace module references no grant permits", func() {
+			obj.Spec.ModuleRef.Namespace = "platform"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.moduleRef: Forbidden: no TofuModuleGrant in namespace platform permits TofuExecution objects")))
+		})
+
+		It("Should admit executions created by a stack under the stack's grant", func() {
+			obj.Spec.ModuleRef.Namespace = "platform"
+			obj.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: opentofuv1alpha1.GroupVersion.String(),
+				Kind:       "TofuStack",
+				Name:       "envs",
+				UID:        "stack-uid",
+				Controller: ptr.To(true),
+			}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+
+			obj.OwnerReferences[0].UID = "other-uid"
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should only check the module when the reference changes", func() {
+			oldObj.Spec.ModuleRef.Name = "deleted"
+			obj.Spec.ModuleRef.Name = "deleted"
+			obj.Spec.Cancel = true
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+
+			obj.Spec.ModuleRef.Name = "other"
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should deny value sources referencing neither a Secret nor a ConfigMap", func() {
+			obj.Spec.ValueSources = map[string]opentofuv1alpha1.ValueFrom{"token": {}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.valueSources[token]: Required value")))
+		})
+	})
+})
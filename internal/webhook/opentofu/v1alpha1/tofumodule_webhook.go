@@ -0,0 +1,170 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	"github.com/soyplane-io/soyplane/internal/pullrequest"
+)
+
+// log is for logging in this package.
+var tofumodulelog = logf.Log.WithName("tofumodule-resource")
+
+// SetupTofuModuleWebhookWithManager registers the webhook for TofuModule in the manager.
+func SetupTofuModuleWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&opentofuv1alpha1.TofuModule{}).
+		WithValidator(&TofuModuleCustomValidator{}).
+		WithDefaulter(&TofuModuleCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-opentofu-soyplane-io-v1alpha1-tofumodule,mutating=true,failurePolicy=fail,sideEffects=None,groups=opentofu.soyplane.io,resources=tofumodules,verbs=create;update,versions=v1alpha1,name=mtofumodule-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// TofuModuleCustomDefaulter struct is responsible for setting default values on the custom resource of the
+// Kind TofuModule when those are created or updated.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as it is used only for temporary operations and does not need to be deeply copied.
+// +kubebuilder:object:generate=false
+type TofuModuleCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &TofuModuleCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind TofuModule.
+// It records the working directory, and the engine and engine version of the execution template,
+// so that the module shows what its executions run with.
+func (d *TofuModuleCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	tofumodule, ok := obj.(*opentofuv1alpha1.TofuModule)
+	if !ok {
+		return fmt.Errorf("expected a TofuModule object but got %T", obj)
+	}
+	tofumodulelog.Info("Defaulting for TofuModule", "name", tofumodule.GetName())
+
+	if tofumodule.Spec.Workdir == "" {
+		tofumodule.Spec.Workdir = opentofuv1alpha1.DefaultWorkdir
+	}
+	return defaultEngine(&tofumodule.Spec.ExecutionTemplate.Spec.Engine)
+}
+
+// +kubebuilder:webhook:path=/validate-opentofu-soyplane-io-v1alpha1-tofumodule,mutating=false,failurePolicy=fail,sideEffects=None,groups=opentofu.soyplane.io,resources=tofumodules,verbs=create;update,versions=v1alpha1,name=vtofumodule-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// TofuModuleCustomValidator struct is responsible for validating the TofuModule resource
+// when it is created, updated, or deleted.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+// +kubebuilder:object:generate=false
+type TofuModuleCustomValidator struct{}
+
+var _ webhook.CustomValidator = &TofuModuleCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type TofuModule.
+func (v *TofuModuleCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	tofumodule, ok := obj.(*opentofuv1alpha1.TofuModule)
+	if !ok {
+		return nil, fmt.Errorf("expected a TofuModule object but got %T", obj)
+	}
+	tofumodulelog.Info("Validation for TofuModule upon creation", "name", tofumodule.GetName())
+
+	return nil, validateTofuModule(tofumodule)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type TofuModule.
+func (v *TofuModuleCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	tofumodule, ok := newObj.(*opentofuv1alpha1.TofuModule)
+	if !ok {
+		return nil, fmt.Errorf("expected a TofuModule object for the newObj but got %T", newObj)
+	}
+	tofumodulelog.Info("Validation for TofuModule upon update", "name", tofumodule.GetName())
+
+	return nil, validateTofuModule(tofumodule)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type TofuModule.
+func (v *TofuModuleCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateTofuModule(module *opentofuv1alpha1.TofuModule) error {
+	spec := field.NewPath("spec")
+	var errs field.ErrorList
+	if err := validateSource(spec.Child("source"), module.Spec.Source); err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, validateValueSources(spec.Child("valueSources"), module.Spec.ValueSources)...)
+	errs = append(errs, validateValueSources(spec.Child("backend", "valueSources"), module.Spec.Backend.ValueSources)...)
+	errs = append(errs, validateOutputs(spec.Child("outputs"), module.Spec.Outputs)...)
+	errs = append(errs, validateExecutionSpec(spec.Child("executionTemplate", "spec"), &module.Spec.ExecutionTemplate.Spec)...)
+	errs = append(errs, validatePullRequestComment(spec, module)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(opentofuv1alpha1.GroupVersion.WithKind("TofuModule").GroupKind(), module.Name, errs)
+}
+
+// validatePullRequestComment checks that a module commenting on pull requests tracks a ref and
+// is cloned from a repository the provider API can be derived from.
+func validatePullRequestComment(spec *field.Path, module *opentofuv1alpha1.TofuModule) field.ErrorList {
+	comment := module.Spec.PullRequestComment
+	if comment == nil {
+		return nil
+	}
+	path := spec.Child("pullRequestComment")
+	var errs field.ErrorList
+	if module.Spec.Version == "" {
+		errs = append(errs, field.Required(spec.Child("version"), "must name the branch or pull request ref to comment on"))
+	}
+	if comment.APIURL != "" {
+		if u, err := url.Parse(comment.APIURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, field.Invalid(path.Child("apiURL"), comment.APIURL, "must be an http or https URL"))
+		}
+	}
+	if _, err := pullrequest.NewClient(*comment, module.Spec.Source, ""); err != nil {
+		errs = append(errs, field.Invalid(spec.Child("source"), module.Spec.Source, err.Error()))
+	}
+	return errs
+}
+
+// validateOutputs refuses output targets writing the same key of the same object twice, as the
+// outputs would overwrite each other.
+func validateOutputs(path *field.Path, outputs []opentofuv1alpha1.OutputSpec) field.ErrorList {
+	var errs field.ErrorList
+	seen := make(map[opentofuv1alpha1.OutputTarget]string)
+	for i, output := range outputs {
+		for j, target := range output.To {
+			targetPath := path.Index(i).Child("to").Index(j)
+			if previous, found := seen[target]; found {
+				errs = append(errs, field.Duplicate(targetPath, fmt.Sprintf("%s %s key %s, also written by %s", target.Kind, target.Name, target.Key, previous)))
+				continue
+			}
+			seen[target] = targetPath.String()
+		}
+	}
+	return errs
+}
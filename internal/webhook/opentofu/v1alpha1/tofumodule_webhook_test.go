@@ -0,0 +1,131 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+var _ = Describe("TofuModule Webhook", func() {
+	var (
+		obj       *opentofuv1alpha1.TofuModule
+		oldObj    *opentofuv1alpha1.TofuModule
+		validator TofuModuleCustomValidator
+		defaulter TofuModuleCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		obj = &opentofuv1alpha1.TofuModule{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "default"},
+			Spec:       opentofuv1alpha1.TofuModuleSpec{Source: "https://github.com/soyplane-io/sample-tofu-modules.git"},
+		}
+		oldObj = obj.DeepCopy()
+		validator = TofuModuleCustomValidator{}
+		defaulter = TofuModuleCustomDefaulter{}
+	})
+
+	Context("When creating TofuModule under Defaulting Webhook", func() {
+		It("Should default the working directory to the repository root", func() {
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Workdir).To(Equal(opentofuv1alpha1.DefaultWorkdir))
+
+			obj.Spec.Workdir = "modules/network"
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Workdir).To(Equal("modules/network"))
+		})
+
+		It("Should default the engine of the execution template", func() {
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.ExecutionTemplate.Spec.Engine).To(Equal(opentofuv1alpha1.EngineSpec{Name: opentofuv1alpha1.DefaultEngine, Version: "1.9.0"}))
+
+			obj.Spec.ExecutionTemplate.Spec.Engine = opentofuv1alpha1.EngineSpec{Name: "terraform", Version: "1.5.7"}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.ExecutionTemplate.Spec.Engine).To(Equal(opentofuv1alpha1.EngineSpec{Name: "terraform", Version: "1.5.7"}))
+		})
+	})
+
+	Context("When creating or updating TofuModule under Validating Webhook", func() {
+		It("Should admit git sources", func() {
+			for _, source := range []string{
+				"https://github.com/soyplane-io/sample-tofu-modules.git",
+				"ssh://git@github.com/soyplane-io/sample-tofu-modules.git",
+				"git@github.com:soyplane-io/sample-tofu-modules.git",
+				"file:///srv/git/modules.git",
+			} {
+				obj.Spec.Source = source
+				Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred(), source)
+			}
+		})
+
+		It("Should deny invalid sources", func() {
+			for _, source := range []string{
+				"",
+				"github.com/soyplane-io/sample-tofu-modules",
+				"ftp://example.com/modules.git",
+				"https://example.com",
+				"https://example.com/repo.git; rm -rf /",
+			} {
+				obj.Spec.Source = source
+				Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred(), source)
+			}
+		})
+
+		It("Should deny outputs writing the same key twice", func() {
+			target := opentofuv1alpha1.OutputTarget{Kind: "Secret", Name: "network", Key: "vpc_id"}
+			obj.Spec.Outputs = []opentofuv1alpha1.OutputSpec{
+				{From: "vpc_id", To: []opentofuv1alpha1.OutputTarget{target}},
+				{From: "vpc", To: []opentofuv1alpha1.OutputTarget{{Kind: "ConfigMap", Name: "network", Key: "vpc_id"}, target}},
+			}
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.outputs[1].to[1]")))
+		})
+
+		It("Should deny value sources referencing both a Secret and a ConfigMap", func() {
+			ref := &opentofuv1alpha1.KeyRef{Name: "network", Key: "token"}
+			obj.Spec.ValueSources = map[string]opentofuv1alpha1.ValueFrom{
+				"token": {SecretRef: ref, ConfigMapRef: ref},
+			}
+			obj.Spec.ExecutionTemplate.Spec.ValueSources = map[string]opentofuv1alpha1.ValueFrom{"region": {}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.valueSources[token]")))
+			Expect(err).To(MatchError(ContainSubstring("spec.executionTemplate.spec.valueSources[region]")))
+		})
+
+		It("Should require pull request comments to track a ref of a hosted repository", func() {
+			obj.Spec.PullRequestComment = &opentofuv1alpha1.PullRequestCommentSpec{
+				Provider:       opentofuv1alpha1.GitProviderGitHub,
+				TokenSecretRef: opentofuv1alpha1.KeyRef{Name: "github", Key: "token"},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.version: Required value")))
+
+			obj.Spec.Version = "refs/pull/42/head"
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+
+			obj.Spec.Source = "file:///srv/git/modules.git"
+			obj.Spec.PullRequestComment.APIURL = "github.example.com/api/v3"
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.source")))
+			Expect(err).To(MatchError(ContainSubstring("spec.pullRequestComment.apiURL")))
+		})
+	})
+})
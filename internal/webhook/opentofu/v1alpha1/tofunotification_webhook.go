@@ -0,0 +1,121 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	"github.com/soyplane-io/soyplane/internal/notification"
+)
+
+// log is for logging in this package.
+var tofunotificationlog = logf.Log.WithName("tofunotification-resource")
+
+// SetupTofuNotificationWebhookWithManager registers the webhook for TofuNotification in the manager.
+func SetupTofuNotificationWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&opentofuv1alpha1.TofuNotification{}).
+		WithValidator(&TofuNotificationCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-opentofu-soyplane-io-v1alpha1-tofunotification,mutating=false,failurePolicy=fail,sideEffects=None,groups=opentofu.soyplane.io,resources=tofunotifications,verbs=create;update,versions=v1alpha1,name=vtofunotification-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// TofuNotificationCustomValidator struct is responsible for validating the TofuNotification resource
+// when it is created, updated, or deleted.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+// +kubebuilder:object:generate=false
+type TofuNotificationCustomValidator struct{}
+
+var _ webhook.CustomValidator = &TofuNotificationCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type TofuNotification.
+func (v *TofuNotificationCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	tofunotification, ok := obj.(*opentofuv1alpha1.TofuNotification)
+	if !ok {
+		return nil, fmt.Errorf("expected a TofuNotification object but got %T", obj)
+	}
+	tofunotificationlog.Info("Validation for TofuNotification upon creation", "name", tofunotification.GetName())
+
+	return nil, validateTofuNotification(tofunotification)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type TofuNotification.
+func (v *TofuNotificationCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	tofunotification, ok := newObj.(*opentofuv1alpha1.TofuNotification)
+	if !ok {
+		return nil, fmt.Errorf("expected a TofuNotification object for the newObj but got %T", newObj)
+	}
+	tofunotificationlog.Info("Validation for TofuNotification upon update", "name", tofunotification.GetName())
+
+	return nil, validateTofuNotification(tofunotification)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type TofuNotification.
+func (v *TofuNotificationCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateTofuNotification checks the sink, selectors and template, which would otherwise only
+// fail when an event is delivered.
+func validateTofuNotification(n *opentofuv1alpha1.TofuNotification) error {
+	spec := field.NewPath("spec")
+	sink := spec.Child("sink")
+	var errs field.ErrorList
+	switch {
+	case n.Spec.Sink.URL != "" && n.Spec.Sink.URLSecretRef != nil:
+		errs = append(errs, field.Invalid(sink, "url, urlSecretRef", "must set either url or urlSecretRef, not both"))
+	case n.Spec.Sink.URL == "" && n.Spec.Sink.URLSecretRef == nil:
+		errs = append(errs, field.Required(sink, "must set url or urlSecretRef"))
+	case n.Spec.Sink.URL != "":
+		if u, err := url.Parse(n.Spec.Sink.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, field.Invalid(sink.Child("url"), n.Spec.Sink.URL, "must be an http or https URL"))
+		}
+	}
+	if n.Spec.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(n.Spec.NamespaceSelector); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("namespaceSelector"), n.Spec.NamespaceSelector, err.Error()))
+		}
+	}
+	if n.Spec.ModuleSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(n.Spec.ModuleSelector); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("moduleSelector"), n.Spec.ModuleSelector, err.Error()))
+		}
+	}
+	if n.Spec.Template != "" {
+		if _, err := notification.ParseTemplate(n.Spec.Template); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("template"), n.Spec.Template, err.Error()))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(opentofuv1alpha1.GroupVersion.WithKind("TofuNotification").GroupKind(), n.Name, errs)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+var _ = Describe("TofuNotification Webhook", func() {
+	var (
+		obj       *opentofuv1alpha1.TofuNotification
+		validator TofuNotificationCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &opentofuv1alpha1.TofuNotification{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform-slack"},
+			Spec: opentofuv1alpha1.TofuNotificationSpec{
+				Sink: opentofuv1alpha1.NotificationSink{
+					Type: opentofuv1alpha1.SinkSlack,
+					URLSecretRef: &opentofuv1alpha1.NamespacedKeyRef{
+						Namespace: "soyplane-system", Name: "slack-webhook", Key: "url",
+					},
+				},
+				Events:   []opentofuv1alpha1.NotificationEvent{"ApplyFailed"},
+				Template: "{{ .Namespace }}/{{ .Name }}: {{ .Message }}",
+			},
+		}
+		validator = TofuNotificationCustomValidator{}
+	})
+
+	Context("When creating or updating TofuNotification under Validating Webhook", func() {
+		It("Should admit a valid notification", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+			obj.Spec.Sink.URLSecretRef = nil
+			obj.Spec.Sink.URL = "https://hooks.example.com/soyplane"
+			Expect(validator.ValidateUpdate(ctx, obj.DeepCopy(), obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should require exactly one of url and urlSecretRef", func() {
+			obj.Spec.Sink.URL = "https://hooks.example.com/soyplane"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("must set either url or urlSecretRef, not both")))
+
+			obj.Spec.Sink.URL = ""
+			obj.Spec.Sink.URLSecretRef = nil
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.sink: Required value")))
+		})
+
+		It("Should deny URLs other than http and https", func() {
+			obj.Spec.Sink.URLSecretRef = nil
+			obj.Spec.Sink.URL = "ftp://hooks.example.com/soyplane"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.sink.url")))
+		})
+
+		It("Should deny invalid selectors and templates", func() {
+			obj.Spec.NamespaceSelector = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key: "team", Operator: "Near",
+			}}}
+			obj.Spec.Template = "{{ .Message "
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.namespaceSelector")))
+			Expect(err).To(MatchError(ContainSubstring("spec.template")))
+		})
+	})
+})
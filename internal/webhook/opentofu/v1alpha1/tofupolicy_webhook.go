@@ -0,0 +1,110 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	"github.com/soyplane-io/soyplane/internal/policy"
+)
+
+// log is for logging in this package.
+var tofupolicylog = logf.Log.WithName("tofupolicy-resource")
+
+// SetupTofuPolicyWebhookWithManager registers the webhook for TofuPolicy in the manager.
+func SetupTofuPolicyWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&opentofuv1alpha1.TofuPolicy{}).
+		WithValidator(&TofuPolicyCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-opentofu-soyplane-io-v1alpha1-tofupolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=opentofu.soyplane.io,resources=tofupolicies,verbs=create;update,versions=v1alpha1,name=vtofupolicy-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// TofuPolicyCustomValidator struct is responsible for validating the TofuPolicy resource
+// when it is created, updated, or deleted.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+// +kubebuilder:object:generate=false
+type TofuPolicyCustomValidator struct{}
+
+var _ webhook.CustomValidator = &TofuPolicyCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type TofuPolicy.
+func (v *TofuPolicyCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	tofupolicy, ok := obj.(*opentofuv1alpha1.TofuPolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected a TofuPolicy object but got %T", obj)
+	}
+	tofupolicylog.Info("Validation for TofuPolicy upon creation", "name", tofupolicy.GetName())
+
+	return nil, validateTofuPolicy(tofupolicy)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type TofuPolicy.
+func (v *TofuPolicyCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	tofupolicy, ok := newObj.(*opentofuv1alpha1.TofuPolicy)
+	if !ok {
+		return nil, fmt.Errorf("expected a TofuPolicy object for the newObj but got %T", newObj)
+	}
+	tofupolicylog.Info("Validation for TofuPolicy upon update", "name", tofupolicy.GetName())
+
+	return nil, validateTofuPolicy(tofupolicy)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type TofuPolicy.
+func (v *TofuPolicyCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateTofuPolicy compiles the rules, so that mistakes surface when the policy is applied
+// rather than as Error outcomes blocking every apply of the selected modules.
+func validateTofuPolicy(p *opentofuv1alpha1.TofuPolicy) error {
+	spec := field.NewPath("spec")
+	var errs field.ErrorList
+	if p.Spec.ModuleSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(p.Spec.ModuleSelector); err != nil {
+			errs = append(errs, field.Invalid(spec.Child("moduleSelector"), p.Spec.ModuleSelector, err.Error()))
+		}
+	}
+	rules := make(map[string]bool, len(p.Spec.Rules))
+	for i, rule := range p.Spec.Rules {
+		path := spec.Child("rules").Index(i)
+		if rules[rule.Name] {
+			errs = append(errs, field.Duplicate(path.Child("name"), rule.Name))
+		}
+		rules[rule.Name] = true
+		if _, err := policy.Compile(rule.Expression); err != nil {
+			errs = append(errs, field.Invalid(path.Child("expression"), rule.Expression, err.Error()))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(opentofuv1alpha1.GroupVersion.WithKind("TofuPolicy").GroupKind(), p.Name, errs)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+var _ = Describe("TofuPolicy Webhook", func() {
+	var (
+		obj       *opentofuv1alpha1.TofuPolicy
+		validator TofuPolicyCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &opentofuv1alpha1.TofuPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "guardrails", Namespace: "default"},
+			Spec: opentofuv1alpha1.TofuPolicySpec{
+				Rules: []opentofuv1alpha1.PolicyRule{{
+					Name:       "no-deletes",
+					Expression: `plan.resource_changes.all(rc, !("delete" in rc.change.actions))`,
+				}},
+			},
+		}
+		validator = TofuPolicyCustomValidator{}
+	})
+
+	Context("When creating or updating TofuPolicy under Validating Webhook", func() {
+		It("Should admit valid rules", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny rules that do not compile or return a bool", func() {
+			obj.Spec.Rules = append(obj.Spec.Rules,
+				opentofuv1alpha1.PolicyRule{Name: "broken", Expression: "plan.resource_changes.all("},
+				opentofuv1alpha1.PolicyRule{Name: "count", Expression: "1 + 1"},
+			)
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.rules[1].expression")))
+			Expect(err).To(MatchError(ContainSubstring("spec.rules[2].expression")))
+		})
+
+		It("Should deny duplicate rule names", func() {
+			obj.Spec.Rules = append(obj.Spec.Rules, obj.Spec.Rules[0])
+			_, err := validator.ValidateUpdate(ctx, obj.DeepCopy(), obj)
+			Expect(err).To(MatchError(ContainSubstring(`spec.rules[1].name: Duplicate value: "no-deletes"`)))
+		})
+	})
+})
@@ -0,0 +1,101 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+// log is for logging in this package.
+var tofuproviderlog = logf.Log.WithName("tofuprovider-resource")
+
+// SetupTofuProviderWebhookWithManager registers the webhook for TofuProvider in the manager.
+func SetupTofuProviderWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&opentofuv1alpha1.TofuProvider{}).
+		WithValidator(&TofuProviderCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-opentofu-soyplane-io-v1alpha1-tofuprovider,mutating=false,failurePolicy=fail,sideEffects=None,groups=opentofu.soyplane.io,resources=tofuproviders,verbs=create;update,versions=v1alpha1,name=vtofuprovider-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// TofuProviderCustomValidator struct is responsible for validating the TofuProvider resource
+// when it is created, updated, or deleted.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+// +kubebuilder:object:generate=false
+type TofuProviderCustomValidator struct{}
+
+var _ webhook.CustomValidator = &TofuProviderCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type TofuProvider.
+func (v *TofuProviderCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	tofuprovider, ok := obj.(*opentofuv1alpha1.TofuProvider)
+	if !ok {
+		return nil, fmt.Errorf("expected a TofuProvider object but got %T", obj)
+	}
+	tofuproviderlog.Info("Validation for TofuProvider upon creation", "name", tofuprovider.GetName())
+
+	return nil, validateTofuProvider(tofuprovider)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type TofuProvider.
+func (v *TofuProviderCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	tofuprovider, ok := newObj.(*opentofuv1alpha1.TofuProvider)
+	if !ok {
+		return nil, fmt.Errorf("expected a TofuProvider object for the newObj but got %T", newObj)
+	}
+	tofuproviderlog.Info("Validation for TofuProvider upon update", "name", tofuprovider.GetName())
+
+	return nil, validateTofuProvider(tofuprovider)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type TofuProvider.
+func (v *TofuProviderCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateTofuProvider(provider *opentofuv1alpha1.TofuProvider) error {
+	spec := field.NewPath("spec")
+	var errs field.ErrorList
+	if provider.Spec.RawConfig != "" && len(provider.Spec.Config) > 0 {
+		errs = append(errs, field.Forbidden(spec.Child("config"), "must not be set together with rawConfig"))
+	}
+	for _, name := range slices.Sorted(maps.Keys(provider.Spec.ValueSources)) {
+		source := provider.Spec.ValueSources[name]
+		if err := validateValueSource(spec.Child("valueSources").Key(name), source.SecretRef != nil, source.ConfigMapRef != nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(opentofuv1alpha1.GroupVersion.WithKind("TofuProvider").GroupKind(), provider.Name, errs)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+var _ = Describe("TofuProvider Webhook", func() {
+	var (
+		obj       *opentofuv1alpha1.TofuProvider
+		validator TofuProviderCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &opentofuv1alpha1.TofuProvider{
+			ObjectMeta: metav1.ObjectMeta{Name: "aws", Namespace: "default"},
+			Spec:       opentofuv1alpha1.TofuProviderSpec{Type: "aws", RawConfig: `region = "eu-west-1"`},
+		}
+		validator = TofuProviderCustomValidator{}
+	})
+
+	Context("When creating or updating TofuProvider under Validating Webhook", func() {
+		It("Should admit a single configuration", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny rawConfig together with config", func() {
+			obj.Spec.Config = map[string]apiextv1.JSON{"region": {Raw: []byte(`"eu-west-1"`)}}
+			_, err := validator.ValidateUpdate(ctx, obj.DeepCopy(), obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.config: Forbidden")))
+		})
+
+		It("Should deny value sources referencing both a Secret and a ConfigMap", func() {
+			obj.Spec.ValueSources = map[string]opentofuv1alpha1.ValueSource{"access_key": {
+				SecretRef:    &opentofuv1alpha1.SecretKeyRef{Name: "aws", Key: "access_key"},
+				ConfigMapRef: &opentofuv1alpha1.ConfigMapKeyRef{Name: "aws", Key: "access_key"},
+			}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.valueSources[access_key]")))
+		})
+	})
+})
@@ -0,0 +1,168 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	"github.com/soyplane-io/soyplane/internal/modulegrant"
+)
+
+// log is for logging in this package.
+var tofustacklog = logf.Log.WithName("tofustack-resource")
+
+// SetupTofuStackWebhookWithManager registers the webhook for TofuStack in the manager.
+func SetupTofuStackWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&opentofuv1alpha1.TofuStack{}).
+		WithValidator(&TofuStackCustomValidator{Reader: mgr.GetAPIReader()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-opentofu-soyplane-io-v1alpha1-tofustack,mutating=false,failurePolicy=fail,sideEffects=None,groups=opentofu.soyplane.io,resources=tofustacks,verbs=create;update,versions=v1alpha1,name=vtofustack-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// TofuStackCustomValidator struct is responsible for validating the TofuStack resource
+// when it is created, updated, or deleted.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+// +kubebuilder:object:generate=false
+type TofuStackCustomValidator struct {
+	// Reader looks up the grants permitting cross-namespace module references. It bypasses the
+	// cache, so that a grant created right before the stack is found.
+	Reader client.Reader
+}
+
+var _ webhook.CustomValidator = &TofuStackCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type TofuStack.
+func (v *TofuStackCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	tofustack, ok := obj.(*opentofuv1alpha1.TofuStack)
+	if !ok {
+		return nil, fmt.Errorf("expected a TofuStack object but got %T", obj)
+	}
+	tofustacklog.Info("Validation for TofuStack upon creation", "name", tofustack.GetName())
+
+	return nil, v.validateTofuStack(ctx, nil, tofustack)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type TofuStack.
+func (v *TofuStackCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	tofustack, ok := newObj.(*opentofuv1alpha1.TofuStack)
+	if !ok {
+		return nil, fmt.Errorf("expected a TofuStack object for the newObj but got %T", newObj)
+	}
+	old, ok := oldObj.(*opentofuv1alpha1.TofuStack)
+	if !ok {
+		return nil, fmt.Errorf("expected a TofuStack object for the oldObj but got %T", oldObj)
+	}
+	tofustacklog.Info("Validation for TofuStack upon update", "name", tofustack.GetName())
+
+	return nil, v.validateTofuStack(ctx, old, tofustack)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type TofuStack.
+func (v *TofuStackCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateTofuStack checks the stack, and that a TofuModuleGrant permits its module reference
+// when it is set or changed.
+func (v *TofuStackCustomValidator) validateTofuStack(ctx context.Context, old, stack *opentofuv1alpha1.TofuStack) error {
+	spec := field.NewPath("spec")
+	errs := validateExecutionSpec(spec.Child("executionTemplate", "spec"), &stack.Spec.ExecutionTemplate.Spec)
+
+	if old == nil || old.Spec.ModuleRef != stack.Spec.ModuleRef {
+		module := modulegrant.ModuleKey(stack.Spec.ModuleRef, stack.Namespace)
+		fieldErr, err := validateModuleGrant(ctx, v.Reader, spec.Child("moduleTemplate"), modulegrant.KindStack, stack.Namespace, module)
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+		if fieldErr != nil {
+			errs = append(errs, fieldErr)
+		}
+	}
+
+	instances := make(map[string]string, len(stack.Spec.Instances))
+	for i, instance := range stack.Spec.Instances {
+		path := spec.Child("instances").Index(i)
+		errs = append(errs, validateValueSources(path.Child("valueSources"), instance.ValueSources)...)
+		if _, found := instances[instance.Name]; found {
+			errs = append(errs, field.Duplicate(path.Child("name"), instance.Name))
+			continue
+		}
+		instances[instance.Name] = path.String()
+	}
+
+	axes := make(map[string]bool, len(stack.Spec.Matrix))
+	for i, axis := range stack.Spec.Matrix {
+		if axes[axis.Name] {
+			errs = append(errs, field.Duplicate(spec.Child("matrix").Index(i).Child("name"), axis.Name))
+		}
+		axes[axis.Name] = true
+	}
+	// Matrix instances are named after their values joined with dashes, like the controller does.
+	// The schema checks each value, but only the joined name can exceed the length of a label.
+	for _, name := range matrixInstanceNames(stack.Spec.Matrix) {
+		if msgs := validation.IsDNS1123Label(name); len(msgs) > 0 {
+			errs = append(errs, field.Invalid(spec.Child("matrix"), name, fmt.Sprintf("generated instance name: %s", msgs[0])))
+			continue
+		}
+		if previous, found := instances[name]; found {
+			errs = append(errs, field.Duplicate(spec.Child("matrix"), fmt.Sprintf("instance %s, also generated by %s", name, previous)))
+			continue
+		}
+		instances[name] = "the matrix"
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(opentofuv1alpha1.GroupVersion.WithKind("TofuStack").GroupKind(), stack.Name, errs)
+}
+
+// matrixInstanceNames returns the names of the instances generated by matrix.
+func matrixInstanceNames(matrix []opentofuv1alpha1.TofuStackMatrixAxis) []string {
+	if len(matrix) == 0 {
+		return nil
+	}
+	names := []string{""}
+	for i, axis := range matrix {
+		next := make([]string, 0, len(names)*len(axis.Values))
+		for _, name := range names {
+			for _, value := range axis.Values {
+				if i > 0 {
+					value = name + "-" + value
+				}
+				next = append(next, value)
+			}
+		}
+		names = next
+	}
+	return names
+}
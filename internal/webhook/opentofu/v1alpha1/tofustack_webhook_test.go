@@ -0,0 +1,104 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+)
+
+var _ = Describe("TofuStack Webhook", func() {
+	var (
+		obj       *opentofuv1alpha1.TofuStack
+		validator TofuStackCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &opentofuv1alpha1.TofuStack{
+			ObjectMeta: metav1.ObjectMeta{Name: "network", Namespace: "default"},
+			Spec: opentofuv1alpha1.TofuStackSpec{
+				ModuleRef: opentofuv1alpha1.ObjectRef{Name: "network", Namespace: "default"},
+				Instances: []opentofuv1alpha1.TofuStackInstance{{Name: "staging"}, {Name: "prod"}},
+				Matrix: []opentofuv1alpha1.TofuStackMatrixAxis{
+					{Name: "region", Values: []string{"eu", "us"}},
+					{Name: "tier", Values: []string{"a", "b"}},
+				},
+			},
+		}
+		scheme := runtime.NewScheme()
+		Expect(opentofuv1alpha1.AddToScheme(scheme)).To(Succeed())
+		grant := &opentofuv1alpha1.TofuModuleGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: "stacks", Namespace: "infra"},
+			Spec: opentofuv1alpha1.TofuModuleGrantSpec{
+				From: []opentofuv1alpha1.TofuModuleGrantFrom{{Kind: "TofuStack", Namespace: "default"}},
+				To:   []opentofuv1alpha1.TofuModuleGrantTo{{Name: "network"}},
+			},
+		}
+		validator = TofuStackCustomValidator{Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(grant).Build()}
+	})
+
+	Context("When creating or updating TofuStack under Validating Webhook", func() {
+		It("Should admit distinct instances", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should only admit cross-namespace module references permitted by a grant", func() {
+			obj.Spec.ModuleRef = opentofuv1alpha1.ObjectRef{Name: "network", Namespace: "infra"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+
+			old := obj.DeepCopy()
+			obj.Spec.ModuleRef.Name = "database"
+			_, err := validator.ValidateUpdate(ctx, old, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.moduleTemplate: Forbidden: no TofuModuleGrant in namespace infra")))
+		})
+
+		It("Should deny duplicate instance and axis names", func() {
+			obj.Spec.Instances = append(obj.Spec.Instances, opentofuv1alpha1.TofuStackInstance{Name: "prod"})
+			obj.Spec.Matrix = append(obj.Spec.Matrix, opentofuv1alpha1.TofuStackMatrixAxis{Name: "region", Values: []string{"x"}})
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(`spec.instances[2].name: Duplicate value: "prod"`)))
+			Expect(err).To(MatchError(ContainSubstring(`spec.matrix[2].name: Duplicate value: "region"`)))
+		})
+
+		It("Should deny matrix instances colliding with explicit instances", func() {
+			obj.Spec.Instances = append(obj.Spec.Instances, opentofuv1alpha1.TofuStackInstance{Name: "eu-a"})
+			_, err := validator.ValidateUpdate(ctx, obj.DeepCopy(), obj)
+			Expect(err).To(MatchError(ContainSubstring("instance eu-a, also generated by spec.instances[2]")))
+		})
+
+		It("Should deny matrix instance names longer than a label", func() {
+			obj.Spec.Matrix[1].Values = append(obj.Spec.Matrix[1].Values, strings.Repeat("c", 62))
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.matrix: Invalid value: \"eu-" + strings.Repeat("c", 62))))
+			Expect(err).To(MatchError(ContainSubstring("must be no more than 63 characters")))
+		})
+
+		It("Should deny invalid instance value sources", func() {
+			obj.Spec.Instances[0].ValueSources = map[string]opentofuv1alpha1.ValueFrom{"token": {}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.instances[0].valueSources[token]")))
+		})
+	})
+})
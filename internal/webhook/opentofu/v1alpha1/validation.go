@@ -0,0 +1,105 @@
+/*
+Copyright 2025 Othmane El Warrak.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	opentofuv1alpha1 "github.com/soyplane-io/soyplane/api/opentofu/v1alpha1"
+	"github.com/soyplane-io/soyplane/internal/modulegrant"
+)
+
+// sourceSchemes are the URL schemes git can clone modules from.
+var sourceSchemes = []string{"https", "http", "ssh", "git", "file"}
+
+// scpLikeSource matches the scp-like syntax of ssh remotes, e.g. git@github.com:org/repo.git.
+var scpLikeSource = regexp.MustCompile(`^[A-Za-z0-9._-]+@[A-Za-z0-9.-]+:[^:]+$`)
+
+// validateSource checks that source is a git remote. Executions pass it to git clone in a shell
+// script, so whitespace and shell metacharacters are refused as well.
+func validateSource(path *field.Path, source string) *field.Error {
+	if source == "" {
+		return field.Required(path, "")
+	}
+	if strings.ContainsAny(source, " \t\r\n;&|`$()<>'\"\\") {
+		return field.Invalid(path, source, "must not contain whitespace or shell metacharacters")
+	}
+	if scpLikeSource.MatchString(source) {
+		return nil
+	}
+	u, err := url.Parse(source)
+	if err != nil {
+		return field.Invalid(path, source, err.Error())
+	}
+	if !slices.Contains(sourceSchemes, u.Scheme) {
+		return field.Invalid(path, source, fmt.Sprintf("must be a git URL with one of the schemes %s, or user@host:path", strings.Join(sourceSchemes, ", ")))
+	}
+	if (u.Host == "" && u.Scheme != "file") || strings.Trim(u.Path, "/") == "" {
+		return field.Invalid(path, source, "must name a host and a repository")
+	}
+	return nil
+}
+
+// validateValueSource checks that a value source references exactly one of a Secret or a ConfigMap.
+func validateValueSource(path *field.Path, secret, configMap bool) *field.Error {
+	switch {
+	case secret && configMap:
+		return field.Invalid(path, "secretRef, configMapRef", "must reference either a Secret or a ConfigMap, not both")
+	case !secret && !configMap:
+		return field.Required(path, "must reference a Secret or a ConfigMap")
+	}
+	return nil
+}
+
+// validateValueSources checks every value source of a module, execution or stack instance.
+func validateValueSources(path *field.Path, sources map[string]opentofuv1alpha1.ValueFrom) field.ErrorList {
+	var errs field.ErrorList
+	for _, name := range slices.Sorted(maps.Keys(sources)) {
+		source := sources[name]
+		if err := validateValueSource(path.Key(name), source.SecretRef != nil, source.ConfigMapRef != nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// validateExecutionSpec checks the parts of an execution spec that do not depend on other objects,
+// so that module and stack execution templates are checked like executions.
+func validateExecutionSpec(path *field.Path, spec *opentofuv1alpha1.TofuExecutionSpec) field.ErrorList {
+	return validateValueSources(path.Child("valueSources"), spec.ValueSources)
+}
+
+// validateModuleGrant reports a field error when no TofuModuleGrant permits objects of kind in
+// namespace to reference module, and a lookup error when that could not be determined.
+func validateModuleGrant(ctx context.Context, reader client.Reader, path *field.Path, kind, namespace string, module types.NamespacedName) (*field.Error, error) {
+	granted, err := modulegrant.Permitted(ctx, reader, kind, namespace, module)
+	if err != nil || granted {
+		return nil, err
+	}
+	return field.Forbidden(path, fmt.Sprintf("no TofuModuleGrant in namespace %s permits %s objects of namespace %s to reference module %s",
+		module.Namespace, kind, namespace, module.Name)), nil
+}